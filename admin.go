@@ -0,0 +1,70 @@
+package main
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// runCreateAdmin creates an admin account, which /register does not allow
+// safely. Without --password a random one is generated and printed once.
+func runCreateAdmin(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin (required)")
+	name := flags.String("name", "Admin", "display name")
+	password := flags.String("password", "", "password; generated when empty")
+	super := flags.Bool("super", false, "create a super admin for every branch")
+	clinicID := flags.Uint("clinic", 0, "clinic of the admin (required unless --super)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("--email is required")
+	}
+	if *super && *clinicID != 0 {
+		return errors.New("a super admin cannot be limited to a clinic")
+	}
+	if !*super && *clinicID == 0 {
+		return errors.New("an admin needs --clinic, or --super for every clinic")
+	}
+
+	generated := *password == ""
+	if generated {
+		randomPassword, err := utils.RandomPassword()
+		if err != nil {
+			return err
+		}
+		*password = randomPassword
+	}
+
+	role := "admin"
+	if *super {
+		role = "superadmin"
+	}
+
+	commands, err := connectForCommand(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	user, err := commands.User.RegisterUser(ctx, &model.User{Name: *name, Email: *email, Password: *password, Role: role})
+	if err != nil {
+		return err
+	}
+	if *clinicID != 0 {
+		if err := commands.Clinic.AssignAdmin(ctx, uint(*clinicID), user.ID); err != nil {
+			return fmt.Errorf("admin %d created but not assigned to clinic: %w", user.ID, err)
+		}
+	}
+
+	fmt.Printf("Created %s %s (id %d)\n", role, user.Email, user.ID)
+	if generated {
+		fmt.Println("Password:", *password)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/services"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// commandServices are the services the admin commands work through, wired
+// like the router wires them, so commands follow the same rules as the API.
+type commandServices struct {
+	User     services.UserService
+	Clinic   services.ClinicService
+	Doctor   services.DoctorServices
+	Service  services.ServiceService
+	Importer services.ImportService
+}
+
+// connectForCommand connects to a database at the schema version of this build.
+func connectForCommand(cfg *config.Config) (*commandServices, error) {
+	db, err := config.ConnectDB(cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.CheckSchemaVersion(db); err != nil {
+		return nil, err
+	}
+	return newCommandServices(db, cfg), nil
+}
+
+func newCommandServices(db *gorm.DB, cfg *config.Config) *commandServices {
+	userRepository := &repository.UserRepositoryImpl{DB: db}
+	serviceRepository := &repository.ServiceRepositoryImpl{DB: db}
+	doctorScheduleRepository := &repository.DoctorScheduleRepositoryImpl{DB: db}
+	doctorRepository := &repository.DoctorRepositoryImpl{DB: db}
+	resourceRepository := &repository.ResourceRepositoryImpl{DB: db}
+	clinicRepository := &repository.ClinicRepositoryImpl{DB: db}
+	servicePriceRepository := &repository.ServicePriceRepositoryImpl{DB: db}
+
+	userService := &services.UserServicesImpl{UserRepository: userRepository, JWTSecret: cfg.JWT.Secret, JWTExpiresIn: cfg.JWT.ExpiresIn()}
+	clinicService := &services.ClinicServiceImpl{ClinicRepository: clinicRepository, DoctorRepository: doctorRepository, UserRepository: userRepository}
+	doctorService := &services.DoctorServicesImpl{
+		DoctorRepository:         doctorRepository,
+		UserRepository:           userRepository,
+		ServiceRepository:        serviceRepository,
+		DoctorScheduleRepository: doctorScheduleRepository,
+	}
+	doctorScheduleService := &services.DoctorScheduleServiceImpl{DoctorScheduleRepository: doctorScheduleRepository, DoctorRepository: doctorRepository, ServiceRepository: serviceRepository, ResourceRepository: resourceRepository, ClinicRepository: clinicRepository}
+	servicePriceService := &services.ServicePriceServiceImpl{ServicePriceRepository: servicePriceRepository, ServiceRepository: serviceRepository, DoctorRepository: doctorRepository}
+	serviceService := &services.ServiceServiceImpl{ServiceRepository: serviceRepository, ServicePriceService: servicePriceService, DoctorScheduleRepository: doctorScheduleRepository}
+	importService := &services.ImportServiceImpl{
+		UserService:           userService,
+		UserRepository:        userRepository,
+		DoctorService:         doctorService,
+		DoctorRepository:      doctorRepository,
+		DoctorScheduleService: doctorScheduleService,
+		ServiceRepository:     serviceRepository,
+		ClinicService:         clinicService,
+	}
+
+	return &commandServices{User: userService, Clinic: clinicService, Doctor: doctorService, Service: serviceService, Importer: importService}
+}
+
+// printImportReport prints the outcome of every row and returns an error when
+// any row was rejected, so scripts can tell.
+func printImportReport(report *model.ImportReport) error {
+	for _, row := range report.Accepted {
+		line := fmt.Sprintf("row %d: accepted, id %d", row.Row, row.ID)
+		if row.Note != "" {
+			line += " (" + row.Note + ")"
+		}
+		fmt.Println(line)
+	}
+	for _, row := range report.Rejected {
+		fmt.Printf("row %d: rejected: %s\n", row.Row, row.Error)
+	}
+	fmt.Printf("%d accepted, %d rejected\n", len(report.Accepted), len(report.Rejected))
+
+	if len(report.Rejected) > 0 {
+		return fmt.Errorf("%d rows rejected", len(report.Rejected))
+	}
+	return nil
+}
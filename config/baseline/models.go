@@ -0,0 +1,355 @@
+// Package baseline holds frozen copies of the models as they were at
+// migration 0001_baseline. Databases created by gorm AutoMigrate are upgraded
+// to these, not to the current models, so that migrations after the baseline
+// still find the schema they were written against. The type and field names
+// are kept because gorm derives constraint and join column names from them.
+// Never change them; write a migration.
+package baseline
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	gorm.Model
+	Name      string
+	Email     string `gorm:"unique;not null"`
+	Password  string `gorm:"not null"`
+	Role      string `gorm:"not null,default:'patient'"`
+	ClinicId  *uint  `gorm:"index"`
+	CreatedBy uint   `gorm:"not null"`
+	UpdatedBy uint
+	Booking   []Booking `gorm:"foreignKey:UserId"`
+}
+
+type Doctor struct {
+	gorm.Model
+	UserId         uint   `gorm:"not null;uniqueIndex:idx_user_id"`
+	Specialization string `gorm:"not null"`
+	Bio            string `gorm:"type:text"`
+	PhotoURL       string
+	Languages      string
+	Education      string `gorm:"type:text"`
+	PracticeSince  int
+	STRNumber      string
+	STRExpiresAt   *time.Time
+	SIPNumber      string     `gorm:"column:sip_number"`
+	SIPExpiresAt   *time.Time `gorm:"column:sip_expires_at"`
+	CreatedBy      uint       `gorm:"not null"`
+	UpdatedBy      uint
+	User           User             `gorm:"foreignKey:UserId;references:ID"`
+	Bookings       []Booking        `gorm:"foreignKey:DoctorId;references:ID"`
+	Schedules      []DoctorSchedule `gorm:"foreignKey:DoctorId;references:ID"`
+	Services       []Service        `gorm:"many2many:doctor_services"`
+	Clinics        []Clinic         `gorm:"many2many:doctor_clinics"`
+}
+
+type Clinic struct {
+	gorm.Model
+	Name         string `gorm:"not null;uniqueIndex;size:150"`
+	Address      string `gorm:"type:text"`
+	Phone        string
+	Timezone     string `gorm:"not null;default:Asia/Jakarta"`
+	IsActive     bool   `gorm:"not null;default:true"`
+	CreatedBy    uint   `gorm:"not null"`
+	UpdatedBy    uint
+	OpeningHours []ClinicOpeningHour `gorm:"foreignKey:ClinicId;references:ID"`
+	Doctors      []Doctor            `gorm:"many2many:doctor_clinics"`
+}
+
+type ClinicOpeningHour struct {
+	ID       uint   `gorm:"primarykey"`
+	ClinicId uint   `gorm:"not null;index"`
+	Weekday  int    `gorm:"not null"`
+	OpensAt  string `gorm:"size:5;not null"`
+	ClosesAt string `gorm:"size:5;not null"`
+}
+
+type Service struct {
+	gorm.Model
+	Name            string `gorm:"unique;not null"`
+	Description     string `gorm:"type:text"`
+	Price           int    `gorm:"not null"`
+	DurationMinutes int    `gorm:"not null"`
+	IsActive        bool   `gorm:"not null"`
+	CreatedBy       uint   `gorm:"not null"`
+	UpdatedBy       uint
+	Bookings        []Booking        `gorm:"foreignKey:ServiceId;references:ID"`
+	Schedules       []DoctorSchedule `gorm:"foreignKey:ServiceId;references:ID"`
+	Doctors         []Doctor         `gorm:"many2many:doctor_services"`
+	Resources       []Resource       `gorm:"many2many:service_resources"`
+	ClinicId        *uint            `gorm:"index"`
+}
+
+type Booking struct {
+	gorm.Model
+	UserId         uint      `gorm:"not null"`
+	DoctorId       uint      `gorm:"not null;index:idx_booking_doctor_interval,priority:1"`
+	ServiceId      uint      `gorm:"not null"`
+	ClinicId       uint      `gorm:"index"`
+	StartAt        time.Time `gorm:"not null;index:idx_booking_doctor_interval,priority:2"`
+	EndAt          time.Time `gorm:"not null;index:idx_booking_doctor_interval,priority:3"`
+	Status         string    `gorm:"not null;default:pending"`
+	Notes          string    `gorm:"type:text"`
+	PayerType      string    `gorm:"not null;default:self_pay"`
+	InsurerName    string
+	PolicyNumber   string
+	Price          int `gorm:"not null;default:0"`
+	DiscountAmount int `gorm:"not null;default:0"`
+	FinalPrice     int `gorm:"not null;default:0"`
+	PromoCode      string
+	CreatedBy      uint `gorm:"not null"`
+	UpdatedBy      uint
+	User           User       `gorm:"foreignKey:UserId;references:ID"`
+	Doctor         Doctor     `gorm:"foreignKey:DoctorId;references:ID"`
+	Service        Service    `gorm:"foreignKey:ServiceId;references:ID"`
+	Resources      []Resource `gorm:"many2many:booking_resources"`
+}
+
+type Resource struct {
+	gorm.Model
+	Name        string `gorm:"not null"`
+	Type        string `gorm:"not null;index"`
+	Description string `gorm:"type:text"`
+	IsActive    bool   `gorm:"not null;default:true"`
+	ClinicId    uint   `gorm:"index"`
+	CreatedBy   uint   `gorm:"not null"`
+	UpdatedBy   uint
+	Services    []Service `gorm:"many2many:service_resources"`
+}
+
+type DoctorSchedule struct {
+	gorm.Model
+	DoctorId  uint      `gorm:"not null"`
+	ServiceId uint      `gorm:"not null;index:idx_schedule_service_date"`
+	Date      time.Time `gorm:"not null;index:idx_schedule_service_date"`
+	StartTime time.Time `gorm:"not null"`
+	EndTime   time.Time `gorm:"not null"`
+	RoomId    *uint     `gorm:"index"`
+	ClinicId  uint      `gorm:"index"`
+	CreatedBy uint      `gorm:"not null"`
+	UpdatedBy uint
+	Doctor    Doctor    `gorm:"foreignKey:DoctorId;references:ID"`
+	Service   Service   `gorm:"foreignKey:ServiceId;references:ID"`
+	Room      *Resource `gorm:"foreignKey:RoomId;references:ID"`
+}
+
+type VitalSign struct {
+	gorm.Model
+	BookingId   uint    `gorm:"not null;index"`
+	UserId      uint    `gorm:"not null;index"`
+	Systolic    int     `gorm:"not null"`
+	Diastolic   int     `gorm:"not null"`
+	Temperature float64 `gorm:"not null"`
+	WeightKg    float64 `gorm:"not null"`
+	HeightCm    float64 `gorm:"not null"`
+	Pulse       int     `gorm:"not null"`
+	SpO2        int     `gorm:"not null"`
+	BMI         float64 `gorm:"not null"`
+	Flags       string
+	RecordedAt  time.Time `gorm:"not null"`
+	CreatedBy   uint      `gorm:"not null"`
+	UpdatedBy   uint
+	Booking     Booking `gorm:"foreignKey:BookingId;references:ID"`
+	User        User    `gorm:"foreignKey:UserId;references:ID"`
+}
+
+type Attachment struct {
+	gorm.Model
+	BookingId   uint   `gorm:"not null;index"`
+	FileName    string `gorm:"not null"`
+	ContentType string `gorm:"not null"`
+	Size        int64  `gorm:"not null"`
+	StorageKey  string `gorm:"not null;unique"`
+	Category    string `gorm:"not null;default:other"`
+	CreatedBy   uint   `gorm:"not null"`
+	UpdatedBy   uint
+	Booking     Booking `gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type Invoice struct {
+	gorm.Model
+	InvoiceNumber  string    `gorm:"not null;unique"`
+	BookingId      uint      `gorm:"not null;uniqueIndex"`
+	UserId         uint      `gorm:"not null;index"`
+	Subtotal       int       `gorm:"not null"`
+	DiscountAmount int       `gorm:"not null;default:0"`
+	TaxPercent     float64   `gorm:"not null;default:0"`
+	TaxAmount      int       `gorm:"not null;default:0"`
+	Total          int       `gorm:"not null"`
+	Status         string    `gorm:"not null;default:unpaid;index"`
+	IssuedAt       time.Time `gorm:"not null"`
+	PaidAt         *time.Time
+	CreatedBy      uint `gorm:"not null"`
+	UpdatedBy      uint
+	Items          []InvoiceItem `gorm:"foreignKey:InvoiceId;references:ID"`
+	Booking        Booking       `gorm:"foreignKey:BookingId;references:ID"`
+	User           User          `gorm:"foreignKey:UserId;references:ID"`
+}
+
+type InvoiceItem struct {
+	gorm.Model
+	InvoiceId   uint   `gorm:"not null;index"`
+	ItemType    string `gorm:"not null"`
+	Description string `gorm:"not null"`
+	Quantity    int    `gorm:"not null"`
+	UnitPrice   int    `gorm:"not null"`
+	Amount      int    `gorm:"not null"`
+	CreatedBy   uint   `gorm:"not null"`
+}
+
+type InvoiceSequence struct {
+	Period     string `gorm:"primaryKey;size:6"`
+	LastNumber int    `gorm:"not null"`
+}
+
+type Payment struct {
+	gorm.Model
+	BookingId   uint   `gorm:"not null;index"`
+	UserId      uint   `gorm:"not null;index"`
+	Amount      int    `gorm:"not null"`
+	Method      string `gorm:"not null"`
+	Provider    string `gorm:"not null;uniqueIndex:idx_provider_ref;size:50"`
+	ProviderRef string `gorm:"not null;uniqueIndex:idx_provider_ref;size:100"`
+	Reference   string
+	Status      string `gorm:"not null;default:pending"`
+	VANumber    string
+	RedirectURL string
+	ExpiresAt   *time.Time
+	PaidAt      *time.Time
+	CreatedBy   uint `gorm:"not null"`
+	UpdatedBy   uint
+	Booking     Booking `gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type Refund struct {
+	gorm.Model
+	PaymentId   uint `gorm:"not null;index"`
+	BookingId   uint `gorm:"not null;index"`
+	Amount      int  `gorm:"not null"`
+	Percent     int  `gorm:"not null"`
+	Reason      string
+	Status      string `gorm:"not null;default:pending_approval;index"`
+	RefundRef   string
+	ApprovedBy  uint
+	ProcessedAt *time.Time
+	CreatedBy   uint `gorm:"not null"`
+	UpdatedBy   uint
+	Payment     Payment `gorm:"foreignKey:PaymentId;references:ID"`
+}
+
+type ServiceCoverage struct {
+	gorm.Model
+	ServiceId       uint   `gorm:"not null;index"`
+	PayerType       string `gorm:"not null"`
+	InsurerName     string
+	CoveragePercent int  `gorm:"not null"`
+	MaxAmount       int  `gorm:"not null;default:0"`
+	CreatedBy       uint `gorm:"not null"`
+	UpdatedBy       uint
+	Service         Service `gorm:"foreignKey:ServiceId;references:ID"`
+}
+
+type InsuranceClaim struct {
+	gorm.Model
+	BookingId       uint   `gorm:"not null;uniqueIndex"`
+	UserId          uint   `gorm:"not null;index"`
+	ServiceId       uint   `gorm:"not null"`
+	PayerType       string `gorm:"not null;index"`
+	InsurerName     string
+	PolicyNumber    string `gorm:"not null"`
+	BilledAmount    int    `gorm:"not null"`
+	CoveredAmount   int    `gorm:"not null"`
+	Status          string `gorm:"not null;default:draft;index"`
+	BatchId         *uint  `gorm:"index"`
+	RejectionReason string
+	SubmittedAt     *time.Time
+	DecidedAt       *time.Time
+	CreatedBy       uint `gorm:"not null"`
+	UpdatedBy       uint
+	Booking         Booking `gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type ClaimBatch struct {
+	gorm.Model
+	BatchNumber string `gorm:"not null;unique"`
+	PayerType   string `gorm:"not null"`
+	InsurerName string
+	ClaimCount  int              `gorm:"not null"`
+	TotalAmount int              `gorm:"not null"`
+	CreatedBy   uint             `gorm:"not null"`
+	Claims      []InsuranceClaim `gorm:"foreignKey:BatchId;references:ID"`
+}
+
+type Promo struct {
+	gorm.Model
+	Code              string    `gorm:"not null;unique;size:50"`
+	Description       string    `gorm:"type:text"`
+	DiscountType      string    `gorm:"not null"`
+	DiscountValue     int       `gorm:"not null"`
+	ValidFrom         time.Time `gorm:"not null"`
+	ValidUntil        time.Time `gorm:"not null"`
+	ServiceId         *uint
+	DoctorId          *uint
+	MaxUses           int  `gorm:"not null;default:0"`
+	MaxUsesPerPatient int  `gorm:"not null;default:0"`
+	FirstVisitOnly    bool `gorm:"not null;default:false"`
+	IsActive          bool `gorm:"not null"`
+	CreatedBy         uint `gorm:"not null"`
+	UpdatedBy         uint
+}
+
+type PromoUsage struct {
+	gorm.Model
+	PromoId        uint    `gorm:"not null;index"`
+	BookingId      uint    `gorm:"not null;uniqueIndex"`
+	UserId         uint    `gorm:"not null;index"`
+	DiscountAmount int     `gorm:"not null"`
+	Promo          Promo   `gorm:"foreignKey:PromoId;references:ID"`
+	Booking        Booking `gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type ServicePrice struct {
+	gorm.Model
+	ServiceId     uint      `gorm:"not null;index:idx_service_price_lookup"`
+	DoctorId      *uint     `gorm:"index:idx_service_price_lookup"`
+	Price         int       `gorm:"not null"`
+	EffectiveFrom time.Time `gorm:"not null;index:idx_service_price_lookup"`
+	CreatedBy     uint      `gorm:"not null"`
+	UpdatedBy     uint
+	Service       Service `gorm:"foreignKey:ServiceId;references:ID"`
+}
+
+type LicenseAlert struct {
+	gorm.Model
+	DoctorId  uint      `gorm:"not null;uniqueIndex:idx_license_alert"`
+	License   string    `gorm:"size:3;not null;uniqueIndex:idx_license_alert"`
+	Kind      string    `gorm:"size:10;not null;uniqueIndex:idx_license_alert"`
+	ExpiresAt time.Time `gorm:"not null;uniqueIndex:idx_license_alert"`
+	Doctor    Doctor    `gorm:"foreignKey:DoctorId;references:ID"`
+}
+
+type Review struct {
+	gorm.Model
+	BookingId    uint   `gorm:"not null;uniqueIndex"`
+	DoctorId     uint   `gorm:"not null;index"`
+	UserId       uint   `gorm:"not null"`
+	Rating       int    `gorm:"not null"`
+	Comment      string `gorm:"type:text"`
+	IsHidden     bool   `gorm:"not null;default:false"`
+	HiddenReason string
+	HiddenBy     uint
+	Reply        string `gorm:"type:text"`
+	RepliedAt    *time.Time
+	CreatedBy    uint `gorm:"not null"`
+	UpdatedBy    uint
+	User         User    `gorm:"foreignKey:UserId;references:ID"`
+	Doctor       Doctor  `gorm:"foreignKey:DoctorId;references:ID"`
+	Booking      Booking `gorm:"foreignKey:BookingId;references:ID"`
+}
+
+// Tables are the tables of migration 0001_baseline, in the order
+// AutoMigrate created them.
+var Tables = []interface{}{&User{}, &Doctor{}, &Booking{}, &Service{}, &DoctorSchedule{}, &VitalSign{}, &Attachment{}, &Invoice{}, &InvoiceItem{}, &InvoiceSequence{}, &Payment{}, &Refund{}, &ServiceCoverage{}, &InsuranceClaim{}, &ClaimBatch{}, &Promo{}, &PromoUsage{}, &ServicePrice{}, &LicenseAlert{}, &Review{}, &Resource{}, &Clinic{}, &ClinicOpeningHour{}}
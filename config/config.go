@@ -0,0 +1,354 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is every setting the application reads, loaded once at startup.
+// Values come from the defaults below, then the YAML file named by
+// CONFIG_FILE (config.yaml when it exists), then .env, then the environment;
+// each source overrides the one before it. The env tag names the variable of
+// a setting.
+type Config struct {
+	Server      ServerConfig     `yaml:"server"`
+	Database    DatabaseConfig   `yaml:"database"`
+	JWT         JWTConfig        `yaml:"jwt"`
+	Attachments AttachmentConfig `yaml:"attachments"`
+	Payment     PaymentConfig    `yaml:"payment"`
+	Rules       RulesConfig      `yaml:"rules"`
+	Log         LogConfig        `yaml:"log"`
+	Tracing     TracingConfig    `yaml:"tracing"`
+}
+
+type ServerConfig struct {
+	Port        int    `yaml:"port" env:"PORT"`
+	TLSCertFile string `yaml:"tls_cert_file" env:"TLS_CERT_FILE"`
+	TLSKeyFile  string `yaml:"tls_key_file" env:"TLS_KEY_FILE"`
+	// CORSAllowedOrigins are the browser origins allowed to call the API; *
+	// allows any. Empty disables CORS.
+	CORSAllowedOrigins []string      `yaml:"cors_allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	ReadTimeout        time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout       time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout        time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	// ShutdownTimeout is how long in-flight requests may take to finish once
+	// the server is asked to stop.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+	// MetricsAddr is the address /metrics is served on, apart from the API so
+	// it can be kept off the public network; empty turns it off.
+	MetricsAddr string `yaml:"metrics_addr" env:"METRICS_ADDR"`
+}
+
+// DatabaseConfig selects the database driver: mysql, postgres or sqlite. For
+// sqlite, Name is the path of the database file, or :memory: for a database
+// that lives as long as the process.
+type DatabaseConfig struct {
+	Driver          string        `yaml:"driver" env:"DB_DRIVER"`
+	Host            string        `yaml:"host" env:"DB_HOST"`
+	Port            string        `yaml:"port" env:"DB_PORT"`
+	User            string        `yaml:"user" env:"DB_USER"`
+	Password        string        `yaml:"password" env:"DB_PASS"`
+	Name            string        `yaml:"name" env:"DB_NAME"`
+	SSLMode         string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	// SlowQueryThreshold is how long a query may take before it is logged as
+	// slow; 0 turns this off.
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+}
+
+type JWTConfig struct {
+	Secret         string `yaml:"secret" env:"JWT_SECRET_KEY"`
+	ExpiresInHours int    `yaml:"expires_in_hours" env:"JWT_EXPIRES_IN"`
+}
+
+// ExpiresIn is how long a token issued at login stays valid.
+func (c JWTConfig) ExpiresIn() time.Duration {
+	return time.Duration(c.ExpiresInHours) * time.Hour
+}
+
+type AttachmentConfig struct {
+	Dir string `yaml:"dir" env:"ATTACHMENT_DIR"`
+}
+
+type PaymentConfig struct {
+	// Provider is the gateway online payments go through; only mock for now.
+	Provider      string `yaml:"provider" env:"PAYMENT_PROVIDER"`
+	WebhookSecret string `yaml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET"`
+}
+
+type LogConfig struct {
+	// Level is debug, info, warn or error.
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+	// Format is json, or text for reading logs in a terminal.
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+// SlogLevel is the parsed Level; the configuration has been validated.
+func (c LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Level))
+	return level
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout for printing spans while developing, or otlp.
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER"`
+	// OTLPEndpoint is the URL of the OTLP/HTTP collector, such as
+	// http://localhost:4318; empty leaves it to the OTEL_EXPORTER_OTLP_*
+	// variables.
+	OTLPEndpoint string  `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	ServiceName  string  `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+	SampleRatio  float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// RulesConfig holds the business rules clinics tune.
+type RulesConfig struct {
+	InvoiceTaxPercent float64 `yaml:"invoice_tax_percent" env:"INVOICE_TAX_PERCENT"`
+	// RefundPolicy is "hours:percent" pairs, see ParseRefundPolicy.
+	RefundPolicy string `yaml:"refund_policy" env:"REFUND_POLICY"`
+	// RefundRules is RefundPolicy as parsed when the configuration is loaded.
+	RefundRules []RefundRule `yaml:"-"`
+	// CancellationCutoffHours is how close to the appointment a patient may
+	// still cancel it; 0 allows cancelling until it starts.
+	CancellationCutoffHours int `yaml:"cancellation_cutoff_hours" env:"CANCELLATION_CUTOFF_HOURS"`
+	ReviewWindowDays        int `yaml:"review_window_days" env:"REVIEW_WINDOW_DAYS"`
+	AvailabilityHorizonDays int `yaml:"availability_horizon_days" env:"AVAILABILITY_HORIZON_DAYS"`
+	LicenseAlertDays        int `yaml:"license_alert_days" env:"LICENSE_ALERT_DAYS"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     30 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+			MetricsAddr:     "127.0.0.1:9090",
+		},
+		Database: DatabaseConfig{
+			Driver:             "mysql",
+			SSLMode:            "disable",
+			MaxOpenConns:       25,
+			MaxIdleConns:       5,
+			ConnMaxLifetime:    30 * time.Minute,
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		JWT:         JWTConfig{ExpiresInHours: 1},
+		Attachments: AttachmentConfig{Dir: "uploads"},
+		Payment:     PaymentConfig{Provider: "mock"},
+		Rules: RulesConfig{
+			RefundPolicy:            "24:100,0:50",
+			ReviewWindowDays:        14,
+			AvailabilityHorizonDays: 30,
+			LicenseAlertDays:        30,
+		},
+		Log:     LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{Exporter: "none", ServiceName: "bookingklinik", SampleRatio: 1},
+	}
+}
+
+// Load reads and validates the configuration. The error lists every problem
+// found, not just the first.
+func Load() (*Config, error) {
+	cfg := defaultConfig()
+
+	path, required := os.Getenv("CONFIG_FILE"), true
+	if path == "" {
+		path, required = "config.yaml", false
+	}
+	if err := cfg.loadFile(path, required); err != nil {
+		return nil, err
+	}
+
+	// Variables already set in the environment win over .env
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading .env: %w", err)
+	}
+
+	problems := applyEnv(reflect.ValueOf(cfg).Elem())
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return cfg, nil
+}
+
+func (cfg *Config) loadFile(path string, required bool) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	// A misspelt key would otherwise be silently ignored
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("reading config file %s: %w", path, err)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv overrides every field with an env tag whose variable is set and
+// not empty.
+func applyEnv(v reflect.Value) []string {
+	var problems []string
+	for i := 0; i < v.NumField(); i++ {
+		field, fieldType := v.Field(i), v.Type().Field(i)
+		if field.Kind() == reflect.Struct {
+			problems = append(problems, applyEnv(field)...)
+			continue
+		}
+		name := fieldType.Tag.Get("env")
+		value := strings.TrimSpace(os.Getenv(name))
+		if name == "" || value == "" {
+			continue
+		}
+
+		switch {
+		case field.Type() == durationType:
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				problems = append(problems, name+" must be a duration such as 30m or 1h")
+				continue
+			}
+			field.SetInt(int64(d))
+		case field.Kind() == reflect.String:
+			field.SetString(value)
+		case field.Kind() == reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, name+" must be a whole number")
+				continue
+			}
+			field.SetInt(int64(n))
+		case field.Kind() == reflect.Float64:
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				problems = append(problems, name+" must be a number")
+				continue
+			}
+			field.SetFloat(f)
+		case field.Kind() == reflect.Slice:
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			field.Set(reflect.ValueOf(items))
+		}
+	}
+	return problems
+}
+
+func (cfg *Config) validate() []string {
+	var problems []string
+	required := func(name, value string) {
+		if strings.TrimSpace(value) == "" {
+			problems = append(problems, name+" is required")
+		}
+	}
+
+	if cfg.Server.Port < 1 || cfg.Server.Port > 65535 {
+		problems = append(problems, "PORT must be between 1 and 65535")
+	}
+	if (cfg.Server.TLSCertFile == "") != (cfg.Server.TLSKeyFile == "") {
+		problems = append(problems, "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.Server.ReadTimeout <= 0 || cfg.Server.WriteTimeout <= 0 || cfg.Server.IdleTimeout <= 0 || cfg.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "SERVER_READ_TIMEOUT, SERVER_WRITE_TIMEOUT, SERVER_IDLE_TIMEOUT and SERVER_SHUTDOWN_TIMEOUT must be positive")
+	}
+	if cfg.Server.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(cfg.Server.MetricsAddr); err != nil {
+			problems = append(problems, "METRICS_ADDR must be host:port, such as 127.0.0.1:9090")
+		}
+	}
+
+	switch cfg.Database.Driver {
+	case "mysql", "postgres":
+		required("DB_HOST", cfg.Database.Host)
+		required("DB_PORT", cfg.Database.Port)
+		required("DB_USER", cfg.Database.User)
+	case "sqlite":
+	default:
+		problems = append(problems, fmt.Sprintf("DB_DRIVER must be mysql, postgres or sqlite, not %q", cfg.Database.Driver))
+	}
+	required("DB_NAME", cfg.Database.Name)
+	if cfg.Database.MaxOpenConns < 0 || cfg.Database.MaxIdleConns < 0 || cfg.Database.ConnMaxLifetime < 0 || cfg.Database.SlowQueryThreshold < 0 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME and DB_SLOW_QUERY_THRESHOLD cannot be negative")
+	}
+
+	required("JWT_SECRET_KEY", cfg.JWT.Secret)
+	if cfg.JWT.ExpiresInHours <= 0 {
+		problems = append(problems, "JWT_EXPIRES_IN must be a positive number of hours")
+	}
+
+	required("ATTACHMENT_DIR", cfg.Attachments.Dir)
+
+	if cfg.Payment.Provider != "mock" {
+		problems = append(problems, fmt.Sprintf("PAYMENT_PROVIDER must be mock, not %q", cfg.Payment.Provider))
+	}
+	required("PAYMENT_WEBHOOK_SECRET", cfg.Payment.WebhookSecret)
+
+	if cfg.Rules.InvoiceTaxPercent < 0 || cfg.Rules.InvoiceTaxPercent > 100 {
+		problems = append(problems, "INVOICE_TAX_PERCENT must be between 0 and 100")
+	}
+	required("REFUND_POLICY", cfg.Rules.RefundPolicy)
+	if rules, err := ParseRefundPolicy(cfg.Rules.RefundPolicy); err != nil {
+		problems = append(problems, fmt.Sprintf("REFUND_POLICY must be hours:percent pairs such as 24:100,0:50 (%v)", err))
+	} else {
+		cfg.Rules.RefundRules = rules
+	}
+	if cfg.Rules.CancellationCutoffHours < 0 {
+		problems = append(problems, "CANCELLATION_CUTOFF_HOURS cannot be negative")
+	}
+	if cfg.Rules.ReviewWindowDays <= 0 {
+		problems = append(problems, "REVIEW_WINDOW_DAYS must be a positive number of days")
+	}
+	if cfg.Rules.AvailabilityHorizonDays <= 0 {
+		problems = append(problems, "AVAILABILITY_HORIZON_DAYS must be a positive number of days")
+	}
+	if cfg.Rules.LicenseAlertDays <= 0 {
+		problems = append(problems, "LICENSE_ALERT_DAYS must be a positive number of days")
+	}
+
+	switch strings.ToLower(cfg.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be debug, info, warn or error, not %q", cfg.Log.Level))
+	}
+	if cfg.Log.Format != "json" && cfg.Log.Format != "text" {
+		problems = append(problems, fmt.Sprintf("LOG_FORMAT must be json or text, not %q", cfg.Log.Format))
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be none, stdout or otlp, not %q", cfg.Tracing.Exporter))
+	}
+	required("OTEL_SERVICE_NAME", cfg.Tracing.ServiceName)
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	return problems
+}
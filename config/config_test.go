@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv blanks every variable Load reads, so the host environment does not
+// leak into the test. Blank variables are treated as unset.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	var walk func(reflect.Type)
+	walk = func(typ reflect.Type) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				walk(field.Type)
+				continue
+			}
+			if name := field.Tag.Get("env"); name != "" {
+				t.Setenv(name, "")
+			}
+		}
+	}
+	walk(reflect.TypeOf(Config{}))
+}
+
+// writeConfig writes a config file for Load to read through CONFIG_FILE.
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+}
+
+const minimalConfig = `
+database:
+  driver: sqlite
+  name: clinic.db
+jwt:
+  secret: secret
+payment:
+  webhook_secret: webhook
+`
+
+func TestLoadLayersEnvOverFile(t *testing.T) {
+	clearEnv(t)
+	writeConfig(t, minimalConfig+`
+server:
+  port: 8000
+  read_timeout: 10s
+log:
+  level: debug
+`)
+	t.Setenv("PORT", "9000")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.test, ,https://b.test")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Server.Port != 9000 {
+		t.Errorf("Port = %d, want the env value 9000", cfg.Server.Port)
+	}
+	if cfg.Server.ReadTimeout != 10*time.Second {
+		t.Errorf("ReadTimeout = %v, want the file value 10s", cfg.Server.ReadTimeout)
+	}
+	if cfg.Server.WriteTimeout != 30*time.Second {
+		t.Errorf("WriteTimeout = %v, want the default 30s", cfg.Server.WriteTimeout)
+	}
+	if cfg.Log.Level != "debug" || cfg.Database.Name != "clinic.db" {
+		t.Errorf("Log.Level = %q, Database.Name = %q, want the file values", cfg.Log.Level, cfg.Database.Name)
+	}
+	if want := []RefundRule{{24, 100}, {0, 50}}; !reflect.DeepEqual(cfg.Rules.RefundRules, want) {
+		t.Errorf("RefundRules = %v, want the default policy parsed", cfg.Rules.RefundRules)
+	}
+	if want := []string{"https://a.test", "https://b.test"}; !reflect.DeepEqual(cfg.Server.CORSAllowedOrigins, want) {
+		t.Errorf("CORSAllowedOrigins = %q, want %q", cfg.Server.CORSAllowedOrigins, want)
+	}
+}
+
+func TestLoadListsEveryProblem(t *testing.T) {
+	clearEnv(t)
+	writeConfig(t, "")
+	t.Setenv("DB_DRIVER", "oracle")
+	t.Setenv("PORT", "abc")
+	t.Setenv("LOG_LEVEL", "loud")
+	t.Setenv("METRICS_ADDR", "9090")
+	t.Setenv("REFUND_POLICY", "24:150")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load() error = nil, want the configuration rejected")
+	}
+	for _, want := range []string{
+		"PORT must be a whole number",
+		`DB_DRIVER must be mysql, postgres or sqlite, not "oracle"`,
+		"DB_NAME is required",
+		"JWT_SECRET_KEY is required",
+		"PAYMENT_WEBHOOK_SECRET is required",
+		`LOG_LEVEL must be debug, info, warn or error, not "loud"`,
+		"METRICS_ADDR must be host:port",
+		"REFUND_POLICY must be hours:percent pairs",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error = %v\nwant it to mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadRejectsBadFiles(t *testing.T) {
+	t.Run("unknown key", func(t *testing.T) {
+		clearEnv(t)
+		writeConfig(t, minimalConfig+"server:\n  prot: 8000\n")
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "prot") {
+			t.Errorf("Load() error = %v, want the misspelt key reported", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "reading config file") {
+			t.Errorf("Load() error = %v, want the missing file reported", err)
+		}
+	})
+}
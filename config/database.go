@@ -1,28 +1,61 @@
 package config
 
 import (
+	"booking-klinik/logging"
+	"booking-klinik/tracing"
 	"fmt"
-	"os"
+	"time"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func ConnectDB() *gorm.DB {
+// ConnectDB opens the configured database and sizes its connection pool.
+func ConnectDB(cfg DatabaseConfig) (*gorm.DB, error) {
 
-	dbUser := os.Getenv("DB_USER")
-	dbPass := os.Getenv("DB_PASS")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+	// Times are stored in UTC; clinics convert to their own timezone
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "mysql":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
+		dialector = mysql.Open(dsn)
+	case "postgres":
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+			cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Name, cfg.SSLMode)
+		dialector = postgres.Open(dsn)
+	case "sqlite":
+		dsn := "file:" + cfg.Name + "?"
+		if cfg.Name == ":memory:" {
+			// Every pooled connection has to see the same in-memory database
+			dsn = "file::memory:?cache=shared&"
+		}
+		dialector = sqlite.Open(dsn + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.Driver)
+	}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPass, dbHost, dbPort, dbName)
+	db, err := gorm.Open(dialector, &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  &logging.GormLogger{SlowThreshold: cfg.SlowQueryThreshold},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		return nil, fmt.Errorf("setting up query tracing: %w", err)
+	}
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
+		return nil, fmt.Errorf("connecting to database: %w", err)
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	return db
+	return db, nil
 }
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"booking-klinik/config/baseline"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// dataMigration records a one-off data fix so that it only runs once.
+type dataMigration struct {
+	Name      string    `gorm:"primaryKey;size:100"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func runDataMigration(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	if err := db.AutoMigrate(&dataMigration{}); err != nil {
+		return err
+	}
+
+	var applied int64
+	if err := db.Model(&dataMigration{}).Where("name = ?", name).Count(&applied).Error; err != nil {
+		return err
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := migrate(tx); err != nil {
+			return err
+		}
+		return tx.Create(&dataMigration{Name: name, AppliedAt: time.Now().UTC()}).Error
+	})
+}
+
+// calendarDateColumns hold dates without a time of day. They were stored as
+// midnight and already match the midnight UTC convention.
+var calendarDateColumns = map[string]bool{"date": true, "valid_from": true, "valid_until": true}
+
+// convertInstantsToUTC shifts every stored instant from Jakarta wall-clock
+// time to UTC. Before timezones became a clinic setting the server forced
+// time.Local to Asia/Jakarta (UTC+7, no daylight saving) and the DSN used
+// loc=Local.
+func convertInstantsToUTC(tx *gorm.DB) error {
+	var users int64
+	if err := tx.Unscoped().Model(&baseline.User{}).Count(&users).Error; err != nil {
+		return err
+	}
+	if users == 0 {
+		// A new database has nothing to convert
+		return nil
+	}
+
+	legacyModels := []interface{}{&baseline.User{}, &baseline.Doctor{}, &baseline.Booking{}, &baseline.Service{}, &baseline.DoctorSchedule{}, &baseline.VitalSign{}, &baseline.Attachment{}, &baseline.Invoice{}, &baseline.InvoiceItem{}, &baseline.InvoiceSequence{}, &baseline.Payment{}, &baseline.Refund{}, &baseline.ServiceCoverage{}, &baseline.InsuranceClaim{}, &baseline.ClaimBatch{}, &baseline.Promo{}, &baseline.PromoUsage{}, &baseline.ServicePrice{}, &baseline.LicenseAlert{}, &baseline.Review{}, &baseline.Resource{}}
+	for _, legacyModel := range legacyModels {
+		stmt := &gorm.Statement{DB: tx}
+		if err := stmt.Parse(legacyModel); err != nil {
+			return err
+		}
+		for _, field := range stmt.Schema.Fields {
+			if field.DataType != schema.Time || field.DBName == "" || calendarDateColumns[field.DBName] {
+				continue
+			}
+			query := fmt.Sprintf("UPDATE `%s` SET `%s` = DATE_SUB(`%s`, INTERVAL 7 HOUR) WHERE `%s` IS NOT NULL", stmt.Schema.Table, field.DBName, field.DBName, field.DBName)
+			if err := tx.Exec(query).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// migrateBookingIntervals moves bookings from a booking_date and booking_time
+// to a start_at/end_at interval, the end taken from the service duration. It
+// runs before AutoMigrate, which would otherwise add the new NOT NULL columns
+// without values. Every step can be repeated if an earlier run was interrupted.
+func migrateBookingIntervals(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable("bookings") || !migrator.HasColumn("bookings", "booking_time") {
+		return nil
+	}
+
+	for _, column := range []string{"start_at", "end_at"} {
+		if !migrator.HasColumn("bookings", column) {
+			if err := db.Exec(fmt.Sprintf("ALTER TABLE `bookings` ADD COLUMN `%s` DATETIME(3) NULL", column)).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := db.Exec("UPDATE `bookings` JOIN `services` ON `services`.`id` = `bookings`.`service_id` " +
+		"SET `bookings`.`start_at` = `bookings`.`booking_time`, " +
+		"`bookings`.`end_at` = DATE_ADD(`bookings`.`booking_time`, INTERVAL `services`.`duration_minutes` MINUTE) " +
+		"WHERE `bookings`.`start_at` IS NULL").Error; err != nil {
+		return err
+	}
+
+	if migrator.HasIndex("bookings", "idx_booking_doctor_date") {
+		if err := migrator.DropIndex("bookings", "idx_booking_doctor_date"); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{"booking_date", "booking_time"} {
+		if err := migrator.DropColumn("bookings", column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package config
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrations are pairs of SQL files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, in a directory per database driver (mysql,
+// postgres, sqlite) with the same versions in each. Statements in a file end
+// with a semicolon at the end of a line; lines starting with -- are comments.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// MigrationsDir is where `migrate create` writes new migrations, relative to
+// the repository root.
+const MigrationsDir = "config/migrations"
+
+// migrationDialects are the database drivers migrations are written for.
+var migrationDialects = []string{"mysql", "postgres", "sqlite"}
+
+// baselineVersion is the schema that gorm AutoMigrate used to create.
+const baselineVersion = 1
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// schemaMigration records an applied migration. Dirty is set while the
+// migration runs, so a failure halfway is visible.
+type schemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:100;not null"`
+	Dirty     bool      `gorm:"not null;default:false"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// LoadMigrations returns the embedded migrations of a database driver
+// ordered by version.
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := "migrations/" + dialect
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database driver %s", dialect)
+	}
+
+	byVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		content, err := migrationFiles.ReadFile(dir + "/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// LatestVersion is the schema version this build expects.
+func LatestVersion(dialect string) (uint, error) {
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[uint]schemaMigration, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		if err := db.Migrator().CreateTable(&schemaMigration{}); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	applied := map[uint]schemaMigration{}
+	for _, row := range rows {
+		if row.Dirty {
+			return nil, fmt.Errorf("migration %d_%s did not finish: repair the schema by hand, then clear its dirty flag or delete its row in schema_migrations", row.Version, row.Name)
+		}
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// MigrateUp applies pending migrations in order, at most steps of them when
+// steps is positive.
+func MigrateUp(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// A database created by AutoMigrate already has tables but no versions.
+	// Those were only ever MySQL databases.
+	if len(applied) == 0 && db.Migrator().HasTable("users") {
+		if db.Dialector.Name() != "mysql" {
+			return nil, errors.New("database has tables but no schema_migrations; only MySQL databases from before versioned migrations can be upgraded")
+		}
+		slog.Info("upgrading unversioned database to the baseline schema")
+		if err := upgradeLegacySchema(db); err != nil {
+			return nil, err
+		}
+		baseline := schemaMigration{Version: baselineVersion, Name: "baseline", AppliedAt: time.Now().UTC()}
+		if err := db.Create(&baseline).Error; err != nil {
+			return nil, err
+		}
+		applied[baseline.Version] = baseline
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if steps > 0 && len(done) == steps {
+			break
+		}
+
+		row := schemaMigration{Version: migration.Version, Name: migration.Name, Dirty: true, AppliedAt: time.Now().UTC()}
+		if err := db.Create(&row).Error; err != nil {
+			return done, err
+		}
+		if err := execMigration(db, migration.Up); err != nil {
+			return done, fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if err := db.Model(&row).Updates(map[string]interface{}{"dirty": false, "applied_at": time.Now().UTC()}).Error; err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown rolls back the last steps applied migrations.
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		row, ok := applied[migration.Version]
+		if !ok {
+			continue
+		}
+
+		if err := db.Model(&row).Update("dirty", true).Error; err != nil {
+			return done, err
+		}
+		if err := execMigration(db, migration.Down); err != nil {
+			return done, fmt.Errorf("rolling back migration %d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		if err := db.Delete(&row).Error; err != nil {
+			return done, err
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// GetMigrationStatus lists every known migration and whether it is applied,
+// followed by applied versions this build does not know.
+func GetMigrationStatus(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		if err := db.Order("version").Find(&rows).Error; err != nil {
+			return nil, err
+		}
+	}
+	applied := map[uint]schemaMigration{}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+
+	var statuses []MigrationStatus
+	known := map[uint]bool{}
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		row, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{Version: migration.Version, Name: migration.Name, Applied: ok, Dirty: row.Dirty, AppliedAt: row.AppliedAt})
+	}
+	for _, row := range rows {
+		if !known[row.Version] {
+			statuses = append(statuses, MigrationStatus{Version: row.Version, Name: row.Name, Applied: true, Dirty: row.Dirty, AppliedAt: row.AppliedAt})
+		}
+	}
+	return statuses, nil
+}
+
+// CheckSchemaVersion returns an error unless every migration of this build,
+// and no other, has been applied to the database.
+func CheckSchemaVersion(db *gorm.DB) error {
+	statuses, err := GetMigrationStatus(db)
+	if err != nil {
+		return err
+	}
+	latest, err := LatestVersion(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		switch {
+		case status.Dirty:
+			return fmt.Errorf("migration %d_%s did not finish; repair the database before starting", status.Version, status.Name)
+		case status.Version > latest:
+			return fmt.Errorf("database schema has migration %d_%s, newer than this build (version %d)", status.Version, status.Name, latest)
+		case !status.Applied:
+			return fmt.Errorf("database schema is missing migration %d_%s; run `migrate up`", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// SchemaVersion returns the highest migration version applied to the
+// database, or 0 when none is.
+func SchemaVersion(db *gorm.DB) (uint, error) {
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+	var version uint
+	err := db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// CreateMigration writes an empty up and down file for the next version in
+// the directory of every database driver under dir and returns their paths.
+func CreateMigration(dir, name string) ([]string, error) {
+	if !regexp.MustCompile(`^[a-z0-9_]+$`).MatchString(name) {
+		return nil, errors.New("migration name may only contain lowercase letters, digits and underscores")
+	}
+
+	var latest uint64
+	for _, dialect := range migrationDialects {
+		entries, err := os.ReadDir(filepath.Join(dir, dialect))
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if match := migrationFileName.FindStringSubmatch(entry.Name()); match != nil {
+				version, _ := strconv.ParseUint(match[1], 10, 32)
+				if version > latest {
+					latest = version
+				}
+			}
+		}
+	}
+
+	var paths []string
+	for _, dialect := range migrationDialects {
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(dir, dialect, fmt.Sprintf("%04d_%s.%s.sql", latest+1, name, direction))
+			content := fmt.Sprintf("-- %s: %s\n", name, direction)
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				return paths, err
+			}
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func execMigration(db *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
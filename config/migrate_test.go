@@ -0,0 +1,178 @@
+package config
+
+import (
+	"booking-klinik/config/baseline"
+	"booking-klinik/model"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens an empty in-memory SQLite database of the test's own. It
+// holds a single connection, which keeps the database alive until the test
+// ends.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestMigrationsMatchAcrossDrivers(t *testing.T) {
+	want, err := LoadMigrations(migrationDialects[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dialect := range migrationDialects[1:] {
+		migrations, err := LoadMigrations(dialect)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(migrations) != len(want) {
+			t.Fatalf("%s has %d migrations, %s has %d", dialect, len(migrations), migrationDialects[0], len(want))
+		}
+		for i := range want {
+			if migrations[i].Version != want[i].Version || migrations[i].Name != want[i].Name {
+				t.Errorf("%s migration %d is %d_%s, want %d_%s", dialect, i, migrations[i].Version, migrations[i].Name, want[i].Version, want[i].Name)
+			}
+		}
+	}
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	db := newTestDB(t)
+	latest, err := LatestVersion("sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := MigrateUp(db, 0)
+	if err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	if err := CheckSchemaVersion(db); err != nil {
+		t.Fatalf("CheckSchemaVersion() after migrating up = %v", err)
+	}
+	if version, _ := SchemaVersion(db); version != latest {
+		t.Errorf("SchemaVersion() = %d, want %d", version, latest)
+	}
+	if again, err := MigrateUp(db, 0); err != nil || len(again) != 0 {
+		t.Errorf("second MigrateUp() = %d migrations, %v; want none", len(again), err)
+	}
+
+	if _, err := MigrateDown(db, len(applied)); err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+	if version, _ := SchemaVersion(db); version != 0 {
+		t.Errorf("SchemaVersion() after migrating down = %d, want 0", version)
+	}
+	if db.Migrator().HasTable("bookings") {
+		t.Error("bookings table left after migrating down")
+	}
+
+	if _, err := MigrateUp(db, 0); err != nil {
+		t.Fatalf("MigrateUp() after migrating down error = %v", err)
+	}
+}
+
+func TestCheckSchemaVersionReportsMissingMigrations(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := MigrateUp(db, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	err := CheckSchemaVersion(db)
+	if err == nil || !strings.Contains(err.Error(), "run `migrate up`") {
+		t.Errorf("CheckSchemaVersion() = %v, want a missing migration", err)
+	}
+}
+
+// The migrations, not the models, define the schema; every column a model
+// maps must exist.
+func TestMigratedSchemaHasEveryModelColumn(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := MigrateUp(db, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range []interface{}{&model.User{}, &model.Doctor{}, &model.Booking{}, &model.Service{}, &model.DoctorSchedule{}, &model.VitalSign{}, &model.Attachment{}, &model.Invoice{}, &model.InvoiceItem{}, &model.InvoiceSequence{}, &model.Payment{}, &model.Refund{}, &model.ServiceCoverage{}, &model.InsuranceClaim{}, &model.ClaimBatch{}, &model.Promo{}, &model.PromoUsage{}, &model.ServicePrice{}, &model.LicenseAlert{}, &model.Review{}, &model.Resource{}, &model.Clinic{}, &model.ClinicOpeningHour{}} {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(value); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range statement.Schema.Fields {
+			if field.DBName != "" && !db.Migrator().HasColumn(value, field.DBName) {
+				t.Errorf("%s has no column %s", statement.Schema.Table, field.DBName)
+			}
+		}
+	}
+}
+
+// Legacy databases are brought to the frozen baseline models, so those must
+// map exactly the tables and columns of the baseline migration.
+func TestBaselineModelsMatchBaselineMigration(t *testing.T) {
+	db := newTestDB(t)
+	if _, err := MigrateUp(db, baselineVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range baseline.Tables {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(value); err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, field := range statement.Schema.Fields {
+			if field.DBName != "" {
+				want = append(want, field.DBName)
+			}
+		}
+		columns, err := db.Migrator().ColumnTypes(statement.Schema.Table)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, column := range columns {
+			got = append(got, column.Name())
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s has columns %v, the baseline model maps %v", statement.Schema.Table, got, want)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- comment
+CREATE TABLE a (
+  id INT
+);
+
+  -- indented comment
+INSERT INTO a VALUES ('x;'), ('y');
+UPDATE a SET id = 2`
+
+	got := splitStatements(script)
+	want := []string{"CREATE TABLE a (\n  id INT\n)", "INSERT INTO a VALUES ('x;'), ('y')", "UPDATE a SET id = 2"}
+	if len(got) != len(want) {
+		t.Fatalf("splitStatements() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
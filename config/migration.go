@@ -1,17 +1,60 @@
 package config
 
 import (
-	"booking-klinik/model"
-	"log"
+	"booking-klinik/config/baseline"
+	"booking-klinik/utils"
 
 	"gorm.io/gorm"
 )
 
-func MigrateDB(db *gorm.DB) {
-	err := db.AutoMigrate(&model.User{}, &model.Doctor{}, &model.Booking{}, &model.Service{}, &model.DoctorSchedule{})
-	if err != nil {
-		panic(err)
+// upgradeLegacySchema brings a database created by gorm AutoMigrate, before
+// versioned migrations existed, up to the baseline migration. Later
+// migrations then apply as on any other database.
+func upgradeLegacySchema(db *gorm.DB) error {
+	if err := migrateBookingIntervals(db); err != nil {
+		return err
 	}
 
-	log.Println("Database migrated successfully")
+	if err := db.AutoMigrate(baseline.Tables...); err != nil {
+		return err
+	}
+
+	// Doctors used to be bookable for any service they had a schedule for.
+	// Seed the doctor-service mapping from those schedules the first time.
+	var mappings int64
+	if err := db.Table("doctor_services").Count(&mappings).Error; err != nil {
+		return err
+	}
+	if mappings == 0 {
+		if err := db.Exec("INSERT INTO doctor_services (doctor_id, service_id) SELECT DISTINCT doctor_id, service_id FROM doctor_schedules WHERE deleted_at IS NULL").Error; err != nil {
+			return err
+		}
+	}
+
+	// Everything existing before branches were introduced belongs to the
+	// first clinic.
+	var clinics int64
+	if err := db.Model(&baseline.Clinic{}).Count(&clinics).Error; err != nil {
+		return err
+	}
+	if clinics == 0 {
+		mainClinic := baseline.Clinic{Name: "Main Clinic", Timezone: utils.DefaultTimezone, IsActive: true}
+		if err := db.Create(&mainClinic).Error; err != nil {
+			return err
+		}
+		for _, table := range []string{"doctor_schedules", "bookings", "resources"} {
+			if err := db.Table(table).Where("clinic_id = ? OR clinic_id IS NULL", 0).Update("clinic_id", mainClinic.ID).Error; err != nil {
+				return err
+			}
+		}
+		if err := db.Exec("INSERT INTO doctor_clinics (doctor_id, clinic_id) SELECT id, ? FROM doctors WHERE deleted_at IS NULL", mainClinic.ID).Error; err != nil {
+			return err
+		}
+		// Admins only see a clinic they are assigned to; super admins see all
+		if err := db.Model(&baseline.User{}).Where("role = ? AND clinic_id IS NULL", "admin").Update("clinic_id", mainClinic.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return runDataMigration(db, "store_instants_in_utc", convertInstantsToUTC)
 }
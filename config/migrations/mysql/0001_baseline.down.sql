@@ -0,0 +1,27 @@
+DROP TABLE IF EXISTS `clinic_opening_hours`;
+DROP TABLE IF EXISTS `reviews`;
+DROP TABLE IF EXISTS `license_alerts`;
+DROP TABLE IF EXISTS `service_prices`;
+DROP TABLE IF EXISTS `promo_usages`;
+DROP TABLE IF EXISTS `promos`;
+DROP TABLE IF EXISTS `insurance_claims`;
+DROP TABLE IF EXISTS `claim_batches`;
+DROP TABLE IF EXISTS `service_coverages`;
+DROP TABLE IF EXISTS `refunds`;
+DROP TABLE IF EXISTS `payments`;
+DROP TABLE IF EXISTS `invoice_sequences`;
+DROP TABLE IF EXISTS `invoice_items`;
+DROP TABLE IF EXISTS `invoices`;
+DROP TABLE IF EXISTS `attachments`;
+DROP TABLE IF EXISTS `vital_signs`;
+DROP TABLE IF EXISTS `doctor_schedules`;
+DROP TABLE IF EXISTS `service_resources`;
+DROP TABLE IF EXISTS `booking_resources`;
+DROP TABLE IF EXISTS `resources`;
+DROP TABLE IF EXISTS `bookings`;
+DROP TABLE IF EXISTS `doctor_services`;
+DROP TABLE IF EXISTS `services`;
+DROP TABLE IF EXISTS `doctor_clinics`;
+DROP TABLE IF EXISTS `clinics`;
+DROP TABLE IF EXISTS `doctors`;
+DROP TABLE IF EXISTS `users`;
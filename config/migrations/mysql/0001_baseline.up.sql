@@ -0,0 +1,513 @@
+-- Schema of the application when versioned migrations were introduced.
+
+CREATE TABLE `users` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` longtext,
+  `email` varchar(191) NOT NULL,
+  `password` longtext NOT NULL,
+  `role` longtext,
+  `clinic_id` bigint unsigned,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_users_deleted_at` (`deleted_at`),
+  INDEX `idx_users_clinic_id` (`clinic_id`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE `doctors` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `specialization` longtext NOT NULL,
+  `bio` text,
+  `photo_url` longtext,
+  `languages` longtext,
+  `education` text,
+  `practice_since` bigint,
+  `str_number` longtext,
+  `str_expires_at` datetime(3) NULL,
+  `sip_number` longtext,
+  `sip_expires_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_doctors_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_user_id` (`user_id`),
+  CONSTRAINT `fk_doctors_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `clinics` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` varchar(150) NOT NULL,
+  `address` text,
+  `phone` longtext,
+  `timezone` varchar(191) NOT NULL DEFAULT 'Asia/Jakarta',
+  `is_active` boolean NOT NULL DEFAULT true,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_clinics_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_clinics_name` (`name`)
+);
+
+CREATE TABLE `doctor_clinics` (
+  `clinic_id` bigint unsigned,
+  `doctor_id` bigint unsigned,
+  PRIMARY KEY (`clinic_id`,`doctor_id`),
+  CONSTRAINT `fk_doctor_clinics_clinic` FOREIGN KEY (`clinic_id`) REFERENCES `clinics`(`id`),
+  CONSTRAINT `fk_doctor_clinics_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+
+CREATE TABLE `services` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` varchar(191) NOT NULL,
+  `description` text,
+  `price` bigint NOT NULL,
+  `duration_minutes` bigint NOT NULL,
+  `is_active` boolean NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  `clinic_id` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_services_deleted_at` (`deleted_at`),
+  INDEX `idx_services_clinic_id` (`clinic_id`),
+  CONSTRAINT `uni_services_name` UNIQUE (`name`)
+);
+
+CREATE TABLE `doctor_services` (
+  `service_id` bigint unsigned,
+  `doctor_id` bigint unsigned,
+  PRIMARY KEY (`service_id`,`doctor_id`),
+  CONSTRAINT `fk_doctor_services_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_doctor_services_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+
+CREATE TABLE `bookings` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `doctor_id` bigint unsigned NOT NULL,
+  `service_id` bigint unsigned NOT NULL,
+  `clinic_id` bigint unsigned,
+  `start_at` datetime(3) NOT NULL,
+  `end_at` datetime(3) NOT NULL,
+  `status` varchar(191) NOT NULL DEFAULT 'pending',
+  `notes` text,
+  `payer_type` varchar(191) NOT NULL DEFAULT 'self_pay',
+  `insurer_name` longtext,
+  `policy_number` longtext,
+  `price` bigint NOT NULL DEFAULT 0,
+  `discount_amount` bigint NOT NULL DEFAULT 0,
+  `final_price` bigint NOT NULL DEFAULT 0,
+  `promo_code` longtext,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_bookings_deleted_at` (`deleted_at`),
+  INDEX `idx_booking_doctor_interval` (`doctor_id`,`start_at`,`end_at`),
+  INDEX `idx_bookings_clinic_id` (`clinic_id`),
+  CONSTRAINT `fk_services_bookings` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`),
+  CONSTRAINT `fk_users_booking` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_doctors_bookings` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+
+CREATE TABLE `resources` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `name` longtext NOT NULL,
+  `type` varchar(191) NOT NULL,
+  `description` text,
+  `is_active` boolean NOT NULL DEFAULT true,
+  `clinic_id` bigint unsigned,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_resources_type` (`type`),
+  INDEX `idx_resources_clinic_id` (`clinic_id`),
+  INDEX `idx_resources_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE `booking_resources` (
+  `booking_id` bigint unsigned,
+  `resource_id` bigint unsigned,
+  PRIMARY KEY (`booking_id`,`resource_id`),
+  CONSTRAINT `fk_booking_resources_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_booking_resources_resource` FOREIGN KEY (`resource_id`) REFERENCES `resources`(`id`)
+);
+
+CREATE TABLE `service_resources` (
+  `resource_id` bigint unsigned,
+  `service_id` bigint unsigned,
+  PRIMARY KEY (`resource_id`,`service_id`),
+  CONSTRAINT `fk_service_resources_resource` FOREIGN KEY (`resource_id`) REFERENCES `resources`(`id`),
+  CONSTRAINT `fk_service_resources_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+
+CREATE TABLE `doctor_schedules` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `doctor_id` bigint unsigned NOT NULL,
+  `service_id` bigint unsigned NOT NULL,
+  `date` datetime(3) NOT NULL,
+  `start_time` datetime(3) NOT NULL,
+  `end_time` datetime(3) NOT NULL,
+  `room_id` bigint unsigned,
+  `clinic_id` bigint unsigned,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_doctor_schedules_room_id` (`room_id`),
+  INDEX `idx_doctor_schedules_clinic_id` (`clinic_id`),
+  INDEX `idx_doctor_schedules_deleted_at` (`deleted_at`),
+  INDEX `idx_schedule_service_date` (`service_id`,`date`),
+  CONSTRAINT `fk_doctors_schedules` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_services_schedules` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`),
+  CONSTRAINT `fk_doctor_schedules_room` FOREIGN KEY (`room_id`) REFERENCES `resources`(`id`)
+);
+
+CREATE TABLE `vital_signs` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `systolic` bigint NOT NULL,
+  `diastolic` bigint NOT NULL,
+  `temperature` double NOT NULL,
+  `weight_kg` double NOT NULL,
+  `height_cm` double NOT NULL,
+  `pulse` bigint NOT NULL,
+  `sp_o2` bigint NOT NULL,
+  `bmi` double NOT NULL,
+  `flags` longtext,
+  `recorded_at` datetime(3) NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_vital_signs_deleted_at` (`deleted_at`),
+  INDEX `idx_vital_signs_booking_id` (`booking_id`),
+  INDEX `idx_vital_signs_user_id` (`user_id`),
+  CONSTRAINT `fk_vital_signs_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_vital_signs_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+
+CREATE TABLE `attachments` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `file_name` longtext NOT NULL,
+  `content_type` longtext NOT NULL,
+  `size` bigint NOT NULL,
+  `storage_key` varchar(191) NOT NULL,
+  `category` varchar(191) NOT NULL DEFAULT 'other',
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_attachments_deleted_at` (`deleted_at`),
+  INDEX `idx_attachments_booking_id` (`booking_id`),
+  CONSTRAINT `fk_attachments_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `uni_attachments_storage_key` UNIQUE (`storage_key`)
+);
+
+CREATE TABLE `invoices` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `invoice_number` varchar(191) NOT NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `subtotal` bigint NOT NULL,
+  `discount_amount` bigint NOT NULL DEFAULT 0,
+  `tax_percent` double NOT NULL DEFAULT 0,
+  `tax_amount` bigint NOT NULL DEFAULT 0,
+  `total` bigint NOT NULL,
+  `status` varchar(191) NOT NULL DEFAULT 'unpaid',
+  `issued_at` datetime(3) NOT NULL,
+  `paid_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_invoices_user_id` (`user_id`),
+  INDEX `idx_invoices_status` (`status`),
+  INDEX `idx_invoices_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_invoices_booking_id` (`booking_id`),
+  CONSTRAINT `fk_invoices_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_invoices_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `uni_invoices_invoice_number` UNIQUE (`invoice_number`)
+);
+
+CREATE TABLE `invoice_items` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `invoice_id` bigint unsigned NOT NULL,
+  `item_type` longtext NOT NULL,
+  `description` longtext NOT NULL,
+  `quantity` bigint NOT NULL,
+  `unit_price` bigint NOT NULL,
+  `amount` bigint NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_invoice_items_deleted_at` (`deleted_at`),
+  INDEX `idx_invoice_items_invoice_id` (`invoice_id`),
+  CONSTRAINT `fk_invoices_items` FOREIGN KEY (`invoice_id`) REFERENCES `invoices`(`id`)
+);
+
+CREATE TABLE `invoice_sequences` (
+  `period` varchar(6),
+  `last_number` bigint NOT NULL,
+  PRIMARY KEY (`period`)
+);
+
+CREATE TABLE `payments` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `amount` bigint NOT NULL,
+  `method` longtext NOT NULL,
+  `provider` varchar(50) NOT NULL,
+  `provider_ref` varchar(100) NOT NULL,
+  `reference` longtext,
+  `status` varchar(191) NOT NULL DEFAULT 'pending',
+  `va_number` longtext,
+  `redirect_url` longtext,
+  `expires_at` datetime(3) NULL,
+  `paid_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_payments_booking_id` (`booking_id`),
+  INDEX `idx_payments_user_id` (`user_id`),
+  UNIQUE INDEX `idx_provider_ref` (`provider`,`provider_ref`),
+  INDEX `idx_payments_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_payments_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+
+CREATE TABLE `refunds` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `payment_id` bigint unsigned NOT NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `amount` bigint NOT NULL,
+  `percent` bigint NOT NULL,
+  `reason` longtext,
+  `status` varchar(191) NOT NULL DEFAULT 'pending_approval',
+  `refund_ref` longtext,
+  `approved_by` bigint unsigned,
+  `processed_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_refunds_deleted_at` (`deleted_at`),
+  INDEX `idx_refunds_payment_id` (`payment_id`),
+  INDEX `idx_refunds_booking_id` (`booking_id`),
+  INDEX `idx_refunds_status` (`status`),
+  CONSTRAINT `fk_refunds_payment` FOREIGN KEY (`payment_id`) REFERENCES `payments`(`id`)
+);
+
+CREATE TABLE `service_coverages` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `service_id` bigint unsigned NOT NULL,
+  `payer_type` longtext NOT NULL,
+  `insurer_name` longtext,
+  `coverage_percent` bigint NOT NULL,
+  `max_amount` bigint NOT NULL DEFAULT 0,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_service_coverages_service_id` (`service_id`),
+  INDEX `idx_service_coverages_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_service_coverages_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+
+CREATE TABLE `claim_batches` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `batch_number` varchar(191) NOT NULL,
+  `payer_type` longtext NOT NULL,
+  `insurer_name` longtext,
+  `claim_count` bigint NOT NULL,
+  `total_amount` bigint NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_claim_batches_deleted_at` (`deleted_at`),
+  CONSTRAINT `uni_claim_batches_batch_number` UNIQUE (`batch_number`)
+);
+
+CREATE TABLE `insurance_claims` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `service_id` bigint unsigned NOT NULL,
+  `payer_type` varchar(191) NOT NULL,
+  `insurer_name` longtext,
+  `policy_number` longtext NOT NULL,
+  `billed_amount` bigint NOT NULL,
+  `covered_amount` bigint NOT NULL,
+  `status` varchar(191) NOT NULL DEFAULT 'draft',
+  `batch_id` bigint unsigned,
+  `rejection_reason` longtext,
+  `submitted_at` datetime(3) NULL,
+  `decided_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_insurance_claims_booking_id` (`booking_id`),
+  INDEX `idx_insurance_claims_user_id` (`user_id`),
+  INDEX `idx_insurance_claims_payer_type` (`payer_type`),
+  INDEX `idx_insurance_claims_status` (`status`),
+  INDEX `idx_insurance_claims_batch_id` (`batch_id`),
+  INDEX `idx_insurance_claims_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_insurance_claims_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_claim_batches_claims` FOREIGN KEY (`batch_id`) REFERENCES `claim_batches`(`id`)
+);
+
+CREATE TABLE `promos` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `code` varchar(50) NOT NULL,
+  `description` text,
+  `discount_type` longtext NOT NULL,
+  `discount_value` bigint NOT NULL,
+  `valid_from` datetime(3) NOT NULL,
+  `valid_until` datetime(3) NOT NULL,
+  `service_id` bigint unsigned,
+  `doctor_id` bigint unsigned,
+  `max_uses` bigint NOT NULL DEFAULT 0,
+  `max_uses_per_patient` bigint NOT NULL DEFAULT 0,
+  `first_visit_only` boolean NOT NULL DEFAULT false,
+  `is_active` boolean NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_promos_deleted_at` (`deleted_at`),
+  CONSTRAINT `uni_promos_code` UNIQUE (`code`)
+);
+
+CREATE TABLE `promo_usages` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `promo_id` bigint unsigned NOT NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `discount_amount` bigint NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_promo_usages_booking_id` (`booking_id`),
+  INDEX `idx_promo_usages_user_id` (`user_id`),
+  INDEX `idx_promo_usages_deleted_at` (`deleted_at`),
+  INDEX `idx_promo_usages_promo_id` (`promo_id`),
+  CONSTRAINT `fk_promo_usages_promo` FOREIGN KEY (`promo_id`) REFERENCES `promos`(`id`),
+  CONSTRAINT `fk_promo_usages_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+
+CREATE TABLE `service_prices` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `service_id` bigint unsigned NOT NULL,
+  `doctor_id` bigint unsigned,
+  `price` bigint NOT NULL,
+  `effective_from` datetime(3) NOT NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_service_prices_deleted_at` (`deleted_at`),
+  INDEX `idx_service_price_lookup` (`service_id`,`doctor_id`,`effective_from`),
+  CONSTRAINT `fk_service_prices_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+
+CREATE TABLE `license_alerts` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `doctor_id` bigint unsigned NOT NULL,
+  `license` varchar(3) NOT NULL,
+  `kind` varchar(10) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_license_alerts_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_license_alert` (`doctor_id`,`license`,`kind`,`expires_at`),
+  CONSTRAINT `fk_license_alerts_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+
+CREATE TABLE `reviews` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `deleted_at` datetime(3) NULL,
+  `booking_id` bigint unsigned NOT NULL,
+  `doctor_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `rating` bigint NOT NULL,
+  `comment` text,
+  `is_hidden` boolean NOT NULL DEFAULT false,
+  `hidden_reason` longtext,
+  `hidden_by` bigint unsigned,
+  `reply` text,
+  `replied_at` datetime(3) NULL,
+  `created_by` bigint unsigned NOT NULL,
+  `updated_by` bigint unsigned,
+  PRIMARY KEY (`id`),
+  INDEX `idx_reviews_deleted_at` (`deleted_at`),
+  UNIQUE INDEX `idx_reviews_booking_id` (`booking_id`),
+  INDEX `idx_reviews_doctor_id` (`doctor_id`),
+  CONSTRAINT `fk_reviews_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_reviews_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_reviews_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+
+CREATE TABLE `clinic_opening_hours` (
+  `id` bigint unsigned AUTO_INCREMENT,
+  `clinic_id` bigint unsigned NOT NULL,
+  `weekday` bigint NOT NULL,
+  `opens_at` varchar(5) NOT NULL,
+  `closes_at` varchar(5) NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_clinic_opening_hours_clinic_id` (`clinic_id`),
+  CONSTRAINT `fk_clinics_opening_hours` FOREIGN KEY (`clinic_id`) REFERENCES `clinics`(`id`)
+);
+
+-- Every record belongs to a clinic; new installations start with one.
+INSERT INTO `clinics` (`created_at`, `updated_at`, `name`, `timezone`, `is_active`, `created_by`) VALUES (UTC_TIMESTAMP(3), UTC_TIMESTAMP(3), 'Main Clinic', 'Asia/Jakarta', true, 0);
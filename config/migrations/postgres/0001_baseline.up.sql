@@ -0,0 +1,513 @@
+-- Schema of the application when versioned migrations were introduced.
+
+CREATE TABLE "users" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" text,
+  "email" text NOT NULL,
+  "password" text NOT NULL,
+  "role" text,
+  "clinic_id" bigint,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_clinic_id" ON "users" ("clinic_id");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE "doctors" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "specialization" text NOT NULL,
+  "bio" text,
+  "photo_url" text,
+  "languages" text,
+  "education" text,
+  "practice_since" bigint,
+  "str_number" text,
+  "str_expires_at" timestamptz,
+  "sip_number" text,
+  "sip_expires_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_doctors_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_id" ON "doctors" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_doctors_deleted_at" ON "doctors" ("deleted_at");
+
+CREATE TABLE "clinics" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" varchar(150) NOT NULL,
+  "address" text,
+  "phone" text,
+  "timezone" text NOT NULL DEFAULT 'Asia/Jakarta',
+  "is_active" boolean NOT NULL DEFAULT true,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_clinics_name" ON "clinics" ("name");
+CREATE INDEX IF NOT EXISTS "idx_clinics_deleted_at" ON "clinics" ("deleted_at");
+
+CREATE TABLE "services" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" text NOT NULL,
+  "description" text,
+  "price" bigint NOT NULL,
+  "duration_minutes" bigint NOT NULL,
+  "is_active" boolean NOT NULL,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  "clinic_id" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "uni_services_name" UNIQUE ("name")
+);
+CREATE INDEX IF NOT EXISTS "idx_services_clinic_id" ON "services" ("clinic_id");
+CREATE INDEX IF NOT EXISTS "idx_services_deleted_at" ON "services" ("deleted_at");
+
+CREATE TABLE "bookings" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "user_id" bigint NOT NULL,
+  "doctor_id" bigint NOT NULL,
+  "service_id" bigint NOT NULL,
+  "clinic_id" bigint,
+  "start_at" timestamptz NOT NULL,
+  "end_at" timestamptz NOT NULL,
+  "status" text NOT NULL DEFAULT 'pending',
+  "notes" text,
+  "payer_type" text NOT NULL DEFAULT 'self_pay',
+  "insurer_name" text,
+  "policy_number" text,
+  "price" bigint NOT NULL DEFAULT 0,
+  "discount_amount" bigint NOT NULL DEFAULT 0,
+  "final_price" bigint NOT NULL DEFAULT 0,
+  "promo_code" text,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_services_bookings" FOREIGN KEY ("service_id") REFERENCES "services"("id"),
+  CONSTRAINT "fk_users_booking" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+  CONSTRAINT "fk_doctors_bookings" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_booking_doctor_interval" ON "bookings" ("doctor_id","start_at","end_at");
+CREATE INDEX IF NOT EXISTS "idx_bookings_deleted_at" ON "bookings" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_bookings_clinic_id" ON "bookings" ("clinic_id");
+
+CREATE TABLE "resources" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "name" text NOT NULL,
+  "type" text NOT NULL,
+  "description" text,
+  "is_active" boolean NOT NULL DEFAULT true,
+  "clinic_id" bigint,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_resources_clinic_id" ON "resources" ("clinic_id");
+CREATE INDEX IF NOT EXISTS "idx_resources_type" ON "resources" ("type");
+CREATE INDEX IF NOT EXISTS "idx_resources_deleted_at" ON "resources" ("deleted_at");
+
+CREATE TABLE "doctor_schedules" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "doctor_id" bigint NOT NULL,
+  "service_id" bigint NOT NULL,
+  "date" timestamptz NOT NULL,
+  "start_time" timestamptz NOT NULL,
+  "end_time" timestamptz NOT NULL,
+  "room_id" bigint,
+  "clinic_id" bigint,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_services_schedules" FOREIGN KEY ("service_id") REFERENCES "services"("id"),
+  CONSTRAINT "fk_doctor_schedules_room" FOREIGN KEY ("room_id") REFERENCES "resources"("id"),
+  CONSTRAINT "fk_doctors_schedules" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_doctor_schedules_clinic_id" ON "doctor_schedules" ("clinic_id");
+CREATE INDEX IF NOT EXISTS "idx_doctor_schedules_room_id" ON "doctor_schedules" ("room_id");
+CREATE INDEX IF NOT EXISTS "idx_schedule_service_date" ON "doctor_schedules" ("service_id","date");
+CREATE INDEX IF NOT EXISTS "idx_doctor_schedules_deleted_at" ON "doctor_schedules" ("deleted_at");
+
+CREATE TABLE "vital_signs" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "booking_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "systolic" bigint NOT NULL,
+  "diastolic" bigint NOT NULL,
+  "temperature" decimal NOT NULL,
+  "weight_kg" decimal NOT NULL,
+  "height_cm" decimal NOT NULL,
+  "pulse" bigint NOT NULL,
+  "sp_o2" bigint NOT NULL,
+  "bmi" decimal NOT NULL,
+  "flags" text,
+  "recorded_at" timestamptz NOT NULL,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_vital_signs_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id"),
+  CONSTRAINT "fk_vital_signs_user" FOREIGN KEY ("user_id") REFERENCES "users"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_vital_signs_booking_id" ON "vital_signs" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_vital_signs_deleted_at" ON "vital_signs" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_vital_signs_user_id" ON "vital_signs" ("user_id");
+
+CREATE TABLE "attachments" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "booking_id" bigint NOT NULL,
+  "file_name" text NOT NULL,
+  "content_type" text NOT NULL,
+  "size" bigint NOT NULL,
+  "storage_key" text NOT NULL,
+  "category" text NOT NULL DEFAULT 'other',
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_attachments_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id"),
+  CONSTRAINT "uni_attachments_storage_key" UNIQUE ("storage_key")
+);
+CREATE INDEX IF NOT EXISTS "idx_attachments_booking_id" ON "attachments" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_attachments_deleted_at" ON "attachments" ("deleted_at");
+
+CREATE TABLE "invoices" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "invoice_number" text NOT NULL,
+  "booking_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "subtotal" bigint NOT NULL,
+  "discount_amount" bigint NOT NULL DEFAULT 0,
+  "tax_percent" decimal NOT NULL DEFAULT 0,
+  "tax_amount" bigint NOT NULL DEFAULT 0,
+  "total" bigint NOT NULL,
+  "status" text NOT NULL DEFAULT 'unpaid',
+  "issued_at" timestamptz NOT NULL,
+  "paid_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_invoices_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id"),
+  CONSTRAINT "fk_invoices_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+  CONSTRAINT "uni_invoices_invoice_number" UNIQUE ("invoice_number")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invoices_booking_id" ON "invoices" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_invoices_deleted_at" ON "invoices" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_invoices_status" ON "invoices" ("status");
+CREATE INDEX IF NOT EXISTS "idx_invoices_user_id" ON "invoices" ("user_id");
+
+CREATE TABLE "invoice_items" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "invoice_id" bigint NOT NULL,
+  "item_type" text NOT NULL,
+  "description" text NOT NULL,
+  "quantity" bigint NOT NULL,
+  "unit_price" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "created_by" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_invoices_items" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_invoice_items_invoice_id" ON "invoice_items" ("invoice_id");
+CREATE INDEX IF NOT EXISTS "idx_invoice_items_deleted_at" ON "invoice_items" ("deleted_at");
+
+CREATE TABLE "invoice_sequences" (
+  "period" varchar(6),
+  "last_number" bigint NOT NULL,
+  PRIMARY KEY ("period")
+);
+
+CREATE TABLE "payments" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "booking_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "method" text NOT NULL,
+  "provider" varchar(50) NOT NULL,
+  "provider_ref" varchar(100) NOT NULL,
+  "reference" text,
+  "status" text NOT NULL DEFAULT 'pending',
+  "va_number" text,
+  "redirect_url" text,
+  "expires_at" timestamptz,
+  "paid_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_payments_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_provider_ref" ON "payments" ("provider","provider_ref");
+CREATE INDEX IF NOT EXISTS "idx_payments_user_id" ON "payments" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_payments_booking_id" ON "payments" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_payments_deleted_at" ON "payments" ("deleted_at");
+
+CREATE TABLE "refunds" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "payment_id" bigint NOT NULL,
+  "booking_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "percent" bigint NOT NULL,
+  "reason" text,
+  "status" text NOT NULL DEFAULT 'pending_approval',
+  "refund_ref" text,
+  "approved_by" bigint,
+  "processed_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_refunds_payment" FOREIGN KEY ("payment_id") REFERENCES "payments"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_refunds_status" ON "refunds" ("status");
+CREATE INDEX IF NOT EXISTS "idx_refunds_booking_id" ON "refunds" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_refunds_payment_id" ON "refunds" ("payment_id");
+CREATE INDEX IF NOT EXISTS "idx_refunds_deleted_at" ON "refunds" ("deleted_at");
+
+CREATE TABLE "service_coverages" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "service_id" bigint NOT NULL,
+  "payer_type" text NOT NULL,
+  "insurer_name" text,
+  "coverage_percent" bigint NOT NULL,
+  "max_amount" bigint NOT NULL DEFAULT 0,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_service_coverages_service" FOREIGN KEY ("service_id") REFERENCES "services"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_coverages_service_id" ON "service_coverages" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_service_coverages_deleted_at" ON "service_coverages" ("deleted_at");
+
+CREATE TABLE "claim_batches" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "batch_number" text NOT NULL,
+  "payer_type" text NOT NULL,
+  "insurer_name" text,
+  "claim_count" bigint NOT NULL,
+  "total_amount" bigint NOT NULL,
+  "created_by" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "uni_claim_batches_batch_number" UNIQUE ("batch_number")
+);
+CREATE INDEX IF NOT EXISTS "idx_claim_batches_deleted_at" ON "claim_batches" ("deleted_at");
+
+CREATE TABLE "insurance_claims" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "booking_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "service_id" bigint NOT NULL,
+  "payer_type" text NOT NULL,
+  "insurer_name" text,
+  "policy_number" text NOT NULL,
+  "billed_amount" bigint NOT NULL,
+  "covered_amount" bigint NOT NULL,
+  "status" text NOT NULL DEFAULT 'draft',
+  "batch_id" bigint,
+  "rejection_reason" text,
+  "submitted_at" timestamptz,
+  "decided_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_insurance_claims_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id"),
+  CONSTRAINT "fk_claim_batches_claims" FOREIGN KEY ("batch_id") REFERENCES "claim_batches"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_insurance_claims_batch_id" ON "insurance_claims" ("batch_id");
+CREATE INDEX IF NOT EXISTS "idx_insurance_claims_status" ON "insurance_claims" ("status");
+CREATE INDEX IF NOT EXISTS "idx_insurance_claims_payer_type" ON "insurance_claims" ("payer_type");
+CREATE INDEX IF NOT EXISTS "idx_insurance_claims_user_id" ON "insurance_claims" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_insurance_claims_booking_id" ON "insurance_claims" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_insurance_claims_deleted_at" ON "insurance_claims" ("deleted_at");
+
+CREATE TABLE "promos" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "code" varchar(50) NOT NULL,
+  "description" text,
+  "discount_type" text NOT NULL,
+  "discount_value" bigint NOT NULL,
+  "valid_from" timestamptz NOT NULL,
+  "valid_until" timestamptz NOT NULL,
+  "service_id" bigint,
+  "doctor_id" bigint,
+  "max_uses" bigint NOT NULL DEFAULT 0,
+  "max_uses_per_patient" bigint NOT NULL DEFAULT 0,
+  "first_visit_only" boolean NOT NULL DEFAULT false,
+  "is_active" boolean NOT NULL,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "uni_promos_code" UNIQUE ("code")
+);
+CREATE INDEX IF NOT EXISTS "idx_promos_deleted_at" ON "promos" ("deleted_at");
+
+CREATE TABLE "promo_usages" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "promo_id" bigint NOT NULL,
+  "booking_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "discount_amount" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_promo_usages_promo" FOREIGN KEY ("promo_id") REFERENCES "promos"("id"),
+  CONSTRAINT "fk_promo_usages_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_promo_usages_user_id" ON "promo_usages" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_promo_usages_booking_id" ON "promo_usages" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_promo_usages_promo_id" ON "promo_usages" ("promo_id");
+CREATE INDEX IF NOT EXISTS "idx_promo_usages_deleted_at" ON "promo_usages" ("deleted_at");
+
+CREATE TABLE "service_prices" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "service_id" bigint NOT NULL,
+  "doctor_id" bigint,
+  "price" bigint NOT NULL,
+  "effective_from" timestamptz NOT NULL,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_service_prices_service" FOREIGN KEY ("service_id") REFERENCES "services"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_price_lookup" ON "service_prices" ("service_id","doctor_id","effective_from");
+CREATE INDEX IF NOT EXISTS "idx_service_prices_deleted_at" ON "service_prices" ("deleted_at");
+
+CREATE TABLE "license_alerts" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "doctor_id" bigint NOT NULL,
+  "license" varchar(3) NOT NULL,
+  "kind" varchar(10) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_license_alerts_doctor" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_license_alert" ON "license_alerts" ("doctor_id","license","kind","expires_at");
+CREATE INDEX IF NOT EXISTS "idx_license_alerts_deleted_at" ON "license_alerts" ("deleted_at");
+
+CREATE TABLE "reviews" (
+  "id" bigserial,
+  "created_at" timestamptz,
+  "updated_at" timestamptz,
+  "deleted_at" timestamptz,
+  "booking_id" bigint NOT NULL,
+  "doctor_id" bigint NOT NULL,
+  "user_id" bigint NOT NULL,
+  "rating" bigint NOT NULL,
+  "comment" text,
+  "is_hidden" boolean NOT NULL DEFAULT false,
+  "hidden_reason" text,
+  "hidden_by" bigint,
+  "reply" text,
+  "replied_at" timestamptz,
+  "created_by" bigint NOT NULL,
+  "updated_by" bigint,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_reviews_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+  CONSTRAINT "fk_reviews_doctor" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id"),
+  CONSTRAINT "fk_reviews_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_reviews_doctor_id" ON "reviews" ("doctor_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reviews_booking_id" ON "reviews" ("booking_id");
+CREATE INDEX IF NOT EXISTS "idx_reviews_deleted_at" ON "reviews" ("deleted_at");
+
+CREATE TABLE "clinic_opening_hours" (
+  "id" bigserial,
+  "clinic_id" bigint NOT NULL,
+  "weekday" bigint NOT NULL,
+  "opens_at" varchar(5) NOT NULL,
+  "closes_at" varchar(5) NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_clinics_opening_hours" FOREIGN KEY ("clinic_id") REFERENCES "clinics"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_clinic_opening_hours_clinic_id" ON "clinic_opening_hours" ("clinic_id");
+
+CREATE TABLE "doctor_services" (
+  "doctor_id" bigint,
+  "service_id" bigint,
+  PRIMARY KEY ("doctor_id","service_id"),
+  CONSTRAINT "fk_doctor_services_doctor" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id"),
+  CONSTRAINT "fk_doctor_services_service" FOREIGN KEY ("service_id") REFERENCES "services"("id")
+);
+
+CREATE TABLE "doctor_clinics" (
+  "doctor_id" bigint,
+  "clinic_id" bigint,
+  PRIMARY KEY ("doctor_id","clinic_id"),
+  CONSTRAINT "fk_doctor_clinics_clinic" FOREIGN KEY ("clinic_id") REFERENCES "clinics"("id"),
+  CONSTRAINT "fk_doctor_clinics_doctor" FOREIGN KEY ("doctor_id") REFERENCES "doctors"("id")
+);
+
+CREATE TABLE "service_resources" (
+  "service_id" bigint,
+  "resource_id" bigint,
+  PRIMARY KEY ("service_id","resource_id"),
+  CONSTRAINT "fk_service_resources_resource" FOREIGN KEY ("resource_id") REFERENCES "resources"("id"),
+  CONSTRAINT "fk_service_resources_service" FOREIGN KEY ("service_id") REFERENCES "services"("id")
+);
+
+CREATE TABLE "booking_resources" (
+  "booking_id" bigint,
+  "resource_id" bigint,
+  PRIMARY KEY ("booking_id","resource_id"),
+  CONSTRAINT "fk_booking_resources_booking" FOREIGN KEY ("booking_id") REFERENCES "bookings"("id"),
+  CONSTRAINT "fk_booking_resources_resource" FOREIGN KEY ("resource_id") REFERENCES "resources"("id")
+);
+
+-- Every record belongs to a clinic; new installations start with one.
+INSERT INTO "clinics" ("created_at", "updated_at", "name", "timezone", "is_active", "created_by") VALUES (NOW(), NOW(), 'Main Clinic', 'Asia/Jakarta', true, 0);
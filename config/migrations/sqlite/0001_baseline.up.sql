@@ -0,0 +1,491 @@
+-- Schema of the application when versioned migrations were introduced.
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text,
+  `email` text NOT NULL,
+  `password` text NOT NULL,
+  `role` text,
+  `clinic_id` integer,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+CREATE INDEX `idx_users_clinic_id` ON `users`(`clinic_id`);
+CREATE INDEX `idx_users_deleted_at` ON `users`(`deleted_at`);
+
+CREATE TABLE `doctors` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `user_id` integer NOT NULL,
+  `specialization` text NOT NULL,
+  `bio` text,
+  `photo_url` text,
+  `languages` text,
+  `education` text,
+  `practice_since` integer,
+  `str_number` text,
+  `str_expires_at` datetime,
+  `sip_number` text,
+  `sip_expires_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_doctors_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE UNIQUE INDEX `idx_user_id` ON `doctors`(`user_id`);
+CREATE INDEX `idx_doctors_deleted_at` ON `doctors`(`deleted_at`);
+
+CREATE TABLE `clinics` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text NOT NULL,
+  `address` text,
+  `phone` text,
+  `timezone` text NOT NULL DEFAULT "Asia/Jakarta",
+  `is_active` numeric NOT NULL DEFAULT true,
+  `created_by` integer NOT NULL,
+  `updated_by` integer
+);
+CREATE INDEX `idx_clinics_deleted_at` ON `clinics`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_clinics_name` ON `clinics`(`name`);
+
+CREATE TABLE `doctor_clinics` (
+  `clinic_id` integer,
+  `doctor_id` integer,
+  PRIMARY KEY (`clinic_id`,`doctor_id`),
+  CONSTRAINT `fk_doctor_clinics_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_doctor_clinics_clinic` FOREIGN KEY (`clinic_id`) REFERENCES `clinics`(`id`)
+);
+
+CREATE TABLE `services` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text NOT NULL,
+  `description` text,
+  `price` integer NOT NULL,
+  `duration_minutes` integer NOT NULL,
+  `is_active` numeric NOT NULL,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  `clinic_id` integer,
+  CONSTRAINT `uni_services_name` UNIQUE (`name`)
+);
+CREATE INDEX `idx_services_clinic_id` ON `services`(`clinic_id`);
+CREATE INDEX `idx_services_deleted_at` ON `services`(`deleted_at`);
+
+CREATE TABLE `doctor_services` (
+  `service_id` integer,
+  `doctor_id` integer,
+  PRIMARY KEY (`service_id`,`doctor_id`),
+  CONSTRAINT `fk_doctor_services_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`),
+  CONSTRAINT `fk_doctor_services_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+
+CREATE TABLE `bookings` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `user_id` integer NOT NULL,
+  `doctor_id` integer NOT NULL,
+  `service_id` integer NOT NULL,
+  `clinic_id` integer,
+  `start_at` datetime NOT NULL,
+  `end_at` datetime NOT NULL,
+  `status` text NOT NULL DEFAULT "pending",
+  `notes` text,
+  `payer_type` text NOT NULL DEFAULT "self_pay",
+  `insurer_name` text,
+  `policy_number` text,
+  `price` integer NOT NULL DEFAULT 0,
+  `discount_amount` integer NOT NULL DEFAULT 0,
+  `final_price` integer NOT NULL DEFAULT 0,
+  `promo_code` text,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_users_booking` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_doctors_bookings` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_services_bookings` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+CREATE INDEX `idx_bookings_clinic_id` ON `bookings`(`clinic_id`);
+CREATE INDEX `idx_booking_doctor_interval` ON `bookings`(`doctor_id`,`start_at`,`end_at`);
+CREATE INDEX `idx_bookings_deleted_at` ON `bookings`(`deleted_at`);
+
+CREATE TABLE `resources` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `name` text NOT NULL,
+  `type` text NOT NULL,
+  `description` text,
+  `is_active` numeric NOT NULL DEFAULT true,
+  `clinic_id` integer,
+  `created_by` integer NOT NULL,
+  `updated_by` integer
+);
+CREATE INDEX `idx_resources_type` ON `resources`(`type`);
+CREATE INDEX `idx_resources_deleted_at` ON `resources`(`deleted_at`);
+CREATE INDEX `idx_resources_clinic_id` ON `resources`(`clinic_id`);
+
+CREATE TABLE `booking_resources` (
+  `booking_id` integer,
+  `resource_id` integer,
+  PRIMARY KEY (`booking_id`,`resource_id`),
+  CONSTRAINT `fk_booking_resources_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_booking_resources_resource` FOREIGN KEY (`resource_id`) REFERENCES `resources`(`id`)
+);
+
+CREATE TABLE `service_resources` (
+  `resource_id` integer,
+  `service_id` integer,
+  PRIMARY KEY (`resource_id`,`service_id`),
+  CONSTRAINT `fk_service_resources_resource` FOREIGN KEY (`resource_id`) REFERENCES `resources`(`id`),
+  CONSTRAINT `fk_service_resources_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+
+CREATE TABLE `doctor_schedules` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `doctor_id` integer NOT NULL,
+  `service_id` integer NOT NULL,
+  `date` datetime NOT NULL,
+  `start_time` datetime NOT NULL,
+  `end_time` datetime NOT NULL,
+  `room_id` integer,
+  `clinic_id` integer,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_services_schedules` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`),
+  CONSTRAINT `fk_doctor_schedules_room` FOREIGN KEY (`room_id`) REFERENCES `resources`(`id`),
+  CONSTRAINT `fk_doctors_schedules` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+CREATE INDEX `idx_doctor_schedules_clinic_id` ON `doctor_schedules`(`clinic_id`);
+CREATE INDEX `idx_doctor_schedules_room_id` ON `doctor_schedules`(`room_id`);
+CREATE INDEX `idx_schedule_service_date` ON `doctor_schedules`(`service_id`,`date`);
+CREATE INDEX `idx_doctor_schedules_deleted_at` ON `doctor_schedules`(`deleted_at`);
+
+CREATE TABLE `vital_signs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `booking_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `systolic` integer NOT NULL,
+  `diastolic` integer NOT NULL,
+  `temperature` real NOT NULL,
+  `weight_kg` real NOT NULL,
+  `height_cm` real NOT NULL,
+  `pulse` integer NOT NULL,
+  `sp_o2` integer NOT NULL,
+  `bmi` real NOT NULL,
+  `flags` text,
+  `recorded_at` datetime NOT NULL,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_vital_signs_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_vital_signs_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`)
+);
+CREATE INDEX `idx_vital_signs_user_id` ON `vital_signs`(`user_id`);
+CREATE INDEX `idx_vital_signs_booking_id` ON `vital_signs`(`booking_id`);
+CREATE INDEX `idx_vital_signs_deleted_at` ON `vital_signs`(`deleted_at`);
+
+CREATE TABLE `attachments` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `booking_id` integer NOT NULL,
+  `file_name` text NOT NULL,
+  `content_type` text NOT NULL,
+  `size` integer NOT NULL,
+  `storage_key` text NOT NULL,
+  `category` text NOT NULL DEFAULT "other",
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_attachments_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `uni_attachments_storage_key` UNIQUE (`storage_key`)
+);
+CREATE INDEX `idx_attachments_booking_id` ON `attachments`(`booking_id`);
+CREATE INDEX `idx_attachments_deleted_at` ON `attachments`(`deleted_at`);
+
+CREATE TABLE `invoices` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `invoice_number` text NOT NULL,
+  `booking_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `subtotal` integer NOT NULL,
+  `discount_amount` integer NOT NULL DEFAULT 0,
+  `tax_percent` real NOT NULL DEFAULT 0,
+  `tax_amount` integer NOT NULL DEFAULT 0,
+  `total` integer NOT NULL,
+  `status` text NOT NULL DEFAULT "unpaid",
+  `issued_at` datetime NOT NULL,
+  `paid_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_invoices_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`),
+  CONSTRAINT `fk_invoices_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `uni_invoices_invoice_number` UNIQUE (`invoice_number`)
+);
+CREATE UNIQUE INDEX `idx_invoices_booking_id` ON `invoices`(`booking_id`);
+CREATE INDEX `idx_invoices_deleted_at` ON `invoices`(`deleted_at`);
+CREATE INDEX `idx_invoices_status` ON `invoices`(`status`);
+CREATE INDEX `idx_invoices_user_id` ON `invoices`(`user_id`);
+
+CREATE TABLE `invoice_items` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `invoice_id` integer NOT NULL,
+  `item_type` text NOT NULL,
+  `description` text NOT NULL,
+  `quantity` integer NOT NULL,
+  `unit_price` integer NOT NULL,
+  `amount` integer NOT NULL,
+  `created_by` integer NOT NULL,
+  CONSTRAINT `fk_invoices_items` FOREIGN KEY (`invoice_id`) REFERENCES `invoices`(`id`)
+);
+CREATE INDEX `idx_invoice_items_invoice_id` ON `invoice_items`(`invoice_id`);
+CREATE INDEX `idx_invoice_items_deleted_at` ON `invoice_items`(`deleted_at`);
+
+CREATE TABLE `invoice_sequences` (
+  `period` text,
+  `last_number` integer NOT NULL,
+  PRIMARY KEY (`period`)
+);
+
+CREATE TABLE `payments` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `booking_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `amount` integer NOT NULL,
+  `method` text NOT NULL,
+  `provider` text NOT NULL,
+  `provider_ref` text NOT NULL,
+  `reference` text,
+  `status` text NOT NULL DEFAULT "pending",
+  `va_number` text,
+  `redirect_url` text,
+  `expires_at` datetime,
+  `paid_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_payments_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+CREATE INDEX `idx_payments_user_id` ON `payments`(`user_id`);
+CREATE INDEX `idx_payments_booking_id` ON `payments`(`booking_id`);
+CREATE INDEX `idx_payments_deleted_at` ON `payments`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_provider_ref` ON `payments`(`provider`,`provider_ref`);
+
+CREATE TABLE `refunds` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `payment_id` integer NOT NULL,
+  `booking_id` integer NOT NULL,
+  `amount` integer NOT NULL,
+  `percent` integer NOT NULL,
+  `reason` text,
+  `status` text NOT NULL DEFAULT "pending_approval",
+  `refund_ref` text,
+  `approved_by` integer,
+  `processed_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_refunds_payment` FOREIGN KEY (`payment_id`) REFERENCES `payments`(`id`)
+);
+CREATE INDEX `idx_refunds_payment_id` ON `refunds`(`payment_id`);
+CREATE INDEX `idx_refunds_deleted_at` ON `refunds`(`deleted_at`);
+CREATE INDEX `idx_refunds_status` ON `refunds`(`status`);
+CREATE INDEX `idx_refunds_booking_id` ON `refunds`(`booking_id`);
+
+CREATE TABLE `service_coverages` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `service_id` integer NOT NULL,
+  `payer_type` text NOT NULL,
+  `insurer_name` text,
+  `coverage_percent` integer NOT NULL,
+  `max_amount` integer NOT NULL DEFAULT 0,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_service_coverages_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+CREATE INDEX `idx_service_coverages_service_id` ON `service_coverages`(`service_id`);
+CREATE INDEX `idx_service_coverages_deleted_at` ON `service_coverages`(`deleted_at`);
+
+CREATE TABLE `claim_batches` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `batch_number` text NOT NULL,
+  `payer_type` text NOT NULL,
+  `insurer_name` text,
+  `claim_count` integer NOT NULL,
+  `total_amount` integer NOT NULL,
+  `created_by` integer NOT NULL,
+  CONSTRAINT `uni_claim_batches_batch_number` UNIQUE (`batch_number`)
+);
+CREATE INDEX `idx_claim_batches_deleted_at` ON `claim_batches`(`deleted_at`);
+
+CREATE TABLE `insurance_claims` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `booking_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `service_id` integer NOT NULL,
+  `payer_type` text NOT NULL,
+  `insurer_name` text,
+  `policy_number` text NOT NULL,
+  `billed_amount` integer NOT NULL,
+  `covered_amount` integer NOT NULL,
+  `status` text NOT NULL DEFAULT "draft",
+  `batch_id` integer,
+  `rejection_reason` text,
+  `submitted_at` datetime,
+  `decided_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_claim_batches_claims` FOREIGN KEY (`batch_id`) REFERENCES `claim_batches`(`id`),
+  CONSTRAINT `fk_insurance_claims_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+CREATE INDEX `idx_insurance_claims_batch_id` ON `insurance_claims`(`batch_id`);
+CREATE INDEX `idx_insurance_claims_status` ON `insurance_claims`(`status`);
+CREATE INDEX `idx_insurance_claims_payer_type` ON `insurance_claims`(`payer_type`);
+CREATE INDEX `idx_insurance_claims_user_id` ON `insurance_claims`(`user_id`);
+CREATE UNIQUE INDEX `idx_insurance_claims_booking_id` ON `insurance_claims`(`booking_id`);
+CREATE INDEX `idx_insurance_claims_deleted_at` ON `insurance_claims`(`deleted_at`);
+
+CREATE TABLE `promos` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `code` text NOT NULL,
+  `description` text,
+  `discount_type` text NOT NULL,
+  `discount_value` integer NOT NULL,
+  `valid_from` datetime NOT NULL,
+  `valid_until` datetime NOT NULL,
+  `service_id` integer,
+  `doctor_id` integer,
+  `max_uses` integer NOT NULL DEFAULT 0,
+  `max_uses_per_patient` integer NOT NULL DEFAULT 0,
+  `first_visit_only` numeric NOT NULL DEFAULT false,
+  `is_active` numeric NOT NULL,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `uni_promos_code` UNIQUE (`code`)
+);
+CREATE INDEX `idx_promos_deleted_at` ON `promos`(`deleted_at`);
+
+CREATE TABLE `promo_usages` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `promo_id` integer NOT NULL,
+  `booking_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `discount_amount` integer NOT NULL,
+  CONSTRAINT `fk_promo_usages_promo` FOREIGN KEY (`promo_id`) REFERENCES `promos`(`id`),
+  CONSTRAINT `fk_promo_usages_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+CREATE INDEX `idx_promo_usages_user_id` ON `promo_usages`(`user_id`);
+CREATE UNIQUE INDEX `idx_promo_usages_booking_id` ON `promo_usages`(`booking_id`);
+CREATE INDEX `idx_promo_usages_promo_id` ON `promo_usages`(`promo_id`);
+CREATE INDEX `idx_promo_usages_deleted_at` ON `promo_usages`(`deleted_at`);
+
+CREATE TABLE `service_prices` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `service_id` integer NOT NULL,
+  `doctor_id` integer,
+  `price` integer NOT NULL,
+  `effective_from` datetime NOT NULL,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_service_prices_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`)
+);
+CREATE INDEX `idx_service_price_lookup` ON `service_prices`(`service_id`,`doctor_id`,`effective_from`);
+CREATE INDEX `idx_service_prices_deleted_at` ON `service_prices`(`deleted_at`);
+
+CREATE TABLE `license_alerts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `doctor_id` integer NOT NULL,
+  `license` text NOT NULL,
+  `kind` text NOT NULL,
+  `expires_at` datetime NOT NULL,
+  CONSTRAINT `fk_license_alerts_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`)
+);
+CREATE UNIQUE INDEX `idx_license_alert` ON `license_alerts`(`doctor_id`,`license`,`kind`,`expires_at`);
+CREATE INDEX `idx_license_alerts_deleted_at` ON `license_alerts`(`deleted_at`);
+
+CREATE TABLE `reviews` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `deleted_at` datetime,
+  `booking_id` integer NOT NULL,
+  `doctor_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `rating` integer NOT NULL,
+  `comment` text,
+  `is_hidden` numeric NOT NULL DEFAULT false,
+  `hidden_reason` text,
+  `hidden_by` integer,
+  `reply` text,
+  `replied_at` datetime,
+  `created_by` integer NOT NULL,
+  `updated_by` integer,
+  CONSTRAINT `fk_reviews_user` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`),
+  CONSTRAINT `fk_reviews_doctor` FOREIGN KEY (`doctor_id`) REFERENCES `doctors`(`id`),
+  CONSTRAINT `fk_reviews_booking` FOREIGN KEY (`booking_id`) REFERENCES `bookings`(`id`)
+);
+CREATE UNIQUE INDEX `idx_reviews_booking_id` ON `reviews`(`booking_id`);
+CREATE INDEX `idx_reviews_deleted_at` ON `reviews`(`deleted_at`);
+CREATE INDEX `idx_reviews_doctor_id` ON `reviews`(`doctor_id`);
+
+CREATE TABLE `clinic_opening_hours` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `clinic_id` integer NOT NULL,
+  `weekday` integer NOT NULL,
+  `opens_at` text NOT NULL,
+  `closes_at` text NOT NULL,
+  CONSTRAINT `fk_clinics_opening_hours` FOREIGN KEY (`clinic_id`) REFERENCES `clinics`(`id`)
+);
+CREATE INDEX `idx_clinic_opening_hours_clinic_id` ON `clinic_opening_hours`(`clinic_id`);
+
+-- Every record belongs to a clinic; new installations start with one.
+INSERT INTO `clinics` (`created_at`, `updated_at`, `name`, `timezone`, `is_active`, `created_by`) VALUES (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'), 'Main Clinic', 'Asia/Jakarta', true, 0);
@@ -0,0 +1 @@
+-- Admins keep the clinic they were given; a super admin has to move them.
@@ -0,0 +1,4 @@
+-- Admins are limited to the clinic they are assigned to and refused without
+-- one. Admins created before that rule get the first clinic.
+UPDATE users SET clinic_id = (SELECT MIN(id) FROM clinics WHERE deleted_at IS NULL)
+WHERE role = 'admin' AND clinic_id IS NULL AND deleted_at IS NULL;
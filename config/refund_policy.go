@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RefundRule grants Percent of the paid amount back when a booking is
+// cancelled at least MinHoursBefore hours before it starts.
+type RefundRule struct {
+	MinHoursBefore int
+	Percent        int
+}
+
+// ParseRefundPolicy reads rules written as "hours:percent" pairs separated by
+// commas, e.g. "24:100,0:50". Cancellations not covered by any rule, including
+// no-shows, are not refunded.
+func ParseRefundPolicy(policy string) ([]RefundRule, error) {
+	var rules []RefundRule
+	for _, part := range strings.Split(policy, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		hoursStr, percentStr, found := strings.Cut(part, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid refund rule %q", part)
+		}
+		hours, err := strconv.Atoi(strings.TrimSpace(hoursStr))
+		if err != nil || hours < 0 {
+			return nil, fmt.Errorf("invalid hours in refund rule %q", part)
+		}
+		percent, err := strconv.Atoi(strings.TrimSpace(percentStr))
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid percent in refund rule %q", part)
+		}
+		rules = append(rules, RefundRule{MinHoursBefore: hours, Percent: percent})
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].MinHoursBefore > rules[j].MinHoursBefore })
+	return rules, nil
+}
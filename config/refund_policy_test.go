@@ -0,0 +1,33 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRefundPolicy(t *testing.T) {
+	tests := []struct {
+		policy  string
+		want    []RefundRule
+		wantErr bool
+	}{
+		{"24:100,0:50", []RefundRule{{24, 100}, {0, 50}}, false},
+		{" 0:50 , 48:100, 24:75 ", []RefundRule{{48, 100}, {24, 75}, {0, 50}}, false},
+		{"", nil, false},
+		{"24", nil, true},
+		{"-1:50", nil, true},
+		{"24:101", nil, true},
+		{"day:50", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got, err := ParseRefundPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRefundPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRefundPolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentController struct {
+	AttachmentService services.AttachmentService
+}
+
+func (ac *AttachmentController) UploadAttachment(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	// Leave some room for the multipart envelope on top of the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAttachmentSize+(1<<20))
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required and must not exceed the size limit"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	attachment, err := ac.AttachmentService.UploadAttachment(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID, fileHeader.Filename, fileHeader.Size, c.PostForm("category"), file)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment uploaded successfully", "attachment": toAttachmentResponse(*attachment)})
+}
+
+func (ac *AttachmentController) GetAttachmentsByBookingId(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	attachments, err := ac.AttachmentService.GetAttachmentsByBookingId(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	var attachmentResponses []model.AttachmentResponse
+	for _, attachment := range attachments {
+		attachmentResponses = append(attachmentResponses, toAttachmentResponse(attachment))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachmentResponses})
+}
+
+func (ac *AttachmentController) DownloadAttachment(c *gin.Context) {
+	bookingIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	attachmentIdUint, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	attachment, file, err := ac.AttachmentService.DownloadAttachment(c.Request.Context(), uint(bookingIdUint), uint(attachmentIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+	defer file.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, file, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("attachment; filename=%q", attachment.FileName),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	})
+}
+
+func (ac *AttachmentController) DeleteAttachment(c *gin.Context) {
+	bookingIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	attachmentIdUint, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	if err := ac.AttachmentService.DeleteAttachment(c.Request.Context(), uint(bookingIdUint), uint(attachmentIdUint), userID, userRole, clinicID); err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
+
+func toAttachmentResponse(attachment model.Attachment) model.AttachmentResponse {
+	return model.AttachmentResponse{
+		ID:          attachment.ID,
+		BookingID:   attachment.BookingId,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		Category:    attachment.Category,
+		UploadedBy:  attachment.CreatedBy,
+		UploadedAt:  attachment.CreatedAt,
+	}
+}
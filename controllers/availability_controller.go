@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityController struct {
+	AvailabilityService services.AvailabilityService
+	ClinicService       services.ClinicService
+}
+
+func (ac *AvailabilityController) GetEarliestAvailability(c *gin.Context) {
+	var serviceID uint64
+	if serviceIDStr := c.Query("service_id"); serviceIDStr != "" {
+		var err error
+		if serviceID, err = strconv.ParseUint(serviceIDStr, 10, 32); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+	}
+	specialization := c.Query("specialization")
+	if serviceID == 0 && specialization == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "service_id or specialization is required"})
+		return
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	// from is an RFC 3339 timestamp or a date, which starts at midnight in the
+	// clinic's timezone (the default one when searching all clinics)
+	from := time.Now()
+	if fromStr := c.Query("from"); fromStr != "" {
+		fromTime, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			loc := utils.DefaultLocation()
+			if clinicID != 0 {
+				if loc, err = ac.ClinicService.GetLocation(c.Request.Context(), clinicID); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+			}
+			if fromTime, err = time.ParseInLocation("2006-01-02", fromStr, loc); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date format"})
+				return
+			}
+		}
+		if fromTime.After(from) {
+			from = fromTime
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil || limit <= 0 {
+		limit = 5
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	slots, err := ac.AvailabilityService.FindEarliestSlots(c.Request.Context(), uint(serviceID), specialization, clinicID, from, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"slots": slots})
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"booking-klinik/model"
 	"booking-klinik/services"
+	"booking-klinik/utils"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,74 +12,99 @@ import (
 )
 
 type BookingController struct {
-	BookingService services.BookingService
-	DoctorService  services.DoctorServices
-	UserService    services.UserService
-	ServiceService services.ServiceService
-	DoctorSchedule services.DoctorScheduleService
+	BookingService   services.BookingService
+	DoctorService    services.DoctorServices
+	UserService      services.UserService
+	ServiceService   services.ServiceService
+	DoctorSchedule   services.DoctorScheduleService
+	VitalSignService services.VitalSignService
+	ClinicService    services.ClinicService
 }
 
 func (bc *BookingController) CreateBooking(c *gin.Context) {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	time.Local = loc
 	var bookingRequest model.BookingRequest
 	if err := c.ShouldBindJSON(&bookingRequest); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	bookingDate, err := time.ParseInLocation("2006-01-02", bookingRequest.BookingDate, loc)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+	// start_at (or booking_time) is an RFC 3339 timestamp; booking_time may
+	// instead be a wall-clock time on booking_date in the clinic's timezone
+	startAt := bookingRequest.StartAt
+	if startAt == "" {
+		startAt = bookingRequest.BookingTime
+	}
+	bookingTime, err := time.Parse(time.RFC3339, startAt)
+	if err != nil && bookingRequest.StartAt != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start_at format"})
 		return
 	}
-
-	bookingTime, err := time.ParseInLocation("2006-01-02 15:04", bookingRequest.BookingDate+" "+bookingRequest.BookingTime, loc)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time format"})
-		return
+		if _, err := utils.ParseDate(bookingRequest.BookingDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+			return
+		}
+		loc, err := bc.ClinicService.ResolveLocation(c.Request.Context(), bookingRequest.ClinicID, bookingRequest.DoctorId)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		bookingTime, err = utils.ParseDateTime(bookingRequest.BookingDate, bookingRequest.BookingTime, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid time format"})
+			return
+		}
 	}
 	userID := c.MustGet("userID").(uint)
 
 	newBooking := model.Booking{
-		UserId:      userID,
-		DoctorId:    bookingRequest.DoctorId,
-		ServiceId:   bookingRequest.ServiceId,
-		BookingDate: bookingDate,
-		BookingTime: bookingTime,
-		Status:      "pending",
-		Notes:       bookingRequest.Notes,
-		CreatedBy:   userID,
-		UpdatedBy:   userID,
-	}
-
-	doctor, err := bc.DoctorService.GetDoctorById(bookingRequest.DoctorId)
+		UserId:       userID,
+		DoctorId:     bookingRequest.DoctorId,
+		ServiceId:    bookingRequest.ServiceId,
+		StartAt:      bookingTime.UTC(),
+		Status:       "pending",
+		Notes:        bookingRequest.Notes,
+		PayerType:    bookingRequest.PayerType,
+		PromoCode:    bookingRequest.PromoCode,
+		InsurerName:  bookingRequest.InsurerName,
+		PolicyNumber: bookingRequest.PolicyNumber,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+	}
+
+	doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), bookingRequest.DoctorId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := bc.UserService.GetUserById(doctor.UserId)
+	user, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	createdBooking, err := bc.BookingService.CreateBooking(newBooking)
+	createdBooking, err := bc.BookingService.CreateBooking(c.Request.Context(), newBooking)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	bookingResponse := model.BookingResponse{
-		ID:          createdBooking.ID,
-		PatientName: createdBooking.User.Name,
-		DoctorName:  user.Name,
-		ServiceName: createdBooking.Service.Name,
-		BookingDate: createdBooking.BookingDate,
-		BookingTime: createdBooking.BookingTime,
-		Status:      createdBooking.Status,
-		Notes:       createdBooking.Notes,
+		ID:             createdBooking.ID,
+		PatientName:    createdBooking.User.Name,
+		DoctorName:     user.Name,
+		ServiceName:    createdBooking.Service.Name,
+		StartAt:        clock.in(createdBooking.ClinicId, createdBooking.StartAt),
+		EndAt:          clock.in(createdBooking.ClinicId, createdBooking.EndAt),
+		Status:         createdBooking.Status,
+		Notes:          createdBooking.Notes,
+		PayerType:      createdBooking.PayerType,
+		Price:          createdBooking.Price,
+		DiscountAmount: createdBooking.DiscountAmount,
+		FinalPrice:     createdBooking.FinalPrice,
+		PromoCode:      createdBooking.PromoCode,
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Booking created successfully", "booking": bookingResponse})
@@ -103,36 +129,48 @@ func (bc *BookingController) GetAllBookings(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	bookings, pagination, err := bc.BookingService.GetAllBookings(limit, offset, userRole, userID)
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	bookings, pagination, err := bc.BookingService.GetAllBookings(c.Request.Context(), limit, offset, userRole, userID, clinicID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	var bookingResponses []model.BookingResponse
 	for _, booking := range bookings {
 
-		doctor, err := bc.DoctorService.GetDoctorById(booking.DoctorId)
+		doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), booking.DoctorId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch doctor details"})
 			return
 		}
 
 		// Ambil nama user (dokter) berdasarkan UserId dokter
-		doc, err := bc.UserService.GetUserById(doctor.UserId)
+		doc, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch doctor user details"})
 			return
 		}
 		bookingResponses = append(bookingResponses, model.BookingResponse{
-			ID:          booking.ID,
-			PatientName: booking.User.Name,
-			DoctorName:  doc.Name,
-			ServiceName: booking.Service.Name,
-			BookingDate: booking.BookingDate,
-			BookingTime: booking.BookingTime,
-			Status:      booking.Status,
-			Notes:       booking.Notes,
+			ID:             booking.ID,
+			PatientName:    booking.User.Name,
+			DoctorName:     doc.Name,
+			ServiceName:    booking.Service.Name,
+			StartAt:        clock.in(booking.ClinicId, booking.StartAt),
+			EndAt:          clock.in(booking.ClinicId, booking.EndAt),
+			Status:         booking.Status,
+			Notes:          booking.Notes,
+			PayerType:      booking.PayerType,
+			Price:          booking.Price,
+			DiscountAmount: booking.DiscountAmount,
+			FinalPrice:     booking.FinalPrice,
+			PromoCode:      booking.PromoCode,
 		})
 	}
 
@@ -154,33 +192,50 @@ func (bc *BookingController) GetBookingsById(c *gin.Context) {
 		return
 	}
 
-	booking, err := bc.BookingService.GetBookingById(uint(bookingIdUint), userID, userRole)
+	clinicID, err := callerClinic(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		clinicScopeError(c, err)
 		return
 	}
 
-	doctor, err := bc.DoctorService.GetDoctorById(booking.DoctorId)
+	booking, err := bc.BookingService.GetBookingById(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), booking.DoctorId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := bc.UserService.GetUserById(doctor.UserId)
+	user, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	bookingResponse := model.BookingResponse{
-		ID:          booking.ID,
-		PatientName: booking.User.Name,
-		DoctorName:  user.Name,
-		ServiceName: booking.Service.Name,
-		BookingDate: booking.BookingDate,
-		BookingTime: booking.BookingTime,
-		Status:      booking.Status,
-		Notes:       booking.Notes,
+		ID:             booking.ID,
+		PatientName:    booking.User.Name,
+		DoctorName:     user.Name,
+		ServiceName:    booking.Service.Name,
+		StartAt:        clock.in(booking.ClinicId, booking.StartAt),
+		EndAt:          clock.in(booking.ClinicId, booking.EndAt),
+		Status:         booking.Status,
+		Notes:          booking.Notes,
+		PayerType:      booking.PayerType,
+		Price:          booking.Price,
+		DiscountAmount: booking.DiscountAmount,
+		FinalPrice:     booking.FinalPrice,
+		PromoCode:      booking.PromoCode,
+	}
+
+	if vitalSign, err := bc.VitalSignService.GetLatestVitalSignByBookingId(c.Request.Context(), booking.ID); err == nil {
+		vitals := toVitalSignResponse(*vitalSign)
+		bookingResponse.Vitals = &vitals
 	}
 
 	c.JSON(http.StatusOK, gin.H{"booking": bookingResponse})
@@ -208,34 +263,47 @@ func (bc *BookingController) GetBookingsByUserId(c *gin.Context) {
 	}
 
 	offset := (page - 1) * limit
-	bookings, pagination, err := bc.BookingService.GetBookingsByUserId(uint(userIDUint), limit, offset)
+
+	clinicID, err := clinicScope(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		clinicScopeError(c, err)
 		return
 	}
 
+	bookings, pagination, err := bc.BookingService.GetBookingsByUserId(c.Request.Context(), uint(userIDUint), c.MustGet("userID").(uint), c.MustGet("role").(string), clinicID, limit, offset)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	var bookingResponses []model.BookingResponse
 	for _, booking := range bookings {
-		doctor, err := bc.DoctorService.GetDoctorById(booking.DoctorId)
+		doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), booking.DoctorId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		user, err := bc.UserService.GetUserById(doctor.UserId)
+		user, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 		bookingResponses = append(bookingResponses, model.BookingResponse{
-			ID:          booking.ID,
-			PatientName: booking.User.Name,
-			DoctorName:  user.Name,
-			ServiceName: booking.Service.Name,
-			BookingDate: booking.BookingDate,
-			BookingTime: booking.BookingTime,
-			Status:      booking.Status,
-			Notes:       booking.Notes,
+			ID:             booking.ID,
+			PatientName:    booking.User.Name,
+			DoctorName:     user.Name,
+			ServiceName:    booking.Service.Name,
+			StartAt:        clock.in(booking.ClinicId, booking.StartAt),
+			EndAt:          clock.in(booking.ClinicId, booking.EndAt),
+			Status:         booking.Status,
+			Notes:          booking.Notes,
+			PayerType:      booking.PayerType,
+			Price:          booking.Price,
+			DiscountAmount: booking.DiscountAmount,
+			FinalPrice:     booking.FinalPrice,
+			PromoCode:      booking.PromoCode,
 		})
 	}
 
@@ -265,34 +333,46 @@ func (bc *BookingController) GetBookingsByDoctorId(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	bookings, pagination, err := bc.BookingService.GetBookingsByDoctorId(uint(doctorIdUint), limit, offset)
+	clinicID, err := clinicScope(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		clinicScopeError(c, err)
+		return
+	}
+
+	bookings, pagination, err := bc.BookingService.GetBookingsByDoctorId(c.Request.Context(), uint(doctorIdUint), c.MustGet("userID").(uint), c.MustGet("role").(string), clinicID, limit, offset)
+	if err != nil {
+		serviceError(c, err)
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	var bookingResponses []model.BookingResponse
 	for _, booking := range bookings {
-		doctor, err := bc.DoctorService.GetDoctorById(booking.DoctorId)
+		doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), booking.DoctorId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 
-		user, err := bc.UserService.GetUserById(doctor.UserId)
+		user, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 		bookingResponses = append(bookingResponses, model.BookingResponse{
-			ID:          booking.ID,
-			PatientName: booking.User.Name,
-			DoctorName:  user.Name,
-			ServiceName: booking.Service.Name,
-			BookingDate: booking.BookingDate,
-			BookingTime: booking.BookingTime,
-			Status:      booking.Status,
-			Notes:       booking.Notes,
+			ID:             booking.ID,
+			PatientName:    booking.User.Name,
+			DoctorName:     user.Name,
+			ServiceName:    booking.Service.Name,
+			StartAt:        clock.in(booking.ClinicId, booking.StartAt),
+			EndAt:          clock.in(booking.ClinicId, booking.EndAt),
+			Status:         booking.Status,
+			Notes:          booking.Notes,
+			PayerType:      booking.PayerType,
+			Price:          booking.Price,
+			DiscountAmount: booking.DiscountAmount,
+			FinalPrice:     booking.FinalPrice,
+			PromoCode:      booking.PromoCode,
 		})
 	}
 
@@ -314,38 +394,51 @@ func (bc *BookingController) UpdateBooking(c *gin.Context) {
 	}
 
 	userRole := c.MustGet("role").(string)
+	userID := c.MustGet("userID").(uint)
 
-	updatedBooking, err := bc.BookingService.UpdateBooking(uint(bookingIdUint), model.Booking{
-		Notes:       updateRequest.Notes,
-		Status:      updateRequest.Status,
-		BookingDate: updateRequest.BookingDate,
-		BookingTime: updateRequest.BookingTime,
-	}, userRole)
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	updatedBooking, err := bc.BookingService.UpdateBooking(c.Request.Context(), uint(bookingIdUint), model.Booking{
+		UserId:  userID,
+		Notes:   updateRequest.Notes,
+		Status:  updateRequest.Status,
+		StartAt: updateRequest.StartAt.UTC(),
+	}, userRole, clinicID)
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		serviceError(c, err)
 		return
 	}
 
-	doctor, err := bc.DoctorService.GetDoctorById(updatedBooking.DoctorId)
+	doctor, err := bc.DoctorService.GetDoctorById(c.Request.Context(), updatedBooking.DoctorId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	user, err := bc.UserService.GetUserById(doctor.UserId)
+	user, err := bc.UserService.GetUserById(c.Request.Context(), doctor.UserId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), bc.ClinicService)
 	bookingResponse := model.BookingResponse{
-		ID:          updatedBooking.ID,
-		DoctorName:  user.Name,
-		BookingDate: updatedBooking.BookingDate,
-		BookingTime: updatedBooking.BookingTime,
-		Status:      updatedBooking.Status,
-		Notes:       updatedBooking.Notes,
+		ID:             updatedBooking.ID,
+		DoctorName:     user.Name,
+		StartAt:        clock.in(updatedBooking.ClinicId, updatedBooking.StartAt),
+		EndAt:          clock.in(updatedBooking.ClinicId, updatedBooking.EndAt),
+		Status:         updatedBooking.Status,
+		Notes:          updatedBooking.Notes,
+		PayerType:      updatedBooking.PayerType,
+		Price:          updatedBooking.Price,
+		DiscountAmount: updatedBooking.DiscountAmount,
+		FinalPrice:     updatedBooking.FinalPrice,
+		PromoCode:      updatedBooking.PromoCode,
 	}
 
 	c.JSON(http.StatusOK, gin.H{"booking": bookingResponse})
@@ -362,11 +455,51 @@ func (bc *BookingController) DeleteBooking(c *gin.Context) {
 	userRole := c.MustGet("role").(string)
 	userID := c.MustGet("userID").(uint)
 
-	err = bc.BookingService.DeleteBooking(uint(bookingIdUint), userRole, userID)
+	clinicID, err := callerClinic(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		clinicScopeError(c, err)
+		return
+	}
+
+	err = bc.BookingService.DeleteBooking(c.Request.Context(), uint(bookingIdUint), userRole, userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Booking deleted successfully"})
 }
+
+func (bc *BookingController) CancelBooking(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var cancelRequest model.CancelBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&cancelRequest); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userRole := c.MustGet("role").(string)
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	cancelledBooking, err := bc.BookingService.CancelBooking(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID, cancelRequest.Reason)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Booking cancelled successfully", "booking_id": cancelledBooking.ID, "status": cancelledBooking.Status})
+}
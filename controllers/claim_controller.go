@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClaimController struct {
+	ClaimService  services.ClaimService
+	ClinicService services.ClinicService
+}
+
+func (cc *ClaimController) GetClaims(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	claims, pagination, err := cc.ClaimService.GetClaims(c.Request.Context(), c.Query("status"), c.Query("payer_type"), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         claims,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (cc *ClaimController) UpdateClaimStatus(c *gin.Context) {
+	claimId := c.Param("id")
+	claimIdUint, err := strconv.ParseUint(claimId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid claim ID"})
+		return
+	}
+
+	var statusRequest model.ClaimStatusRequest
+	if err := c.ShouldBindJSON(&statusRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	claim, err := cc.ClaimService.UpdateClaimStatus(c.Request.Context(), uint(claimIdUint), statusRequest, userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Claim updated successfully", "claim": claim})
+}
+
+func (cc *ClaimController) CreateBatch(c *gin.Context) {
+	var batchRequest model.ClaimBatchRequest
+	if err := c.ShouldBindJSON(&batchRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	batch, err := cc.ClaimService.CreateBatch(c.Request.Context(), batchRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Claim batch created successfully", "batch": batch})
+}
+
+// ExportBatch returns the claims of a batch as CSV for submission to the payer.
+func (cc *ClaimController) ExportBatch(c *gin.Context) {
+	batchId := c.Param("id")
+	batchIdUint, err := strconv.ParseUint(batchId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	batch, err := cc.ClaimService.GetBatchById(c.Request.Context(), uint(batchIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The service date is the day of the appointment at its clinic
+	clock := newClinicClock(c.Request.Context(), cc.ClinicService)
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write([]string{"claim_id", "booking_id", "patient_name", "policy_number", "insurer_name", "service_name", "service_date", "billed_amount", "covered_amount"})
+	for _, claim := range batch.Claims {
+		writer.Write([]string{
+			strconv.FormatUint(uint64(claim.ID), 10),
+			strconv.FormatUint(uint64(claim.BookingId), 10),
+			claim.Booking.User.Name,
+			claim.PolicyNumber,
+			claim.InsurerName,
+			claim.Booking.Service.Name,
+			clock.in(claim.Booking.ClinicId, claim.Booking.StartAt).Format("2006-01-02"),
+			strconv.Itoa(claim.BilledAmount),
+			strconv.Itoa(claim.CoveredAmount),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", batch.BatchNumber+".csv"))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buffer.Bytes())
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClinicController struct {
+	ClinicService services.ClinicService
+}
+
+func (cc *ClinicController) CreateClinic(c *gin.Context) {
+	var clinicRequest model.ClinicRequest
+	if err := c.ShouldBindJSON(&clinicRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinic, err := cc.ClinicService.CreateClinic(c.Request.Context(), clinicRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Clinic created successfully", "clinic": toClinicResponse(*clinic)})
+}
+
+func (cc *ClinicController) GetAllClinics(c *gin.Context) {
+	cc.listClinics(c, false)
+}
+
+// GetActiveClinics is the public list of branches shown in the directory.
+func (cc *ClinicController) GetActiveClinics(c *gin.Context) {
+	cc.listClinics(c, true)
+}
+
+func (cc *ClinicController) listClinics(c *gin.Context, activeOnly bool) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clinics, pagination, err := cc.ClinicService.GetAllClinics(c.Request.Context(), activeOnly, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var clinicResponses []model.ClinicResponse
+	for _, clinic := range clinics {
+		clinicResponses = append(clinicResponses, toClinicResponse(clinic))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         clinicResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (cc *ClinicController) GetClinicById(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+
+	clinic, err := cc.ClinicService.GetClinicById(c.Request.Context(), uint(clinicIdUint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"clinic": toClinicResponse(*clinic)})
+}
+
+func (cc *ClinicController) UpdateClinic(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+	if err := checkClinicAccess(c, uint(clinicIdUint)); err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	var clinicRequest model.ClinicRequest
+	if err := c.ShouldBindJSON(&clinicRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinic, err := cc.ClinicService.UpdateClinic(c.Request.Context(), uint(clinicIdUint), clinicRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Clinic updated successfully", "clinic": toClinicResponse(*clinic)})
+}
+
+func (cc *ClinicController) DeleteClinic(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	if err := cc.ClinicService.DeleteClinic(c.Request.Context(), uint(clinicIdUint), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Clinic deleted successfully"})
+}
+
+func (cc *ClinicController) AddDoctor(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+	if err := checkClinicAccess(c, uint(clinicIdUint)); err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	var clinicDoctorRequest model.ClinicDoctorRequest
+	if err := c.ShouldBindJSON(&clinicDoctorRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cc.ClinicService.AddDoctor(c.Request.Context(), uint(clinicIdUint), clinicDoctorRequest.DoctorID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Doctor added to clinic successfully"})
+}
+
+func (cc *ClinicController) RemoveDoctor(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+	doctorIdUint, err := strconv.ParseUint(c.Param("doctor_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+	if err := checkClinicAccess(c, uint(clinicIdUint)); err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	if err := cc.ClinicService.RemoveDoctor(c.Request.Context(), uint(clinicIdUint), uint(doctorIdUint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Doctor removed from clinic successfully"})
+}
+
+func (cc *ClinicController) AssignAdmin(c *gin.Context) {
+	clinicIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid clinic ID"})
+		return
+	}
+
+	var clinicAdminRequest model.ClinicAdminRequest
+	if err := c.ShouldBindJSON(&clinicAdminRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cc.ClinicService.AssignAdmin(c.Request.Context(), uint(clinicIdUint), clinicAdminRequest.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Admin assigned to clinic successfully"})
+}
+
+func toClinicResponse(clinic model.Clinic) model.ClinicResponse {
+	return model.ClinicResponse{
+		ID:           clinic.ID,
+		Name:         clinic.Name,
+		Address:      clinic.Address,
+		Phone:        clinic.Phone,
+		Timezone:     clinic.Timezone,
+		IsActive:     clinic.IsActive,
+		OpeningHours: clinic.OpeningHours,
+	}
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"booking-klinik/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errOtherClinic = services.ErrOtherClinic
+	errNoClinic    = errors.New("your account is not assigned to a clinic")
+)
+
+// clinicScope returns the clinic a request is limited to, taken from the
+// clinic_id query parameter; 0 means all clinics. Admins other than super
+// admins are always limited to their branch, and refused without one.
+func clinicScope(c *gin.Context) (uint, error) {
+	var requested uint
+	if clinicIDStr := c.Query("clinic_id"); clinicIDStr != "" {
+		clinicID, err := strconv.ParseUint(clinicIDStr, 10, 32)
+		if err != nil {
+			return 0, errors.New("Invalid clinic ID")
+		}
+		requested = uint(clinicID)
+	}
+
+	if branchAdmin(c) {
+		ownClinic := c.GetUint("clinicID")
+		if ownClinic == 0 {
+			return 0, errNoClinic
+		}
+		if requested != 0 && requested != ownClinic {
+			return 0, errOtherClinic
+		}
+		return ownClinic, nil
+	}
+	return requested, nil
+}
+
+// checkClinicAccess fails when a branch admin touches another clinic.
+func checkClinicAccess(c *gin.Context, clinicID uint) error {
+	if !branchAdmin(c) {
+		return nil
+	}
+	switch ownClinic := c.GetUint("clinicID"); {
+	case ownClinic == 0:
+		return errNoClinic
+	case ownClinic != clinicID:
+		return errOtherClinic
+	}
+	return nil
+}
+
+// callerClinic returns the clinic a branch admin or a nurse is limited to, for
+// services to check single records against; 0 for every other caller.
+func callerClinic(c *gin.Context) (uint, error) {
+	if !branchAdmin(c) && c.GetString("role") != "nurse" {
+		return 0, nil
+	}
+	ownClinic := c.GetUint("clinicID")
+	if ownClinic == 0 {
+		return 0, errNoClinic
+	}
+	return ownClinic, nil
+}
+
+// branchAdmin reports whether the caller is an admin limited to one clinic.
+func branchAdmin(c *gin.Context) bool {
+	return c.GetString("role") == "admin" && !c.GetBool("superAdmin")
+}
+
+func clinicScopeError(c *gin.Context, err error) {
+	if errors.Is(err, errOtherClinic) || errors.Is(err, errNoClinic) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+// serviceError answers a failed service call, refusing records of another
+// clinic with 403.
+func serviceError(c *gin.Context, err error) {
+	if errors.Is(err, errOtherClinic) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
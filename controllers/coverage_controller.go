@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CoverageController struct {
+	CoverageService services.CoverageService
+}
+
+func (cc *CoverageController) CreateCoverage(c *gin.Context) {
+	serviceId := c.Param("id")
+	serviceIdUint, err := strconv.ParseUint(serviceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	var coverageRequest model.ServiceCoverageRequest
+	if err := c.ShouldBindJSON(&coverageRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	coverage, err := cc.CoverageService.CreateCoverage(c.Request.Context(), uint(serviceIdUint), coverageRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coverage created successfully", "coverage": coverage})
+}
+
+func (cc *CoverageController) GetCoveragesByServiceId(c *gin.Context) {
+	serviceId := c.Param("id")
+	serviceIdUint, err := strconv.ParseUint(serviceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	coverages, err := cc.CoverageService.GetCoveragesByServiceId(c.Request.Context(), uint(serviceIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"coverages": coverages})
+}
+
+func (cc *CoverageController) DeleteCoverage(c *gin.Context) {
+	coverageId := c.Param("coverage_id")
+	coverageIdUint, err := strconv.ParseUint(coverageId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid coverage ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	if err := cc.CoverageService.DeleteCoverage(c.Request.Context(), uint(coverageIdUint), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coverage deleted successfully"})
+}
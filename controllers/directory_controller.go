@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DirectoryController serves the public, unauthenticated doctor directory and
+// service catalogue.
+type DirectoryController struct {
+	DoctorService  services.DoctorServices
+	ServiceService services.ServiceService
+	ReviewService  services.ReviewService
+}
+
+func (dc *DirectoryController) GetDoctorDirectory(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var serviceID uint64
+	if serviceIDStr := c.Query("service_id"); serviceIDStr != "" {
+		serviceID, err = strconv.ParseUint(serviceIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	doctors, nextDates, pagination, err := dc.DoctorService.GetDoctorDirectory(c.Request.Context(), c.Query("specialization"), uint(serviceID), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	doctorIds := make([]uint, 0, len(doctors))
+	for _, doctor := range doctors {
+		doctorIds = append(doctorIds, doctor.ID)
+	}
+	ratings, err := dc.ReviewService.GetRatingSummaries(c.Request.Context(), doctorIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	doctorResponses := []model.DoctorProfileResponse{}
+	for _, doctor := range doctors {
+		doctorResponse := toDoctorProfileResponse(doctor)
+		if nextDate, ok := nextDates[doctor.ID]; ok {
+			doctorResponse.NextAvailable = nextDate.Format("2006-01-02")
+		}
+		if rating, ok := ratings[doctor.ID]; ok {
+			doctorResponse.AverageRating = math.Round(rating.AverageRating*10) / 10
+			doctorResponse.ReviewCount = rating.ReviewCount
+		}
+		doctorResponses = append(doctorResponses, doctorResponse)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         doctorResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (dc *DirectoryController) GetServiceCatalogue(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	services, nextDates, pagination, err := dc.ServiceService.GetServiceCatalogue(c.Request.Context(), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	serviceResponses := []model.CatalogueServiceResponse{}
+	for _, service := range services {
+		serviceResponse := model.CatalogueServiceResponse{
+			ID:              service.ID,
+			Name:            service.Name,
+			Description:     service.Description,
+			Price:           service.Price,
+			DurationMinutes: service.DurationMinutes,
+		}
+		if nextDate, ok := nextDates[service.ID]; ok {
+			serviceResponse.NextAvailable = nextDate.Format("2006-01-02")
+		}
+		serviceResponses = append(serviceResponses, serviceResponse)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         serviceResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
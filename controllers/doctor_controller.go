@@ -5,6 +5,8 @@ import (
 	"booking-klinik/services"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,16 +29,12 @@ func (dc *DoctorController) CreateDoctor(c *gin.Context) {
 	}
 	doctor.CreatedBy = userID.(uint)
 
-	createdDoctor, err := dc.DoctorService.CreateDoctor(&doctor)
+	createdDoctor, err := dc.DoctorService.CreateDoctor(c.Request.Context(), &doctor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	doctorResponse := model.DoctorResponse{
-		ID:             createdDoctor.ID,
-		Name:           createdDoctor.User.Name,
-		Specialization: createdDoctor.Specialization,
-	}
+	doctorResponse := toDoctorDetailResponse(*createdDoctor)
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor created successfully", "doctor": doctorResponse})
 }
 
@@ -55,7 +53,13 @@ func (dc *DoctorController) GetAllDoctors(c *gin.Context) {
 
 	offset := (page - 1) * limit
 
-	doctors, pagination, err := dc.DoctorService.GetAllDoctors(limit, offset)
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	doctors, pagination, err := dc.DoctorService.GetAllDoctors(c.Request.Context(), clinicID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -86,17 +90,13 @@ func (dc *DoctorController) GetDoctorById(c *gin.Context) {
 		return
 	}
 
-	doctor, err := dc.DoctorService.GetDoctorById(uint(doctorIdUint))
+	doctor, err := dc.DoctorService.GetDoctorById(c.Request.Context(), uint(doctorIdUint))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	doctorResponse := model.DoctorResponse{
-		ID:             doctor.ID,
-		Name:           doctor.User.Name,
-		Specialization: doctor.Specialization,
-	}
+	doctorResponse := toDoctorDetailResponse(*doctor)
 
 	c.JSON(http.StatusOK, gin.H{"doctor": doctorResponse})
 }
@@ -119,17 +119,13 @@ func (dc *DoctorController) UpdateDoctor(c *gin.Context) {
 	userID := c.MustGet("userID").(uint)
 	doctor.UpdatedBy = userID
 
-	updatedDoctor, err := dc.DoctorService.UpdateDoctor(uint(doctorIdUint), doctor)
+	updatedDoctor, err := dc.DoctorService.UpdateDoctor(c.Request.Context(), uint(doctorIdUint), doctor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	doctorResponse := model.DoctorResponse{
-		ID:             updatedDoctor.ID,
-		Name:           updatedDoctor.User.Name,
-		Specialization: updatedDoctor.Specialization,
-	}
+	doctorResponse := toDoctorDetailResponse(*updatedDoctor)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor updated successfully", "doctor": doctorResponse})
 }
@@ -143,7 +139,7 @@ func (dc *DoctorController) DeleteDoctor(c *gin.Context) {
 	}
 
 	userID := c.MustGet("userID").(uint)
-	err = dc.DoctorService.DeleteDoctor(uint(doctorIdUint), userID)
+	err = dc.DoctorService.DeleteDoctor(c.Request.Context(), uint(doctorIdUint), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -151,3 +147,110 @@ func (dc *DoctorController) DeleteDoctor(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor deleted successfully"})
 }
+
+func (dc *DoctorController) GetServicesByDoctorId(c *gin.Context) {
+	doctorId := c.Param("id")
+	doctorIdUint, err := strconv.ParseUint(doctorId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	services, err := dc.DoctorService.GetServicesByDoctorId(c.Request.Context(), uint(doctorIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var serviceResponses []model.ServiceResponse
+	for _, service := range services {
+		serviceResponses = append(serviceResponses, model.ServiceResponse{
+			ID:              service.ID,
+			Name:            service.Name,
+			Description:     service.Description,
+			Price:           service.Price,
+			DurationMinutes: service.DurationMinutes,
+			IsActive:        service.IsActive,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"services": serviceResponses})
+}
+
+func (dc *DoctorController) AddService(c *gin.Context) {
+	doctorId := c.Param("id")
+	doctorIdUint, err := strconv.ParseUint(doctorId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	var doctorServiceRequest model.DoctorServiceRequest
+	if err := c.ShouldBindJSON(&doctorServiceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := dc.DoctorService.AddService(c.Request.Context(), uint(doctorIdUint), doctorServiceRequest.ServiceID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Service added to doctor successfully"})
+}
+
+func (dc *DoctorController) RemoveService(c *gin.Context) {
+	doctorIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	serviceIdUint, err := strconv.ParseUint(c.Param("service_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	if err := dc.DoctorService.RemoveService(c.Request.Context(), uint(doctorIdUint), uint(serviceIdUint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Service removed from doctor successfully"})
+}
+
+func toDoctorProfileResponse(doctor model.Doctor) model.DoctorProfileResponse {
+	languages := []string{}
+	for _, language := range strings.Split(doctor.Languages, ",") {
+		if language = strings.TrimSpace(language); language != "" {
+			languages = append(languages, language)
+		}
+	}
+
+	yearsOfPractice := 0
+	if doctor.PracticeSince != 0 {
+		yearsOfPractice = time.Now().Year() - doctor.PracticeSince
+	}
+
+	return model.DoctorProfileResponse{
+		ID:              doctor.ID,
+		Name:            doctor.User.Name,
+		Specialization:  doctor.Specialization,
+		Bio:             doctor.Bio,
+		PhotoURL:        doctor.PhotoURL,
+		Languages:       languages,
+		Education:       doctor.Education,
+		YearsOfPractice: yearsOfPractice,
+	}
+}
+
+func toDoctorDetailResponse(doctor model.Doctor) model.DoctorDetailResponse {
+	return model.DoctorDetailResponse{
+		DoctorProfileResponse: toDoctorProfileResponse(doctor),
+		STRNumber:             doctor.STRNumber,
+		STRExpiresAt:          doctor.STRExpiresAt,
+		SIPNumber:             doctor.SIPNumber,
+		SIPExpiresAt:          doctor.SIPExpiresAt,
+	}
+}
@@ -13,11 +13,10 @@ import (
 
 type DoctorScheduleController struct {
 	DoctorScheduleService services.DoctorScheduleService
+	ClinicService         services.ClinicService
 }
 
 func (dsc *DoctorScheduleController) CreateDoctorSchedule(c *gin.Context) {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	time.Local = loc
 	var doctorScheduleRequest model.DoctorScheduleRequest
 
 	if err := c.ShouldBindJSON(&doctorScheduleRequest); err != nil {
@@ -25,19 +24,21 @@ func (dsc *DoctorScheduleController) CreateDoctorSchedule(c *gin.Context) {
 		return
 	}
 
-	date, err := time.ParseInLocation("2006-01-02", doctorScheduleRequest.Date, loc)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+	if doctorScheduleRequest.ClinicID == 0 {
+		doctorScheduleRequest.ClinicID = c.GetUint("clinicID")
+	}
+	if err := checkClinicAccess(c, doctorScheduleRequest.ClinicID); err != nil {
+		clinicScopeError(c, err)
 		return
 	}
-	startTime, err := time.ParseInLocation("2006-01-02 15:04", doctorScheduleRequest.Date+" "+doctorScheduleRequest.StartTime, loc)
+
+	loc, err := dsc.ClinicService.GetLocation(c.Request.Context(), doctorScheduleRequest.ClinicID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time format"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "clinic_id is required"})
 		return
 	}
-	endTime, err := time.ParseInLocation("2006-01-02 15:04", doctorScheduleRequest.Date+" "+doctorScheduleRequest.EndTime, loc)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end time format"})
+	date, startTime, endTime, ok := parseScheduleTimes(c, doctorScheduleRequest, loc)
+	if !ok {
 		return
 	}
 
@@ -49,13 +50,16 @@ func (dsc *DoctorScheduleController) CreateDoctorSchedule(c *gin.Context) {
 		Date:      date,
 		StartTime: startTime,
 		EndTime:   endTime,
+		RoomId:    doctorScheduleRequest.RoomID,
+		ClinicId:  doctorScheduleRequest.ClinicID,
 		CreatedBy: userID,
 	}
-	createdDoctorSchedule, err := dsc.DoctorScheduleService.CreateDoctorSchedule(schedule)
+	createdDoctorSchedule, err := dsc.DoctorScheduleService.CreateDoctorSchedule(c.Request.Context(), schedule)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	localizeSchedule(createdDoctorSchedule, loc)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor schedule created successfully", "doctorSchedule": createdDoctorSchedule})
 }
@@ -67,20 +71,28 @@ func (dsc *DoctorScheduleController) GetAllDoctorSchedules(c *gin.Context) {
 		return
 	}
 
-	doctorSchedules, err := dsc.DoctorScheduleService.GetAllDoctorSchedules(paginator.Limit, paginator.Offset)
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	doctorSchedules, err := dsc.DoctorScheduleService.GetAllDoctorSchedules(c.Request.Context(), clinicID, paginator.Limit, paginator.Offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	clock := newClinicClock(c.Request.Context(), dsc.ClinicService)
 	var doctorScheduleResponses []model.DoctorScheduleResponse
 	for _, doctorSchedule := range doctorSchedules {
 		doctorScheduleResponses = append(doctorScheduleResponses, model.DoctorScheduleResponse{
 			ID:        doctorSchedule.ID,
 			DoctorID:  doctorSchedule.Doctor.ID,
-			Date:      doctorSchedule.Date,
-			StartTime: doctorSchedule.StartTime,
-			EndTime:   doctorSchedule.EndTime,
+			ClinicID:  doctorSchedule.ClinicId,
+			Date:      doctorSchedule.Date.Format("2006-01-02"),
+			StartTime: clock.in(doctorSchedule.ClinicId, doctorSchedule.StartTime),
+			EndTime:   clock.in(doctorSchedule.ClinicId, doctorSchedule.EndTime),
 		})
 	}
 
@@ -95,18 +107,19 @@ func (dsc *DoctorScheduleController) GetDoctorScheduleById(c *gin.Context) {
 		return
 	}
 
-	doctorSchedule, err := dsc.DoctorScheduleService.GetDoctorScheduleById(uint(doctorScheduleIdUint))
+	doctorSchedule, err := dsc.DoctorScheduleService.GetDoctorScheduleById(c.Request.Context(), uint(doctorScheduleIdUint))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if loc, err := dsc.ClinicService.GetLocation(c.Request.Context(), doctorSchedule.ClinicId); err == nil {
+		localizeSchedule(doctorSchedule, loc)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"doctorSchedule": doctorSchedule})
 }
 
 func (dsc *DoctorScheduleController) UpdateDoctorSchedule(c *gin.Context) {
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	time.Local = loc
 	doctorScheduleId := c.Param("id")
 	doctorScheduleIdUint, err := strconv.ParseUint(doctorScheduleId, 10, 32)
 	if err != nil {
@@ -120,32 +133,34 @@ func (dsc *DoctorScheduleController) UpdateDoctorSchedule(c *gin.Context) {
 		return
 	}
 
-	date, err := time.ParseInLocation("2006-01-02", updateRequest.Date, loc)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format"})
+	existingSchedule, ok := dsc.checkScheduleAccess(c, uint(doctorScheduleIdUint))
+	if !ok {
 		return
 	}
-	startTime, err := time.ParseInLocation("2006-01-02 15:04", updateRequest.Date+" "+updateRequest.StartTime, loc)
+
+	// A schedule stays at its clinic, so times are read in that clinic's timezone
+	loc, err := dsc.ClinicService.GetLocation(c.Request.Context(), existingSchedule.ClinicId)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time format"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	endTime, err := time.ParseInLocation("2006-01-02 15:04", updateRequest.Date+" "+updateRequest.EndTime, loc)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end time format"})
+	date, startTime, endTime, ok := parseScheduleTimes(c, updateRequest, loc)
+	if !ok {
 		return
 	}
 
-	doctorSchedule, err := dsc.DoctorScheduleService.UpdateDoctorSchedule(uint(doctorScheduleIdUint), model.DoctorSchedule{
+	doctorSchedule, err := dsc.DoctorScheduleService.UpdateDoctorSchedule(c.Request.Context(), uint(doctorScheduleIdUint), model.DoctorSchedule{
 		Date:      date,
 		StartTime: startTime,
 		EndTime:   endTime,
+		RoomId:    updateRequest.RoomID,
 		UpdatedBy: c.MustGet("userID").(uint),
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	localizeSchedule(doctorSchedule, loc)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor schedule updated successfully", "doctorSchedule": doctorSchedule})
 }
@@ -159,7 +174,11 @@ func (dsc *DoctorScheduleController) DeleteDoctorSchedule(c *gin.Context) {
 		return
 	}
 
-	err = dsc.DoctorScheduleService.DeleteDoctorSchedule(uint(doctorScheduleIdUint), userID)
+	if _, ok := dsc.checkScheduleAccess(c, uint(doctorScheduleIdUint)); !ok {
+		return
+	}
+
+	err = dsc.DoctorScheduleService.DeleteDoctorSchedule(c.Request.Context(), uint(doctorScheduleIdUint), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -167,3 +186,40 @@ func (dsc *DoctorScheduleController) DeleteDoctorSchedule(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Doctor schedule deleted successfully"})
 }
+
+// checkScheduleAccess writes the error response and returns false when the
+// schedule is missing or belongs to another clinic than the admin's.
+func (dsc *DoctorScheduleController) checkScheduleAccess(c *gin.Context, scheduleID uint) (*model.DoctorSchedule, bool) {
+	doctorSchedule, err := dsc.DoctorScheduleService.GetDoctorScheduleById(c.Request.Context(), scheduleID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Doctor schedule not found"})
+		return nil, false
+	}
+	if err := checkClinicAccess(c, doctorSchedule.ClinicId); err != nil {
+		clinicScopeError(c, err)
+		return nil, false
+	}
+	return doctorSchedule, true
+}
+
+// parseScheduleTimes reads start_time and end_time as RFC 3339 timestamps, or
+// as HH:MM on date in the clinic's timezone. The schedule's date is the day it
+// starts on at the clinic.
+func parseScheduleTimes(c *gin.Context, request model.DoctorScheduleRequest, loc *time.Location) (time.Time, time.Time, time.Time, bool) {
+	startTime, err := utils.ParseDateTime(request.Date, request.StartTime, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start time format"})
+		return time.Time{}, time.Time{}, time.Time{}, false
+	}
+	endTime, err := utils.ParseDateTime(request.Date, request.EndTime, loc)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end time format"})
+		return time.Time{}, time.Time{}, time.Time{}, false
+	}
+	return utils.DateIn(startTime, loc), startTime, endTime, true
+}
+
+func localizeSchedule(doctorSchedule *model.DoctorSchedule, loc *time.Location) {
+	doctorSchedule.StartTime = doctorSchedule.StartTime.In(loc)
+	doctorSchedule.EndTime = doctorSchedule.EndTime.In(loc)
+}
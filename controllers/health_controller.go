@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"booking-klinik/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	HealthService services.HealthService
+}
+
+// Liveness only shows the process is serving requests; it does not touch the
+// database, so a database outage does not get the app restarted.
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readiness answers 503 while the database is unreachable or its schema does
+// not match this build, so no traffic is sent until it is fixed.
+func (hc *HealthController) Readiness(c *gin.Context) {
+	readiness, err := hc.HealthService.CheckReadiness(c.Request.Context())
+	if err != nil {
+		readiness.Error = err.Error()
+		c.JSON(http.StatusServiceUnavailable, readiness)
+		return
+	}
+	c.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceController struct {
+	InvoiceService services.InvoiceService
+}
+
+func (ic *InvoiceController) GetInvoices(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	invoices, pagination, err := ic.InvoiceService.GetInvoices(c.Request.Context(), userID, userRole, c.Query("status"), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         invoices,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (ic *InvoiceController) GetInvoiceById(c *gin.Context) {
+	invoiceId := c.Param("id")
+	invoiceIdUint, err := strconv.ParseUint(invoiceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	invoice, err := ic.InvoiceService.GetInvoiceById(c.Request.Context(), uint(invoiceIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invoice": invoice})
+}
+
+func (ic *InvoiceController) AddInvoiceItem(c *gin.Context) {
+	invoiceId := c.Param("id")
+	invoiceIdUint, err := strconv.ParseUint(invoiceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	var itemRequest model.InvoiceItemRequest
+	if err := c.ShouldBindJSON(&itemRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	invoice, err := ic.InvoiceService.AddInvoiceItem(c.Request.Context(), uint(invoiceIdUint), itemRequest, userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice item added successfully", "invoice": invoice})
+}
+
+func (ic *InvoiceController) UpdateInvoice(c *gin.Context) {
+	invoiceId := c.Param("id")
+	invoiceIdUint, err := strconv.ParseUint(invoiceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+
+	var updateRequest model.InvoiceUpdateRequest
+	if err := c.ShouldBindJSON(&updateRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	invoice, err := ic.InvoiceService.UpdateInvoice(c.Request.Context(), uint(invoiceIdUint), updateRequest, userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invoice updated successfully", "invoice": invoice})
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LicenseController struct {
+	LicenseService services.LicenseService
+}
+
+func (lc *LicenseController) GetLicenseAlerts(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alerts, pagination, err := lc.LicenseService.GetOpenLicenseAlerts(c.Request.Context(), paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var alertResponses []model.LicenseAlertResponse
+	for _, alert := range alerts {
+		alertResponses = append(alertResponses, model.LicenseAlertResponse{
+			ID:         alert.ID,
+			DoctorID:   alert.DoctorId,
+			DoctorName: alert.Doctor.User.Name,
+			License:    alert.License,
+			Kind:       alert.Kind,
+			ExpiresAt:  alert.ExpiresAt,
+			CreatedAt:  alert.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         alertResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/payment"
+	"booking-klinik/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PaymentController struct {
+	PaymentService services.PaymentService
+}
+
+func (pc *PaymentController) CreateCharge(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	createdPayment, err := pc.PaymentService.CreateCharge(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment created successfully", "payment": createdPayment})
+}
+
+func (pc *PaymentController) RecordManualPayment(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var manualPaymentRequest model.ManualPaymentRequest
+	if err := c.ShouldBindJSON(&manualPaymentRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	recordedPayment, err := pc.PaymentService.RecordManualPayment(c.Request.Context(), uint(bookingIdUint), manualPaymentRequest, userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment recorded successfully", "payment": recordedPayment})
+}
+
+func (pc *PaymentController) GetPaymentsByBookingId(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	payments, err := pc.PaymentService.GetPaymentsByBookingId(c.Request.Context(), uint(bookingIdUint), userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payments": payments})
+}
+
+func (pc *PaymentController) HandleWebhook(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := pc.PaymentService.HandleWebhook(c.Request.Context(), body, c.GetHeader("X-Signature")); err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed successfully"})
+}
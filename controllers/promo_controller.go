@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromoController struct {
+	PromoService services.PromoService
+}
+
+func (pc *PromoController) CreatePromo(c *gin.Context) {
+	var promoRequest model.PromoRequest
+	if err := c.ShouldBindJSON(&promoRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	promo, err := pc.PromoService.CreatePromo(c.Request.Context(), promoRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promo created successfully", "promo": promo})
+}
+
+func (pc *PromoController) GetAllPromos(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	promos, pagination, err := pc.PromoService.GetAllPromos(c.Request.Context(), paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         promos,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (pc *PromoController) GetPromoById(c *gin.Context) {
+	promoId := c.Param("id")
+	promoIdUint, err := strconv.ParseUint(promoId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo ID"})
+		return
+	}
+
+	promo, err := pc.PromoService.GetPromoById(c.Request.Context(), uint(promoIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"promo": promo})
+}
+
+func (pc *PromoController) UpdatePromo(c *gin.Context) {
+	promoId := c.Param("id")
+	promoIdUint, err := strconv.ParseUint(promoId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo ID"})
+		return
+	}
+
+	var promoRequest model.PromoRequest
+	if err := c.ShouldBindJSON(&promoRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	promo, err := pc.PromoService.UpdatePromo(c.Request.Context(), uint(promoIdUint), promoRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promo updated successfully", "promo": promo})
+}
+
+func (pc *PromoController) DeletePromo(c *gin.Context) {
+	promoId := c.Param("id")
+	promoIdUint, err := strconv.ParseUint(promoId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid promo ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	if err := pc.PromoService.DeletePromo(c.Request.Context(), uint(promoIdUint), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Promo deleted successfully"})
+}
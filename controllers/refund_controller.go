@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RefundController struct {
+	RefundService services.RefundService
+}
+
+func (rc *RefundController) GetRefunds(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	refunds, pagination, err := rc.RefundService.GetRefundsByStatus(c.Request.Context(), c.Query("status"), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         refunds,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (rc *RefundController) ApproveRefund(c *gin.Context) {
+	refundId := c.Param("id")
+	refundIdUint, err := strconv.ParseUint(refundId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid refund ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	refund, err := rc.RefundService.ApproveRefund(c.Request.Context(), uint(refundIdUint), userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Refund approved successfully", "refund": refund})
+}
+
+func (rc *RefundController) RejectRefund(c *gin.Context) {
+	refundId := c.Param("id")
+	refundIdUint, err := strconv.ParseUint(refundId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid refund ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	refund, err := rc.RefundService.RejectRefund(c.Request.Context(), uint(refundIdUint), userID, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Refund rejected successfully", "refund": refund})
+}
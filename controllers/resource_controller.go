@@ -0,0 +1,229 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ResourceController struct {
+	ResourceService services.ResourceService
+}
+
+func (rc *ResourceController) CreateResource(c *gin.Context) {
+	var resourceRequest model.ResourceRequest
+	if err := c.ShouldBindJSON(&resourceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Branch admins create resources for their own clinic by default
+	if resourceRequest.ClinicID == 0 {
+		resourceRequest.ClinicID = c.GetUint("clinicID")
+	}
+	if err := checkClinicAccess(c, resourceRequest.ClinicID); err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	resource, err := rc.ResourceService.CreateResource(c.Request.Context(), resourceRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource created successfully", "resource": toResourceResponse(*resource)})
+}
+
+func (rc *ResourceController) GetAllResources(c *gin.Context) {
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	resources, pagination, err := rc.ResourceService.GetAllResources(c.Request.Context(), c.Query("type"), clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resourceResponses []model.ResourceResponse
+	for _, resource := range resources {
+		resourceResponses = append(resourceResponses, toResourceResponse(resource))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         resourceResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func (rc *ResourceController) GetResourceById(c *gin.Context) {
+	resourceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	resource, err := rc.ResourceService.GetResourceById(c.Request.Context(), uint(resourceIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resource": toResourceResponse(*resource)})
+}
+
+func (rc *ResourceController) UpdateResource(c *gin.Context) {
+	resourceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	var resourceRequest model.ResourceRequest
+	if err := c.ShouldBindJSON(&resourceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !rc.checkResourceAccess(c, uint(resourceIdUint)) {
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	resource, err := rc.ResourceService.UpdateResource(c.Request.Context(), uint(resourceIdUint), resourceRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource updated successfully", "resource": toResourceResponse(*resource)})
+}
+
+func (rc *ResourceController) DeleteResource(c *gin.Context) {
+	resourceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	if !rc.checkResourceAccess(c, uint(resourceIdUint)) {
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	if err := rc.ResourceService.DeleteResource(c.Request.Context(), uint(resourceIdUint), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource deleted successfully"})
+}
+
+func (rc *ResourceController) GetServiceResources(c *gin.Context) {
+	serviceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	resources, err := rc.ResourceService.GetResourcesByServiceId(c.Request.Context(), uint(serviceIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resourceResponses []model.ResourceResponse
+	for _, resource := range resources {
+		resourceResponses = append(resourceResponses, toResourceResponse(resource))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resources": resourceResponses})
+}
+
+func (rc *ResourceController) AddServiceResource(c *gin.Context) {
+	serviceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	var serviceResourceRequest model.ServiceResourceRequest
+	if err := c.ShouldBindJSON(&serviceResourceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := rc.ResourceService.AddServiceResource(c.Request.Context(), uint(serviceIdUint), serviceResourceRequest.ResourceID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource added to service successfully"})
+}
+
+func (rc *ResourceController) RemoveServiceResource(c *gin.Context) {
+	serviceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	resourceIdUint, err := strconv.ParseUint(c.Param("resource_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	if err := rc.ResourceService.RemoveServiceResource(c.Request.Context(), uint(serviceIdUint), uint(resourceIdUint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource removed from service successfully"})
+}
+
+// checkResourceAccess writes the error response and returns false when the
+// resource is missing or belongs to another clinic than the admin's.
+func (rc *ResourceController) checkResourceAccess(c *gin.Context, resourceID uint) bool {
+	resource, err := rc.ResourceService.GetResourceById(c.Request.Context(), resourceID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return false
+	}
+	if err := checkClinicAccess(c, resource.ClinicId); err != nil {
+		clinicScopeError(c, err)
+		return false
+	}
+	return true
+}
+
+func toResourceResponse(resource model.Resource) model.ResourceResponse {
+	return model.ResourceResponse{
+		ID:          resource.ID,
+		Name:        resource.Name,
+		Type:        resource.Type,
+		Description: resource.Description,
+		IsActive:    resource.IsActive,
+		ClinicID:    resource.ClinicId,
+	}
+}
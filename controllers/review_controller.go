@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReviewController struct {
+	ReviewService services.ReviewService
+}
+
+func (rc *ReviewController) CreateReview(c *gin.Context) {
+	bookingIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var reviewRequest model.ReviewRequest
+	if err := c.ShouldBindJSON(&reviewRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	review, err := rc.ReviewService.CreateReview(c.Request.Context(), uint(bookingIdUint), reviewRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review created successfully", "review": toReviewResponse(*review, false)})
+}
+
+func (rc *ReviewController) ReplyToReview(c *gin.Context) {
+	reviewIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var replyRequest model.ReviewReplyRequest
+	if err := c.ShouldBindJSON(&replyRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+
+	review, err := rc.ReviewService.ReplyToReview(c.Request.Context(), uint(reviewIdUint), replyRequest, userID, userRole)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reply saved successfully", "review": toReviewResponse(*review, false)})
+}
+
+func (rc *ReviewController) ModerateReview(c *gin.Context) {
+	reviewIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+		return
+	}
+
+	var moderationRequest model.ReviewModerationRequest
+	if err := c.ShouldBindJSON(&moderationRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	review, err := rc.ReviewService.ModerateReview(c.Request.Context(), uint(reviewIdUint), moderationRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Review moderated successfully", "review": toReviewResponse(*review, true)})
+}
+
+// GetDoctorReviews lists a doctor's reviews. Hidden reviews are only included
+// for admins; the public directory never sees them.
+func (rc *ReviewController) GetDoctorReviews(c *gin.Context) {
+	doctorIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid doctor ID"})
+		return
+	}
+
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, _ := c.Get("role")
+	includeHidden := role == "admin"
+
+	reviews, pagination, err := rc.ReviewService.GetReviewsByDoctorId(c.Request.Context(), uint(doctorIdUint), includeHidden, paginator.Limit, paginator.Offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	reviewResponses := []model.ReviewResponse{}
+	for _, review := range reviews {
+		reviewResponses = append(reviewResponses, toReviewResponse(review, includeHidden))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         reviewResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func toReviewResponse(review model.Review, withModeration bool) model.ReviewResponse {
+	reviewResponse := model.ReviewResponse{
+		ID:          review.ID,
+		BookingID:   review.BookingId,
+		DoctorID:    review.DoctorId,
+		PatientName: review.User.Name,
+		Rating:      review.Rating,
+		Comment:     review.Comment,
+		Reply:       review.Reply,
+		RepliedAt:   review.RepliedAt,
+		CreatedAt:   review.CreatedAt,
+	}
+	if withModeration {
+		reviewResponse.IsHidden = review.IsHidden
+		reviewResponse.HiddenReason = review.HiddenReason
+	}
+	return reviewResponse
+}
@@ -24,10 +24,24 @@ func (sc *ServiceController) CreateService(c *gin.Context) {
 
 	//Default isActive = true
 
+	// A branch admin's services are specific to their clinic
+	if clinicID := c.GetUint("clinicID"); clinicID != 0 && service.ClinicId == nil {
+		service.ClinicId = &clinicID
+	}
+	if service.ClinicId != nil {
+		if err := checkClinicAccess(c, *service.ClinicId); err != nil {
+			clinicScopeError(c, err)
+			return
+		}
+	} else if branchAdmin(c) {
+		clinicScopeError(c, errNoClinic)
+		return
+	}
+
 	userID := c.MustGet("userID").(uint)
 	service.CreatedBy = userID
 	service.IsActive = true
-	createdService, err := sc.ServiceService.CreateService(service)
+	createdService, err := sc.ServiceService.CreateService(c.Request.Context(), service)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -43,7 +57,13 @@ func (sc *ServiceController) GetAllServices(c *gin.Context) {
 		return
 	}
 
-	services, err := sc.ServiceService.GetAllServices(paginator.Limit, paginator.Offset)
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	services, err := sc.ServiceService.GetAllServices(c.Request.Context(), clinicID, paginator.Limit, paginator.Offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -52,11 +72,13 @@ func (sc *ServiceController) GetAllServices(c *gin.Context) {
 	var serviceResponses []model.ServiceResponse
 	for _, service := range services {
 		serviceResponses = append(serviceResponses, model.ServiceResponse{
+			ID:              service.ID,
 			Name:            service.Name,
 			Description:     service.Description,
 			Price:           service.Price,
 			DurationMinutes: service.DurationMinutes,
 			IsActive:        service.IsActive,
+			ClinicID:        service.ClinicId,
 		})
 	}
 
@@ -71,13 +93,14 @@ func (sc *ServiceController) GetServiceById(c *gin.Context) {
 		return
 	}
 
-	service, err := sc.ServiceService.GetServiceById(uint(serviceIdUint))
+	service, err := sc.ServiceService.GetServiceById(c.Request.Context(), uint(serviceIdUint))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	var serviceResponse model.ServiceResponse
+	serviceResponse.ID = service.ID
 	serviceResponse.Name = service.Name
 	serviceResponse.Description = service.Description
 	serviceResponse.Price = service.Price
@@ -106,7 +129,7 @@ func (sc *ServiceController) UpdateService(c *gin.Context) {
 
 	service.UpdatedBy = userID
 
-	updatedService, err := sc.ServiceService.UpdateService(uint(serviceIdUint), service)
+	updatedService, err := sc.ServiceService.UpdateService(c.Request.Context(), uint(serviceIdUint), service)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -124,7 +147,7 @@ func (sc *ServiceController) DeleteService(c *gin.Context) {
 		return
 	}
 
-	err = sc.ServiceService.DeleteService(uint(serviceIdUint), userID)
+	err = sc.ServiceService.DeleteService(c.Request.Context(), uint(serviceIdUint), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -132,3 +155,29 @@ func (sc *ServiceController) DeleteService(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Service deleted successfully"})
 }
+
+func (sc *ServiceController) GetDoctorsByServiceId(c *gin.Context) {
+	serviceId := c.Param("id")
+	serviceIdUint, err := strconv.ParseUint(serviceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	doctors, err := sc.ServiceService.GetDoctorsByServiceId(c.Request.Context(), uint(serviceIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var doctorResponses []model.DoctorResponse
+	for _, doctor := range doctors {
+		doctorResponses = append(doctorResponses, model.DoctorResponse{
+			ID:             doctor.ID,
+			Name:           doctor.User.Name,
+			Specialization: doctor.Specialization,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"doctors": doctorResponses})
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ServicePriceController struct {
+	ServicePriceService services.ServicePriceService
+}
+
+func (spc *ServicePriceController) SchedulePrice(c *gin.Context) {
+	serviceId := c.Param("id")
+	serviceIdUint, err := strconv.ParseUint(serviceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	var priceRequest model.ServicePriceRequest
+	if err := c.ShouldBindJSON(&priceRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	servicePrice, err := spc.ServicePriceService.SchedulePrice(c.Request.Context(), uint(serviceIdUint), priceRequest, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Service price scheduled successfully", "price": servicePrice})
+}
+
+func (spc *ServicePriceController) GetPriceHistory(c *gin.Context) {
+	serviceId := c.Param("id")
+	serviceIdUint, err := strconv.ParseUint(serviceId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	servicePrices, err := spc.ServicePriceService.GetPriceHistory(c.Request.Context(), uint(serviceIdUint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prices": servicePrices})
+}
+
+func (spc *ServicePriceController) CancelScheduledPrice(c *gin.Context) {
+	serviceIdUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+		return
+	}
+
+	priceIdUint, err := strconv.ParseUint(c.Param("price_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	if err := spc.ServicePriceService.CancelScheduledPrice(c.Request.Context(), uint(serviceIdUint), uint(priceIdUint), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled price cancelled successfully"})
+}
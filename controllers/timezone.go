@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"booking-klinik/services"
+	"context"
+	"time"
+)
+
+// clinicClock renders stored UTC instants in the timezone of the clinic they
+// belong to, loading each clinic's timezone once per request.
+type clinicClock struct {
+	ctx           context.Context
+	clinicService services.ClinicService
+	locations     map[uint]*time.Location
+}
+
+func newClinicClock(ctx context.Context, clinicService services.ClinicService) *clinicClock {
+	return &clinicClock{ctx: ctx, clinicService: clinicService, locations: map[uint]*time.Location{}}
+}
+
+func (cc *clinicClock) in(clinicID uint, t time.Time) time.Time {
+	loc, ok := cc.locations[clinicID]
+	if !ok {
+		var err error
+		if loc, err = cc.clinicService.GetLocation(cc.ctx, clinicID); err != nil {
+			loc = time.UTC
+		}
+		cc.locations[clinicID] = loc
+	}
+	return t.In(loc)
+}
@@ -3,7 +3,6 @@ package controllers
 import (
 	"booking-klinik/model"
 	"booking-klinik/services"
-	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,14 +12,45 @@ type UserController struct {
 	UserService services.UserService
 }
 
+// RegisterUser creates a patient account. Staff accounts are created by
+// admins (RegisterDoctor, RegisterNurse) or the create-admin command.
 func (uc *UserController) RegisterUser(c *gin.Context) {
-	var user model.User
-	if err := c.ShouldBindJSON(&user); err != nil {
+	uc.register(c, "patient", 0, 0)
+}
+
+// RegisterDoctor creates the account of a doctor, whose profile is then added
+// with POST /doctor.
+func (uc *UserController) RegisterDoctor(c *gin.Context) {
+	uc.register(c, "doctor", c.MustGet("userID").(uint), 0)
+}
+
+// RegisterNurse creates the account of a nurse at the admin's clinic, or for
+// a super admin at the clinic in clinic_id.
+func (uc *UserController) RegisterNurse(c *gin.Context) {
+	clinicID, err := clinicScope(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+	if clinicID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "clinic_id is required"})
+		return
+	}
+	uc.register(c, "nurse", c.MustGet("userID").(uint), clinicID)
+}
+
+func (uc *UserController) register(c *gin.Context, role string, createdBy uint, clinicID uint) {
+	var request model.RegisterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	registeredUser, err := uc.UserService.RegisterUser(&user)
+	user := model.User{Name: request.Name, Email: request.Email, Password: request.Password, Role: role, CreatedBy: createdBy}
+	if clinicID != 0 {
+		user.ClinicId = &clinicID
+	}
+	registeredUser, err := uc.UserService.RegisterUser(c.Request.Context(), &user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -37,7 +67,7 @@ func (uc *UserController) LoginUser(c *gin.Context) {
 		return
 	}
 
-	token, err := uc.UserService.LoginUser(loginRequest.Email, loginRequest.Password)
+	token, err := uc.UserService.LoginUser(c.Request.Context(), loginRequest.Email, loginRequest.Password)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -54,9 +84,7 @@ func (uc *UserController) UpdatePassword(c *gin.Context) {
 		return
 	}
 
-	fmt.Println(updatePasswordRequest.OldPassword, updatePasswordRequest.NewPassword, userID)
-
-	err := uc.UserService.UpdatePassword(userID, updatePasswordRequest.OldPassword, updatePasswordRequest.NewPassword)
+	err := uc.UserService.UpdatePassword(c.Request.Context(), userID, updatePasswordRequest.OldPassword, updatePasswordRequest.NewPassword)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
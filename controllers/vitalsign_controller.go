@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/services"
+	"booking-klinik/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VitalSignController struct {
+	VitalSignService services.VitalSignService
+}
+
+func (vc *VitalSignController) RecordVitalSign(c *gin.Context) {
+	bookingId := c.Param("id")
+	bookingIdUint, err := strconv.ParseUint(bookingId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid booking ID"})
+		return
+	}
+
+	var vitalSignRequest model.VitalSignRequest
+	if err := c.ShouldBindJSON(&vitalSignRequest); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	vitalSign, err := vc.VitalSignService.RecordVitalSign(c.Request.Context(), uint(bookingIdUint), vitalSignRequest, userID, userRole, clinicID)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vital signs recorded successfully", "vitals": toVitalSignResponse(*vitalSign)})
+}
+
+func (vc *VitalSignController) GetVitalSignTrend(c *gin.Context) {
+	patientId := c.Param("user_id")
+	patientIdUint, err := strconv.ParseUint(patientId, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	paginator, err := utils.Pagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	userRole := c.MustGet("role").(string)
+	clinicID, err := callerClinic(c)
+	if err != nil {
+		clinicScopeError(c, err)
+		return
+	}
+
+	vitalSigns, pagination, err := vc.VitalSignService.GetVitalSignTrend(c.Request.Context(), uint(patientIdUint), userID, userRole, clinicID, paginator.Limit, paginator.Offset)
+	if err != nil {
+		serviceError(c, err)
+		return
+	}
+
+	var vitalSignResponses []model.VitalSignResponse
+	for _, vitalSign := range vitalSigns {
+		vitalSignResponses = append(vitalSignResponses, toVitalSignResponse(vitalSign))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":         vitalSignResponses,
+		"total_rows":   pagination.TotalRows,
+		"total_pages":  pagination.TotalPages,
+		"current_page": pagination.Page,
+		"limit":        pagination.Limit,
+	})
+}
+
+func toVitalSignResponse(vitalSign model.VitalSign) model.VitalSignResponse {
+	flags := []string{}
+	if vitalSign.Flags != "" {
+		flags = strings.Split(vitalSign.Flags, ",")
+	}
+
+	return model.VitalSignResponse{
+		ID:          vitalSign.ID,
+		BookingID:   vitalSign.BookingId,
+		Systolic:    vitalSign.Systolic,
+		Diastolic:   vitalSign.Diastolic,
+		Temperature: vitalSign.Temperature,
+		WeightKg:    vitalSign.WeightKg,
+		HeightCm:    vitalSign.HeightCm,
+		Pulse:       vitalSign.Pulse,
+		SpO2:        vitalSign.SpO2,
+		BMI:         vitalSign.BMI,
+		Flags:       flags,
+		RecordedAt:  vitalSign.RecordedAt,
+	}
+}
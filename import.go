@@ -0,0 +1,66 @@
+package main
+
+import (
+	"booking-klinik/config"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runImport creates doctors or schedules from a CSV file. The kind of rows is
+// taken from --type, or else from the file name (doctors.csv, schedules.csv).
+func runImport(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := flags.String("type", "", "doctors or schedules; defaults to the file name")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import [--type doctors|schedules] FILE")
+	}
+	path := flags.Arg(0)
+
+	if *kind == "" {
+		base := strings.ToLower(filepath.Base(path))
+		switch {
+		case strings.HasPrefix(base, "doctors"):
+			*kind = "doctors"
+		case strings.HasPrefix(base, "schedules"):
+			*kind = "schedules"
+		default:
+			return fmt.Errorf("cannot tell what %s contains; pass --type doctors or --type schedules", path)
+		}
+	}
+	if *kind != "doctors" && *kind != "schedules" {
+		return fmt.Errorf("unknown import type %q", *kind)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	commands, err := connectForCommand(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if *kind == "doctors" {
+		report, err := commands.Importer.ImportDoctors(ctx, file, 0)
+		if err != nil {
+			return err
+		}
+		return printImportReport(report)
+	}
+	report, err := commands.Importer.ImportSchedules(ctx, file, 0)
+	if err != nil {
+		return err
+	}
+	return printImportReport(report)
+}
@@ -0,0 +1,154 @@
+// Package testutil sets up the databases and records that the repository
+// and service tests run against.
+package testutil
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewDB opens an in-memory SQLite database of the test's own, migrated to
+// the latest schema. It holds a single connection, which keeps the database
+// alive until the test ends.
+func NewDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+		Logger:  logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if _, err := config.MigrateUp(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// Fixture is a patient, a doctor and the service they provide at the clinic
+// created by the baseline migration, which is in Asia/Jakarta.
+type Fixture struct {
+	DB      *gorm.DB
+	Clinic  model.Clinic
+	Patient model.User
+	Doctor  model.Doctor
+	Service model.Service
+}
+
+func NewFixture(t *testing.T) *Fixture {
+	t.Helper()
+	f := &Fixture{DB: NewDB(t)}
+
+	MustCreate(t, f.DB, &f.Patient, model.User{Name: "Patient", Email: "patient@test", Password: "x", Role: "patient"})
+	if err := f.DB.First(&f.Clinic).Error; err != nil {
+		t.Fatal(err)
+	}
+	f.Service = f.AddService(t, "Check Up", 30)
+	f.Doctor = f.AddDoctor(t, "Andi", "General Practitioner", f.Service)
+	return f
+}
+
+func (f *Fixture) AddService(t *testing.T, name string, minutes int) model.Service {
+	t.Helper()
+	var service model.Service
+	MustCreate(t, f.DB, &service, model.Service{Name: name, Price: 100000, DurationMinutes: minutes, IsActive: true})
+	return service
+}
+
+// AddDoctor stores a doctor providing services. The name has to be unique
+// within the test.
+func (f *Fixture) AddDoctor(t *testing.T, name, specialization string, services ...model.Service) model.Doctor {
+	t.Helper()
+	var user model.User
+	MustCreate(t, f.DB, &user, model.User{Name: name, Email: strings.ToLower(name) + "@test", Password: "x", Role: "doctor"})
+	var doctor model.Doctor
+	MustCreate(t, f.DB, &doctor, model.Doctor{UserId: user.ID, Specialization: specialization})
+	if err := f.DB.Model(&doctor).Association("Services").Append(services); err != nil {
+		t.Fatal(err)
+	}
+	return doctor
+}
+
+func (f *Fixture) AddResource(t *testing.T, name string) model.Resource {
+	t.Helper()
+	var resource model.Resource
+	MustCreate(t, f.DB, &resource, model.Resource{Name: name, Type: "equipment", IsActive: true, ClinicId: f.Clinic.ID})
+	return resource
+}
+
+// AddSchedule gives the doctor a schedule for the service from start to end.
+func (f *Fixture) AddSchedule(t *testing.T, doctor model.Doctor, service model.Service, start, end time.Time) model.DoctorSchedule {
+	t.Helper()
+	var schedule model.DoctorSchedule
+	MustCreate(t, f.DB, &schedule, model.DoctorSchedule{
+		DoctorId:  doctor.ID,
+		ServiceId: service.ID,
+		ClinicId:  f.Clinic.ID,
+		Date:      time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC),
+		StartTime: start,
+		EndTime:   end,
+	})
+	return schedule
+}
+
+// AddBooking stores a booking of the patient with doctor for the service from
+// start, bypassing the slot checks.
+func (f *Fixture) AddBooking(t *testing.T, doctor model.Doctor, service model.Service, start time.Time, status string, resources ...model.Resource) model.Booking {
+	t.Helper()
+	var booking model.Booking
+	MustCreate(t, f.DB, &booking, model.Booking{
+		UserId:    f.Patient.ID,
+		DoctorId:  doctor.ID,
+		ServiceId: service.ID,
+		ClinicId:  f.Clinic.ID,
+		StartAt:   start,
+		EndAt:     start.Add(time.Duration(service.DurationMinutes) * time.Minute),
+		Status:    status,
+		Price:     service.Price,
+		Resources: resources,
+	})
+	return booking
+}
+
+// AddPayment stores an online payment of the booking through provider.
+func (f *Fixture) AddPayment(t *testing.T, booking model.Booking, amount int, provider, status string) model.Payment {
+	t.Helper()
+	var paid model.Payment
+	MustCreate(t, f.DB, &paid, model.Payment{
+		BookingId:   booking.ID,
+		UserId:      booking.UserId,
+		Amount:      amount,
+		Method:      "online",
+		Provider:    provider,
+		ProviderRef: fmt.Sprintf("%s-%d", provider, time.Now().UnixNano()),
+		Status:      status,
+	})
+	return paid
+}
+
+// MustCreate stores value and copies it, with its generated fields, to dest.
+func MustCreate[T any](t *testing.T, db *gorm.DB, dest *T, value T) {
+	t.Helper()
+	*dest = value
+	if err := db.Create(dest).Error; err != nil {
+		t.Fatal(err)
+	}
+}
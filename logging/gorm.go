@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger writes gorm's logs through slog, so queries run with a request
+// context are tagged with its request ID. Failed queries are logged as errors
+// and slow ones as warnings; every query is logged at debug level. Bound
+// values are left out of the SQL, as they can hold personal data and password
+// hashes.
+type GormLogger struct {
+	SlowThreshold time.Duration
+}
+
+func (l *GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	slog.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	slog.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	slog.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	level := slog.LevelDebug
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelError
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		level = slog.LevelWarn
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+	}
+	message := "query"
+	switch level {
+	case slog.LevelError:
+		message = "query failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	case slog.LevelWarn:
+		message = "slow query"
+	}
+	slog.LogAttrs(ctx, level, message, attrs...)
+}
+
+// ParamsFilter drops the bound values from the SQL that is logged.
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
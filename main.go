@@ -2,28 +2,194 @@ package main
 
 import (
 	"booking-klinik/config"
+	"booking-klinik/logging"
+	"booking-klinik/metrics"
+	"booking-klinik/repository"
 	"booking-klinik/routes"
+	"booking-klinik/services"
+	"booking-klinik/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata"
 
-	"github.com/joho/godotenv"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+const usage = `usage: booking-klinik <command> [arguments]
+
+commands:
+  serve                               run the HTTP server (the default)
+  migrate up|down|status|create       manage the database schema
+  create-admin --email EMAIL          create an admin account
+  seed --demo                         fill an empty database with demo data
+  import [--type doctors|schedules] FILE
+                                      create doctors or schedules from a CSV file`
+
 func main() {
 
-	loc, _ := time.LoadLocation("Asia/Jakarta")
-	time.Local = loc
-	err := godotenv.Load(".env")
+	command, args := "serve", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+	if command == "help" || command == "-h" || command == "--help" {
+		fmt.Println(usage)
+		return
+	}
 
+	//Load configuration, refusing to start with a broken one
+	cfg, err := config.Load()
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
+	slog.SetDefault(logging.New(os.Stdout, cfg.Log.SlogLevel(), cfg.Log.Format))
+
+	switch command {
+	case "serve":
+		err = serve(cfg)
+	case "migrate":
+		err = runMigrate(cfg, args)
+	case "create-admin":
+		err = runCreateAdmin(cfg, args)
+	case "seed":
+		err = runSeed(cfg, args)
+	case "import":
+		err = runImport(cfg, args)
+	default:
+		err = fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+	if err != nil {
+		slog.Error("command failed", "command", command, "error", err)
+		os.Exit(1)
+	}
+}
+
+// serve runs the HTTP server until SIGINT or SIGTERM, then stops accepting
+// connections, lets in-flight requests finish and stops the background
+// workers before returning, flushing the spans not yet exported last.
+func serve(cfg *config.Config) error {
+	//Export traces while serving
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.OTLPEndpoint, cfg.Tracing.ServiceName, cfg.Tracing.SampleRatio)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Error("flushing traces failed", "error", err)
+		}
+	}()
 
 	//Connect DB
-	db := config.ConnectDB()
-	//Migrate DB
-	config.MigrateDB(db)
+	db, err := config.ConnectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+	//Refuse to serve an outdated schema
+	if err := config.CheckSchemaVersion(db); err != nil {
+		return err
+	}
+
+	//Setup Router, printing gin's route table only when debugging
+	if os.Getenv(gin.EnvGinMode) == "" && cfg.Log.SlogLevel() > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	registry := prometheus.NewRegistry()
+	appMetrics, err := metrics.New(registry)
+	if err != nil {
+		return err
+	}
+	router, err := routes.SetupRouter(db, cfg, appMetrics)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	//Check doctor licenses daily until shutdown
+	licenseService := &services.LicenseServiceImpl{
+		DoctorRepository:       &repository.DoctorRepositoryImpl{DB: db},
+		LicenseAlertRepository: &repository.LicenseAlertRepositoryImpl{DB: db},
+		WarningDays:            cfg.Rules.LicenseAlertDays,
+	}
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		licenseService.Run(ctx, 24*time.Hour)
+	}()
+
+	server := &http.Server{
+		Addr:         ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+	}
+	serverErr := make(chan error, 2)
+	go func() {
+		if cfg.Server.TLSCertFile != "" {
+			serverErr <- server.ListenAndServeTLS(cfg.Server.TLSCertFile, cfg.Server.TLSKeyFile)
+		} else {
+			serverErr <- server.ListenAndServe()
+		}
+	}()
+	slog.Info("listening", "addr", server.Addr)
 
-	//Setup Router
-	r := routes.SetupRouter(db)
-	r.Run(":8080")
+	//Serve metrics on their own listener, off the public API
+	var metricsServer *http.Server
+	if cfg.Server.MetricsAddr != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", appMetrics.Handler())
+		metricsServer = &http.Server{
+			Addr:         cfg.Server.MetricsAddr,
+			Handler:      metricsMux,
+			ReadTimeout:  cfg.Server.ReadTimeout,
+			WriteTimeout: cfg.Server.WriteTimeout,
+			IdleTimeout:  cfg.Server.IdleTimeout,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- fmt.Errorf("serving metrics: %w", err)
+			}
+		}()
+		slog.Info("serving metrics", "addr", metricsServer.Addr)
+	}
+
+	select {
+	case err = <-serverErr:
+	case <-ctx.Done():
+		// A second signal kills the process without waiting
+		stop()
+		slog.Info("shutting down, waiting for in-flight requests")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+	}
+
+	if metricsServer != nil {
+		metricsServer.Close()
+	}
+
+	stop()
+	workers.Wait()
+	if sqlDB, dbErr := db.DB(); dbErr == nil {
+		sqlDB.Close()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,193 @@
+// Package metrics defines the Prometheus metrics of the application: HTTP
+// traffic, the database connection pool and booking events.
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bookingklinik"
+
+// Metrics holds the metrics of one server, registered on the registry it was
+// created with. A nil *Metrics records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	bookingsCreated     prometheus.Counter
+	bookingsRejected    *prometheus.CounterVec
+	bookingsCancelled   prometheus.Counter
+	bookingsCompleted   prometheus.Counter
+	bookingsNoShow      prometheus.Counter
+}
+
+// New creates the application metrics on registry, along with the Go runtime
+// and process metrics.
+func New(registry *prometheus.Registry) (*Metrics, error) {
+	m := &Metrics{
+		registry: registry,
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to handle HTTP requests, by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		bookingsCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_created_total",
+			Help:      "Bookings created.",
+		}),
+		bookingsRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_rejected_total",
+			Help:      "Booking requests rejected, by reason.",
+		}, []string{"reason"}),
+		bookingsCancelled: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_cancelled_total",
+			Help:      "Bookings cancelled.",
+		}),
+		bookingsCompleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_completed_total",
+			Help:      "Bookings marked completed.",
+		}),
+		bookingsNoShow: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "bookings_no_show_total",
+			Help:      "Bookings marked no-show.",
+		}),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.bookingsCreated,
+		m.bookingsRejected,
+		m.bookingsCancelled,
+		m.bookingsCompleted,
+		m.bookingsNoShow,
+	} {
+		if err := registry.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Handler serves the metrics of the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a handled HTTP request. Route is the route pattern,
+// not the path, so IDs in paths do not each make a new series.
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	labels := []string{method, route, strconv.Itoa(status)}
+	m.httpRequests.WithLabelValues(labels...).Inc()
+	m.httpRequestDuration.WithLabelValues(labels...).Observe(duration.Seconds())
+}
+
+// BookingCreated counts a booking created.
+func (m *Metrics) BookingCreated() {
+	if m == nil {
+		return
+	}
+	m.bookingsCreated.Inc()
+}
+
+// BookingRejected counts a booking request refused by a booking rule, by the
+// rule that refused it.
+func (m *Metrics) BookingRejected(reason string) {
+	if m == nil {
+		return
+	}
+	m.bookingsRejected.WithLabelValues(reason).Inc()
+}
+
+// RecordBookingStatus counts a booking moving to status, for the statuses
+// that close a booking.
+func (m *Metrics) RecordBookingStatus(status string) {
+	if m == nil {
+		return
+	}
+	switch status {
+	case "cancelled":
+		m.bookingsCancelled.Inc()
+	case "completed":
+		m.bookingsCompleted.Inc()
+	case "no_show":
+		m.bookingsNoShow.Inc()
+	}
+}
+
+// RegisterDB exports the connection pool statistics of db, labelled with the
+// name of the database.
+func (m *Metrics) RegisterDB(db *sql.DB, dbName string) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// QueueSource reports how many bookings each doctor still has open today.
+type QueueSource interface {
+	GetTodayQueueLengths(ctx context.Context, now time.Time) (map[uint]int64, error)
+}
+
+// queueScrapeTimeout bounds the queries of one scrape of the queue gauge.
+const queueScrapeTimeout = 5 * time.Second
+
+// RegisterQueue exports the queue length of every doctor for today, read from
+// source on each scrape.
+func (m *Metrics) RegisterQueue(source QueueSource) error {
+	return m.registry.Register(&queueCollector{
+		source: source,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "booking_queue_length"),
+			"Bookings of today still pending or confirmed, by doctor.",
+			[]string{"doctor_id"}, nil,
+		),
+	})
+}
+
+type queueCollector struct {
+	source QueueSource
+	desc   *prometheus.Desc
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueScrapeTimeout)
+	defer cancel()
+
+	lengths, err := c.source.GetTodayQueueLengths(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "reading booking queue lengths failed", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for doctorID, length := range lengths {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(length), strconv.FormatUint(uint64(doctorID), 10))
+	}
+}
@@ -2,27 +2,33 @@ package middleware
 
 import (
 	"booking-klinik/utils"
-	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AuthMiddleware accepts requests carrying a token signed with secret.
+func AuthMiddleware(secret string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// TODO: implement authentication middleware
 		tokenString := c.GetHeader("Authorization")
 		tokenString = strings.TrimPrefix(tokenString, "Bearer ")
-		claims, err := utils.VerifyJWT(tokenString)
+		claims, err := utils.VerifyJWT(tokenString, secret)
 		if err != nil {
 			c.AbortWithStatusJSON(401, gin.H{"error": "Invalid token"})
 			return
 		}
 
-		fmt.Println(claims.Email, claims.UserID, claims.Role)
 		c.Set("email", claims.Email)
 		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
+		// A super admin is an admin that is not tied to any clinic
+		if claims.Role == "superadmin" {
+			c.Set("role", "admin")
+			c.Set("superAdmin", true)
+		} else {
+			c.Set("role", claims.Role)
+			c.Set("clinicID", claims.ClinicID)
+		}
 		c.Next()
 	}
 }
@@ -30,9 +36,9 @@ func AuthMiddleware() gin.HandlerFunc {
 func RoleCheckMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
-		fmt.Println(role)
 		if !exists {
 			c.AbortWithStatusJSON(403, gin.H{"error": "Role not found"})
+			return
 		}
 
 		roleStr := role.(string)
@@ -46,3 +52,13 @@ func RoleCheckMiddleware(allowedRoles ...string) gin.HandlerFunc {
 		c.AbortWithStatusJSON(403, gin.H{"error": "You don't have permission to access this resource"})
 	}
 }
+
+func SuperAdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("superAdmin") {
+			c.AbortWithStatusJSON(403, gin.H{"error": "You don't have permission to access this resource"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// TracingMiddleware starts a span for every request, named after its route
+// and continuing the trace of a caller that sent a traceparent header. The
+// handlers get the span in the request context. Health probes are not
+// traced.
+func TracingMiddleware(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		return !quietPaths[c.Request.URL.Path]
+	}))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"booking-klinik/config"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = "usage: migrate up [N] | down [N] | status | create NAME"
+
+// runMigrate handles `migrate up|down|status|create`. Up applies every
+// pending migration unless N is given; down rolls back one unless N is given.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		paths, err := config.CreateMigration(config.MigrationsDir, args[1])
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			fmt.Println("Created", path)
+		}
+		return nil
+	}
+
+	steps := 0
+	if args[0] == "down" {
+		steps = 1
+	}
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return errors.New(migrateUsage)
+		}
+		steps = n
+	}
+
+	db, err := config.ConnectDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		migrations, err := config.MigrateUp(db, steps)
+		for _, migration := range migrations {
+			fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(migrations) == 0 {
+			fmt.Println("No pending migrations")
+		}
+		return err
+	case "down":
+		migrations, err := config.MigrateDown(db, steps)
+		for _, migration := range migrations {
+			fmt.Printf("Rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		statuses, err := config.GetMigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state, appliedAt = "applied", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Dirty {
+				state = "dirty"
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		return writer.Flush()
+	}
+	return errors.New(migrateUsage)
+}
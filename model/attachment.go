@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Attachment struct {
+	gorm.Model
+	BookingId   uint    `json:"booking_id" gorm:"not null;index"`
+	FileName    string  `json:"file_name" gorm:"not null"`
+	ContentType string  `json:"content_type" gorm:"not null"`
+	Size        int64   `json:"size" gorm:"not null"`
+	StorageKey  string  `json:"-" gorm:"not null;unique"`
+	Category    string  `json:"category" gorm:"not null;default:other"`
+	CreatedBy   uint    `json:"created_by" gorm:"not null"`
+	UpdatedBy   uint    `json:"updated_by"`
+	Booking     Booking `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type AttachmentResponse struct {
+	ID          uint      `json:"id"`
+	BookingID   uint      `json:"booking_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Category    string    `json:"category"`
+	UploadedBy  uint      `json:"uploaded_by"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
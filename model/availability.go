@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type AvailableSlot struct {
+	DoctorID       uint      `json:"doctor_id"`
+	DoctorName     string    `json:"doctor_name"`
+	Specialization string    `json:"specialization"`
+	ServiceID      uint      `json:"service_id"`
+	ClinicID       uint      `json:"clinic_id"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+}
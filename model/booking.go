@@ -8,42 +8,62 @@ import (
 
 type Booking struct {
 	gorm.Model
-	UserId      uint      `json:"user_id" gorm:"not null"`
-	DoctorId    uint      `json:"doctor_id" gorm:"not null"`
-	ServiceId   uint      `json:"service_id" gorm:"not null"`
-	BookingDate time.Time `json:"booking_date" time_format:"2006-01-02" gorm:"not null"`
-	BookingTime time.Time `json:"booking_time" time_format:"15:04" gorm:"not null"`
-	Status      string    `json:"status" gorm:"not null;default:pending"`
-	Notes       string    `json:"notes" gorm:"type:text"`
-	CreatedBy   uint      `json:"created_by" gorm:"not null"`
-	UpdatedBy   uint      `json:"updated_by"`
-	User        User      `json:"-" gorm:"foreignKey:UserId;references:ID"`
-	Doctor      Doctor    `json:"-" gorm:"foreignKey:DoctorId;references:ID"`
-	Service     Service   `json:"-" gorm:"foreignKey:ServiceId;references:ID"`
+	UserId         uint       `json:"user_id" gorm:"not null"`
+	DoctorId       uint       `json:"doctor_id" gorm:"not null;index:idx_booking_doctor_interval,priority:1"`
+	ServiceId      uint       `json:"service_id" gorm:"not null"`
+	ClinicId       uint       `json:"clinic_id" gorm:"index"`
+	StartAt        time.Time  `json:"start_at" gorm:"not null;index:idx_booking_doctor_interval,priority:2"`
+	EndAt          time.Time  `json:"end_at" gorm:"not null;index:idx_booking_doctor_interval,priority:3"`
+	Status         string     `json:"status" gorm:"not null;default:pending"`
+	Notes          string     `json:"notes" gorm:"type:text"`
+	PayerType      string     `json:"payer_type" gorm:"not null;default:self_pay"`
+	InsurerName    string     `json:"insurer_name"`
+	PolicyNumber   string     `json:"policy_number"`
+	Price          int        `json:"price" gorm:"not null;default:0"`
+	DiscountAmount int        `json:"discount_amount" gorm:"not null;default:0"`
+	FinalPrice     int        `json:"final_price" gorm:"not null;default:0"`
+	PromoCode      string     `json:"promo_code"`
+	CreatedBy      uint       `json:"created_by" gorm:"not null"`
+	UpdatedBy      uint       `json:"updated_by"`
+	User           User       `json:"-" gorm:"foreignKey:UserId;references:ID"`
+	Doctor         Doctor     `json:"-" gorm:"foreignKey:DoctorId;references:ID"`
+	Service        Service    `json:"-" gorm:"foreignKey:ServiceId;references:ID"`
+	Resources      []Resource `json:"-" gorm:"many2many:booking_resources;"`
 }
 
 type BookingRequest struct {
-	DoctorId    uint   `json:"doctor_id"`
-	ServiceId   uint   `json:"service_id"`
-	BookingDate string `json:"booking_date" time_format:"2006-01-02"`
-	BookingTime string `json:"booking_time" time_format:"15:04"`
-	Notes       string `json:"notes"`
+	DoctorId     uint   `json:"doctor_id"`
+	ServiceId    uint   `json:"service_id"`
+	StartAt      string `json:"start_at"`
+	BookingDate  string `json:"booking_date" time_format:"2006-01-02"`
+	BookingTime  string `json:"booking_time"`
+	ClinicID     uint   `json:"clinic_id"`
+	Notes        string `json:"notes"`
+	PayerType    string `json:"payer_type"`
+	InsurerName  string `json:"insurer_name"`
+	PolicyNumber string `json:"policy_number"`
+	PromoCode    string `json:"promo_code"`
 }
 
 type BookingResponse struct {
-	ID          uint      `json:"id"`
-	PatientName string    `json:"patient_name"`
-	DoctorName  string    `json:"doctor_name"`
-	ServiceName string    `json:"service_name"`
-	BookingDate time.Time `json:"booking_date" time_format:"2006-01-02"`
-	BookingTime time.Time `json:"booking_time" time_format:"15:04"`
-	Status      string    `json:"status"`
-	Notes       string    `json:"notes"`
+	ID             uint               `json:"id"`
+	PatientName    string             `json:"patient_name"`
+	DoctorName     string             `json:"doctor_name"`
+	ServiceName    string             `json:"service_name"`
+	StartAt        time.Time          `json:"start_at"`
+	EndAt          time.Time          `json:"end_at"`
+	Status         string             `json:"status"`
+	Notes          string             `json:"notes"`
+	PayerType      string             `json:"payer_type"`
+	Price          int                `json:"price"`
+	DiscountAmount int                `json:"discount_amount"`
+	FinalPrice     int                `json:"final_price"`
+	PromoCode      string             `json:"promo_code,omitempty"`
+	Vitals         *VitalSignResponse `json:"vitals,omitempty"`
 }
 
 type UpdateRequest struct {
-	BookingDate time.Time `json:"booking_date" time_format:"2006-01-02"`
-	BookingTime time.Time `json:"booking_time" time_format:"15:04"`
-	Status      string    `json:"status"`
-	Notes       string    `json:"notes"`
+	StartAt time.Time `json:"start_at"`
+	Status  string    `json:"status"`
+	Notes   string    `json:"notes"`
 }
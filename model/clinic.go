@@ -0,0 +1,55 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+type Clinic struct {
+	gorm.Model
+	Name         string              `json:"name" gorm:"not null;uniqueIndex;size:150"`
+	Address      string              `json:"address" gorm:"type:text"`
+	Phone        string              `json:"phone"`
+	Timezone     string              `json:"timezone" gorm:"not null;default:Asia/Jakarta"`
+	IsActive     bool                `json:"is_active" gorm:"not null;default:true"`
+	CreatedBy    uint                `json:"created_by" gorm:"not null"`
+	UpdatedBy    uint                `json:"updated_by"`
+	OpeningHours []ClinicOpeningHour `json:"opening_hours" gorm:"foreignKey:ClinicId;references:ID"`
+	Doctors      []Doctor            `json:"-" gorm:"many2many:doctor_clinics;"`
+}
+
+// ClinicOpeningHour is the opening window of a clinic on one day of the week.
+// Weekday follows time.Weekday, so 0 is Sunday.
+type ClinicOpeningHour struct {
+	ID       uint   `json:"-" gorm:"primarykey"`
+	ClinicId uint   `json:"-" gorm:"not null;index"`
+	Weekday  int    `json:"weekday" gorm:"not null"`
+	OpensAt  string `json:"opens_at" gorm:"size:5;not null"`
+	ClosesAt string `json:"closes_at" gorm:"size:5;not null"`
+}
+
+type ClinicRequest struct {
+	Name         string              `json:"name"`
+	Address      string              `json:"address"`
+	Phone        string              `json:"phone"`
+	Timezone     string              `json:"timezone"`
+	IsActive     *bool               `json:"is_active"`
+	OpeningHours []ClinicOpeningHour `json:"opening_hours"`
+}
+
+type ClinicDoctorRequest struct {
+	DoctorID uint `json:"doctor_id"`
+}
+
+type ClinicAdminRequest struct {
+	UserID uint `json:"user_id"`
+}
+
+type ClinicResponse struct {
+	ID           uint                `json:"id"`
+	Name         string              `json:"name"`
+	Address      string              `json:"address"`
+	Phone        string              `json:"phone"`
+	Timezone     string              `json:"timezone"`
+	IsActive     bool                `json:"is_active"`
+	OpeningHours []ClinicOpeningHour `json:"opening_hours"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -8,11 +10,22 @@ type Doctor struct {
 	gorm.Model
 	UserId         uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_user_id"`
 	Specialization string           `json:"specialization" gorm:"not null"`
+	Bio            string           `json:"bio" gorm:"type:text"`
+	PhotoURL       string           `json:"photo_url"`
+	Languages      string           `json:"languages"`
+	Education      string           `json:"education" gorm:"type:text"`
+	PracticeSince  int              `json:"practice_since"`
+	STRNumber      string           `json:"str_number"`
+	STRExpiresAt   *time.Time       `json:"str_expires_at"`
+	SIPNumber      string           `json:"sip_number" gorm:"column:sip_number"`
+	SIPExpiresAt   *time.Time       `json:"sip_expires_at" gorm:"column:sip_expires_at"`
 	CreatedBy      uint             `json:"created_by" gorm:"not null"`
 	UpdatedBy      uint             `json:"updated_by"`
 	User           User             `json:"-" gorm:"foreignKey:UserId;references:ID"`
 	Bookings       []Booking        `json:"-" gorm:"foreignKey:DoctorId;references:ID"`
 	Schedules      []DoctorSchedule `json:"schedules" gorm:"foreignKey:DoctorId;references:ID"`
+	Services       []Service        `json:"-" gorm:"many2many:doctor_services;"`
+	Clinics        []Clinic         `json:"-" gorm:"many2many:doctor_clinics;"`
 }
 
 type DoctorResponse struct {
@@ -21,6 +34,28 @@ type DoctorResponse struct {
 	Specialization string `json:"specialization"`
 }
 
+type DoctorProfileResponse struct {
+	ID              uint     `json:"id"`
+	Name            string   `json:"name"`
+	Specialization  string   `json:"specialization"`
+	Bio             string   `json:"bio"`
+	PhotoURL        string   `json:"photo_url"`
+	Languages       []string `json:"languages"`
+	Education       string   `json:"education"`
+	YearsOfPractice int      `json:"years_of_practice"`
+	NextAvailable   string   `json:"next_available_date,omitempty"`
+	AverageRating   float64  `json:"average_rating"`
+	ReviewCount     int64    `json:"review_count"`
+}
+
+type DoctorDetailResponse struct {
+	DoctorProfileResponse
+	STRNumber    string     `json:"str_number"`
+	STRExpiresAt *time.Time `json:"str_expires_at"`
+	SIPNumber    string     `json:"sip_number"`
+	SIPExpiresAt *time.Time `json:"sip_expires_at"`
+}
+
 type DoctorRequest struct {
 	ID uint `json:"id"`
 }
@@ -28,3 +63,7 @@ type DoctorRequest struct {
 type GetDoctorNameRequest struct {
 	UserID uint `json:"user_id"`
 }
+
+type DoctorServiceRequest struct {
+	ServiceID uint `json:"service_id"`
+}
@@ -9,28 +9,34 @@ import (
 type DoctorSchedule struct {
 	gorm.Model
 	DoctorId  uint      `json:"doctor_id" gorm:"not null"`
-	ServiceId uint      `json:"service_id" gorm:"not null"`
-	Date      time.Time `json:"date" time_format:"YYYY-MM-DD" gorm:"not null"`
+	ServiceId uint      `json:"service_id" gorm:"not null;index:idx_schedule_service_date"`
+	Date      time.Time `json:"date" time_format:"YYYY-MM-DD" gorm:"not null;index:idx_schedule_service_date"`
 	StartTime time.Time `json:"start_time" time_format:"15:04" gorm:"not null"`
 	EndTime   time.Time `json:"end_time" time_format:"15:04" gorm:"not null"`
+	RoomId    *uint     `json:"room_id" gorm:"index"`
+	ClinicId  uint      `json:"clinic_id" gorm:"index"`
 	CreatedBy uint      `json:"created_by" gorm:"not null"`
 	UpdatedBy uint      `json:"updated_by"`
 	Doctor    Doctor    `json:"doctor" gorm:"foreignKey:DoctorId;references:ID"`
 	Service   Service   `json:"service" gorm:"foreignKey:ServiceId;references:ID"`
+	Room      *Resource `json:"room,omitempty" gorm:"foreignKey:RoomId;references:ID"`
 }
 
 type DoctorScheduleResponse struct {
 	ID        uint      `json:"id"`
 	DoctorID  uint      `json:"doctor_id"`
-	Date      time.Time `json:"date" time_format:"YYYY-MM-DD"`
-	StartTime time.Time `json:"start_time" time_format:"15:04"`
-	EndTime   time.Time `json:"end_time" time_format:"15:04"`
+	ClinicID  uint      `json:"clinic_id"`
+	Date      string    `json:"date"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
 }
 
 type DoctorScheduleRequest struct {
 	DoctorID  uint   `json:"doctor_id"`
 	ServiceID uint   `json:"service_id"`
 	Date      string `json:"date" time_format:"2006-01-02"`
-	StartTime string `json:"start_time" time_format:"15:04"`
-	EndTime   string `json:"end_time" time_format:"15:04"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	RoomID    *uint  `json:"room_id"`
+	ClinicID  uint   `json:"clinic_id"`
 }
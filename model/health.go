@@ -0,0 +1,10 @@
+package model
+
+// Readiness is the state reported by the readiness probe.
+type Readiness struct {
+	Status        string `json:"status"`
+	Database      string `json:"database"`
+	SchemaVersion uint   `json:"schema_version"`
+	LatestVersion uint   `json:"latest_schema_version"`
+	Error         string `json:"error,omitempty"`
+}
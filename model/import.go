@@ -0,0 +1,15 @@
+package model
+
+// ImportRowResult is the outcome of one CSV row. Row is the line number in
+// the file, the header being line 1.
+type ImportRowResult struct {
+	Row   int    `json:"row"`
+	ID    uint   `json:"id,omitempty"`
+	Note  string `json:"note,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Accepted []ImportRowResult `json:"accepted"`
+	Rejected []ImportRowResult `json:"rejected"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ServiceCoverage states how much of a service a payer covers. An empty
+// InsurerName applies to every insurer of the payer type.
+type ServiceCoverage struct {
+	gorm.Model
+	ServiceId       uint    `json:"service_id" gorm:"not null;index"`
+	PayerType       string  `json:"payer_type" gorm:"not null"`
+	InsurerName     string  `json:"insurer_name"`
+	CoveragePercent int     `json:"coverage_percent" gorm:"not null"`
+	MaxAmount       int     `json:"max_amount" gorm:"not null;default:0"`
+	CreatedBy       uint    `json:"created_by" gorm:"not null"`
+	UpdatedBy       uint    `json:"updated_by"`
+	Service         Service `json:"-" gorm:"foreignKey:ServiceId;references:ID"`
+}
+
+type InsuranceClaim struct {
+	gorm.Model
+	BookingId       uint       `json:"booking_id" gorm:"not null;uniqueIndex"`
+	UserId          uint       `json:"user_id" gorm:"not null;index"`
+	ServiceId       uint       `json:"service_id" gorm:"not null"`
+	PayerType       string     `json:"payer_type" gorm:"not null;index"`
+	InsurerName     string     `json:"insurer_name"`
+	PolicyNumber    string     `json:"policy_number" gorm:"not null"`
+	BilledAmount    int        `json:"billed_amount" gorm:"not null"`
+	CoveredAmount   int        `json:"covered_amount" gorm:"not null"`
+	Status          string     `json:"status" gorm:"not null;default:draft;index"`
+	BatchId         *uint      `json:"batch_id" gorm:"index"`
+	RejectionReason string     `json:"rejection_reason"`
+	SubmittedAt     *time.Time `json:"submitted_at"`
+	DecidedAt       *time.Time `json:"decided_at"`
+	CreatedBy       uint       `json:"created_by" gorm:"not null"`
+	UpdatedBy       uint       `json:"updated_by"`
+	Booking         Booking    `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type ClaimBatch struct {
+	gorm.Model
+	BatchNumber string           `json:"batch_number" gorm:"not null;unique"`
+	PayerType   string           `json:"payer_type" gorm:"not null"`
+	InsurerName string           `json:"insurer_name"`
+	ClaimCount  int              `json:"claim_count" gorm:"not null"`
+	TotalAmount int              `json:"total_amount" gorm:"not null"`
+	CreatedBy   uint             `json:"created_by" gorm:"not null"`
+	Claims      []InsuranceClaim `json:"-" gorm:"foreignKey:BatchId;references:ID"`
+}
+
+type ServiceCoverageRequest struct {
+	PayerType       string `json:"payer_type"`
+	InsurerName     string `json:"insurer_name"`
+	CoveragePercent int    `json:"coverage_percent"`
+	MaxAmount       int    `json:"max_amount"`
+}
+
+type ClaimStatusRequest struct {
+	Status          string `json:"status"`
+	RejectionReason string `json:"rejection_reason"`
+}
+
+type ClaimBatchRequest struct {
+	PayerType   string `json:"payer_type"`
+	InsurerName string `json:"insurer_name"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Invoice struct {
+	gorm.Model
+	InvoiceNumber  string        `json:"invoice_number" gorm:"not null;unique"`
+	BookingId      uint          `json:"booking_id" gorm:"not null;uniqueIndex"`
+	UserId         uint          `json:"user_id" gorm:"not null;index"`
+	Subtotal       int           `json:"subtotal" gorm:"not null"`
+	DiscountAmount int           `json:"discount_amount" gorm:"not null;default:0"`
+	TaxPercent     float64       `json:"tax_percent" gorm:"not null;default:0"`
+	TaxAmount      int           `json:"tax_amount" gorm:"not null;default:0"`
+	Total          int           `json:"total" gorm:"not null"`
+	Status         string        `json:"status" gorm:"not null;default:unpaid;index"`
+	IssuedAt       time.Time     `json:"issued_at" gorm:"not null"`
+	PaidAt         *time.Time    `json:"paid_at"`
+	CreatedBy      uint          `json:"created_by" gorm:"not null"`
+	UpdatedBy      uint          `json:"updated_by"`
+	Items          []InvoiceItem `json:"items" gorm:"foreignKey:InvoiceId;references:ID"`
+	Booking        Booking       `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+	User           User          `json:"-" gorm:"foreignKey:UserId;references:ID"`
+}
+
+type InvoiceItem struct {
+	gorm.Model
+	InvoiceId   uint   `json:"invoice_id" gorm:"not null;index"`
+	ItemType    string `json:"item_type" gorm:"not null"`
+	Description string `json:"description" gorm:"not null"`
+	Quantity    int    `json:"quantity" gorm:"not null"`
+	UnitPrice   int    `json:"unit_price" gorm:"not null"`
+	Amount      int    `json:"amount" gorm:"not null"`
+	CreatedBy   uint   `json:"created_by" gorm:"not null"`
+}
+
+// InvoiceSequence holds the last invoice number issued in a period so
+// numbers stay sequential without gaps.
+type InvoiceSequence struct {
+	Period     string `gorm:"primaryKey;size:6"`
+	LastNumber int    `gorm:"not null"`
+}
+
+type InvoiceItemRequest struct {
+	ItemType    string `json:"item_type"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int    `json:"unit_price"`
+}
+
+type InvoiceUpdateRequest struct {
+	DiscountAmount *int     `json:"discount_amount"`
+	TaxPercent     *float64 `json:"tax_percent"`
+	Status         string   `json:"status"`
+}
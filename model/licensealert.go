@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LicenseAlert struct {
+	gorm.Model
+	DoctorId  uint      `json:"doctor_id" gorm:"not null;uniqueIndex:idx_license_alert"`
+	License   string    `json:"license" gorm:"size:3;not null;uniqueIndex:idx_license_alert"`
+	Kind      string    `json:"kind" gorm:"size:10;not null;uniqueIndex:idx_license_alert"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;uniqueIndex:idx_license_alert"`
+	Doctor    Doctor    `json:"-" gorm:"foreignKey:DoctorId;references:ID"`
+}
+
+type LicenseAlertResponse struct {
+	ID         uint      `json:"id"`
+	DoctorID   uint      `json:"doctor_id"`
+	DoctorName string    `json:"doctor_name"`
+	License    string    `json:"license"`
+	Kind       string    `json:"kind"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
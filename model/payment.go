@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Payment struct {
+	gorm.Model
+	BookingId   uint       `json:"booking_id" gorm:"not null;index"`
+	UserId      uint       `json:"user_id" gorm:"not null;index"`
+	Amount      int        `json:"amount" gorm:"not null"`
+	Method      string     `json:"method" gorm:"not null"`
+	Provider    string     `json:"provider" gorm:"not null;uniqueIndex:idx_provider_ref;size:50"`
+	ProviderRef string     `json:"provider_ref" gorm:"not null;uniqueIndex:idx_provider_ref;size:100"`
+	Reference   string     `json:"reference"`
+	Status      string     `json:"status" gorm:"not null;default:pending"`
+	VANumber    string     `json:"va_number"`
+	RedirectURL string     `json:"redirect_url"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	PaidAt      *time.Time `json:"paid_at"`
+	CreatedBy   uint       `json:"created_by" gorm:"not null"`
+	UpdatedBy   uint       `json:"updated_by"`
+	Booking     Booking    `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type ManualPaymentRequest struct {
+	Method    string `json:"method"`
+	Amount    int    `json:"amount"`
+	Reference string `json:"reference"`
+}
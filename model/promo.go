@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Promo struct {
+	gorm.Model
+	Code              string    `json:"code" gorm:"not null;unique;size:50"`
+	Description       string    `json:"description" gorm:"type:text"`
+	DiscountType      string    `json:"discount_type" gorm:"not null"`
+	DiscountValue     int       `json:"discount_value" gorm:"not null"`
+	ValidFrom         time.Time `json:"valid_from" gorm:"not null"`
+	ValidUntil        time.Time `json:"valid_until" gorm:"not null"`
+	ServiceId         *uint     `json:"service_id"`
+	DoctorId          *uint     `json:"doctor_id"`
+	MaxUses           int       `json:"max_uses" gorm:"not null;default:0"`
+	MaxUsesPerPatient int       `json:"max_uses_per_patient" gorm:"not null;default:0"`
+	FirstVisitOnly    bool      `json:"first_visit_only" gorm:"not null;default:false"`
+	IsActive          bool      `json:"is_active" gorm:"not null"`
+	CreatedBy         uint      `json:"created_by" gorm:"not null"`
+	UpdatedBy         uint      `json:"updated_by"`
+}
+
+type PromoUsage struct {
+	gorm.Model
+	PromoId        uint    `json:"promo_id" gorm:"not null;index"`
+	BookingId      uint    `json:"booking_id" gorm:"not null;uniqueIndex"`
+	UserId         uint    `json:"user_id" gorm:"not null;index"`
+	DiscountAmount int     `json:"discount_amount" gorm:"not null"`
+	Promo          Promo   `json:"-" gorm:"foreignKey:PromoId;references:ID"`
+	Booking        Booking `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type PromoRequest struct {
+	Code              string `json:"code"`
+	Description       string `json:"description"`
+	DiscountType      string `json:"discount_type"`
+	DiscountValue     int    `json:"discount_value"`
+	ValidFrom         string `json:"valid_from" time_format:"2006-01-02"`
+	ValidUntil        string `json:"valid_until" time_format:"2006-01-02"`
+	ServiceId         *uint  `json:"service_id"`
+	DoctorId          *uint  `json:"doctor_id"`
+	MaxUses           int    `json:"max_uses"`
+	MaxUsesPerPatient int    `json:"max_uses_per_patient"`
+	FirstVisitOnly    bool   `json:"first_visit_only"`
+	IsActive          *bool  `json:"is_active"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Refund struct {
+	gorm.Model
+	PaymentId   uint       `json:"payment_id" gorm:"not null;index"`
+	BookingId   uint       `json:"booking_id" gorm:"not null;index"`
+	Amount      int        `json:"amount" gorm:"not null"`
+	Percent     int        `json:"percent" gorm:"not null"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status" gorm:"not null;default:pending_approval;index"`
+	RefundRef   string     `json:"refund_ref"`
+	ApprovedBy  uint       `json:"approved_by"`
+	ProcessedAt *time.Time `json:"processed_at"`
+	CreatedBy   uint       `json:"created_by" gorm:"not null"`
+	UpdatedBy   uint       `json:"updated_by"`
+	Payment     Payment    `json:"-" gorm:"foreignKey:PaymentId;references:ID"`
+}
+
+type CancelBookingRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+type Resource struct {
+	gorm.Model
+	Name        string    `json:"name" gorm:"not null"`
+	Type        string    `json:"type" gorm:"not null;index"`
+	Description string    `json:"description" gorm:"type:text"`
+	IsActive    bool      `json:"is_active" gorm:"not null;default:true"`
+	ClinicId    uint      `json:"clinic_id" gorm:"index"`
+	CreatedBy   uint      `json:"created_by" gorm:"not null"`
+	UpdatedBy   uint      `json:"updated_by"`
+	Services    []Service `json:"-" gorm:"many2many:service_resources;"`
+}
+
+type ResourceRequest struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"is_active"`
+	ClinicID    uint   `json:"clinic_id"`
+}
+
+type ServiceResourceRequest struct {
+	ResourceID uint `json:"resource_id"`
+}
+
+type ResourceResponse struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
+	ClinicID    uint   `json:"clinic_id"`
+}
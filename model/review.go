@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Review struct {
+	gorm.Model
+	BookingId    uint       `json:"booking_id" gorm:"not null;uniqueIndex"`
+	DoctorId     uint       `json:"doctor_id" gorm:"not null;index"`
+	UserId       uint       `json:"user_id" gorm:"not null"`
+	Rating       int        `json:"rating" gorm:"not null"`
+	Comment      string     `json:"comment" gorm:"type:text"`
+	IsHidden     bool       `json:"is_hidden" gorm:"not null;default:false"`
+	HiddenReason string     `json:"hidden_reason"`
+	HiddenBy     uint       `json:"hidden_by"`
+	Reply        string     `json:"reply" gorm:"type:text"`
+	RepliedAt    *time.Time `json:"replied_at"`
+	CreatedBy    uint       `json:"created_by" gorm:"not null"`
+	UpdatedBy    uint       `json:"updated_by"`
+	User         User       `json:"-" gorm:"foreignKey:UserId;references:ID"`
+	Doctor       Doctor     `json:"-" gorm:"foreignKey:DoctorId;references:ID"`
+	Booking      Booking    `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+}
+
+type ReviewRequest struct {
+	Rating  int    `json:"rating"`
+	Comment string `json:"comment"`
+}
+
+type ReviewReplyRequest struct {
+	Reply string `json:"reply"`
+}
+
+type ReviewModerationRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+type ReviewResponse struct {
+	ID           uint       `json:"id"`
+	BookingID    uint       `json:"booking_id"`
+	DoctorID     uint       `json:"doctor_id"`
+	PatientName  string     `json:"patient_name"`
+	Rating       int        `json:"rating"`
+	Comment      string     `json:"comment"`
+	IsHidden     bool       `json:"is_hidden,omitempty"`
+	HiddenReason string     `json:"hidden_reason,omitempty"`
+	Reply        string     `json:"reply,omitempty"`
+	RepliedAt    *time.Time `json:"replied_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type RatingSummary struct {
+	DoctorId      uint    `json:"doctor_id"`
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int64   `json:"review_count"`
+}
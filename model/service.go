@@ -13,12 +13,26 @@ type Service struct {
 	UpdatedBy       uint             `json:"updated_by"`
 	Bookings        []Booking        `json:"-" gorm:"foreignKey:ServiceId;references:ID"`
 	Schedules       []DoctorSchedule `json:"doctor_schedule" gorm:"foreignKey:ServiceId;references:ID"`
+	Doctors         []Doctor         `json:"-" gorm:"many2many:doctor_services;"`
+	Resources       []Resource       `json:"-" gorm:"many2many:service_resources;"`
+	ClinicId        *uint            `json:"clinic_id" gorm:"index"`
 }
 
 type ServiceResponse struct {
+	ID              uint   `json:"id"`
 	Name            string `json:"name"`
 	Description     string `json:"description"`
 	Price           int    `json:"price"`
 	DurationMinutes int    `json:"duration_minutes"`
 	IsActive        bool   `json:"is_active"`
+	ClinicID        *uint  `json:"clinic_id"`
+}
+
+type CatalogueServiceResponse struct {
+	ID              uint   `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Price           int    `json:"price"`
+	DurationMinutes int    `json:"duration_minutes"`
+	NextAvailable   string `json:"next_available_date,omitempty"`
 }
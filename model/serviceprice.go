@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ServicePrice is the price of a service from EffectiveFrom onwards. Entries
+// with a DoctorId only apply to bookings with that doctor.
+type ServicePrice struct {
+	gorm.Model
+	ServiceId     uint      `json:"service_id" gorm:"not null;index:idx_service_price_lookup"`
+	DoctorId      *uint     `json:"doctor_id" gorm:"index:idx_service_price_lookup"`
+	Price         int       `json:"price" gorm:"not null"`
+	EffectiveFrom time.Time `json:"effective_from" gorm:"not null;index:idx_service_price_lookup"`
+	CreatedBy     uint      `json:"created_by" gorm:"not null"`
+	UpdatedBy     uint      `json:"updated_by"`
+	Service       Service   `json:"-" gorm:"foreignKey:ServiceId;references:ID"`
+}
+
+type ServicePriceRequest struct {
+	Price         int    `json:"price"`
+	DoctorId      *uint  `json:"doctor_id"`
+	EffectiveFrom string `json:"effective_from" time_format:"2006-01-02"`
+}
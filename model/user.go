@@ -11,6 +11,7 @@ type User struct {
 	Email     string    `json:"email" gorm:"unique;not null"`
 	Password  string    `json:"password" gorm:"not null"`
 	Role      string    `json:"role" gorm:"not null,default:'patient'"`
+	ClinicId  *uint     `json:"clinic_id" gorm:"index"`
 	CreatedBy uint      `json:"created_by" gorm:"not null"`
 	UpdatedBy uint      `json:"updated_by"`
 	Booking   []Booking `json:"-" gorm:"foreignKey:UserId"`
@@ -22,12 +23,21 @@ type UserResponse struct {
 	Email string `json:"email"`
 }
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	ClinicID uint   `json:"clinic_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// RegisterRequest is the account a caller may create for themselves. The
+// role and clinic are never taken from it.
+type RegisterRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
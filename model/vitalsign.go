@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type VitalSign struct {
+	gorm.Model
+	BookingId   uint      `json:"booking_id" gorm:"not null;index"`
+	UserId      uint      `json:"user_id" gorm:"not null;index"`
+	Systolic    int       `json:"systolic" gorm:"not null"`
+	Diastolic   int       `json:"diastolic" gorm:"not null"`
+	Temperature float64   `json:"temperature" gorm:"not null"`
+	WeightKg    float64   `json:"weight_kg" gorm:"not null"`
+	HeightCm    float64   `json:"height_cm" gorm:"not null"`
+	Pulse       int       `json:"pulse" gorm:"not null"`
+	SpO2        int       `json:"spo2" gorm:"not null"`
+	BMI         float64   `json:"bmi" gorm:"not null"`
+	Flags       string    `json:"flags"`
+	RecordedAt  time.Time `json:"recorded_at" gorm:"not null"`
+	CreatedBy   uint      `json:"created_by" gorm:"not null"`
+	UpdatedBy   uint      `json:"updated_by"`
+	Booking     Booking   `json:"-" gorm:"foreignKey:BookingId;references:ID"`
+	User        User      `json:"-" gorm:"foreignKey:UserId;references:ID"`
+}
+
+type VitalSignRequest struct {
+	Systolic        int     `json:"systolic"`
+	Diastolic       int     `json:"diastolic"`
+	Temperature     float64 `json:"temperature"`
+	TemperatureUnit string  `json:"temperature_unit"`
+	Weight          float64 `json:"weight"`
+	WeightUnit      string  `json:"weight_unit"`
+	Height          float64 `json:"height"`
+	HeightUnit      string  `json:"height_unit"`
+	Pulse           int     `json:"pulse"`
+	SpO2            int     `json:"spo2"`
+}
+
+type VitalSignResponse struct {
+	ID          uint      `json:"id"`
+	BookingID   uint      `json:"booking_id"`
+	Systolic    int       `json:"systolic"`
+	Diastolic   int       `json:"diastolic"`
+	Temperature float64   `json:"temperature"`
+	WeightKg    float64   `json:"weight_kg"`
+	HeightCm    float64   `json:"height_cm"`
+	Pulse       int       `json:"pulse"`
+	SpO2        int       `json:"spo2"`
+	BMI         float64   `json:"bmi"`
+	Flags       []string  `json:"flags"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
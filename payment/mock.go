@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// MockProvider is a local stand-in for a payment gateway. Charges only get a
+// virtual account number and are never settled on their own; post a webhook
+// signed with Sign to complete them.
+type MockProvider struct {
+	Secret string
+}
+
+type mockWebhookPayload struct {
+	ProviderRef string `json:"provider_ref"`
+	Status      string `json:"status"`
+	Amount      int    `json:"amount"`
+}
+
+func (p *MockProvider) Name() string {
+	return "mock"
+}
+
+func (p *MockProvider) CreateCharge(request ChargeRequest) (*Charge, error) {
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+	ref := "MOCK-" + strings.ToUpper(hex.EncodeToString(random))
+
+	vaSuffix, err := rand.Int(rand.Reader, big.NewInt(1_000_000_0000))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Charge{
+		ProviderRef: ref,
+		VANumber:    fmt.Sprintf("8808%010d", vaSuffix.Int64()),
+		ExpiresAt:   time.Now().Add(24 * time.Hour),
+	}, nil
+}
+
+func (p *MockProvider) ParseWebhook(body []byte, signature string) (*WebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload mockWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, err
+	}
+
+	return &WebhookEvent{ProviderRef: payload.ProviderRef, Status: payload.Status, Amount: payload.Amount}, nil
+}
+
+func (p *MockProvider) Refund(request RefundRequest) (*RefundResult, error) {
+	if request.Amount <= 0 {
+		return nil, errors.New("refund amount must be greater than 0")
+	}
+
+	random := make([]byte, 8)
+	if _, err := rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	return &RefundResult{RefundRef: "MOCKRF-" + strings.ToUpper(hex.EncodeToString(random)), Status: "succeeded"}, nil
+}
+
+// Sign returns the hex encoded signature the mock expects in the
+// X-Signature header for body.
+func (p *MockProvider) Sign(body []byte) string {
+	return hex.EncodeToString(p.sign(body))
+}
+
+func (p *MockProvider) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
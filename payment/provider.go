@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+type ChargeRequest struct {
+	Reference     string
+	Amount        int
+	CustomerName  string
+	CustomerEmail string
+	Description   string
+}
+
+// Charge is a payment started at the provider. RedirectURL is the provider's
+// payment page, empty when the patient pays by VANumber only.
+type Charge struct {
+	ProviderRef string
+	RedirectURL string
+	VANumber    string
+	ExpiresAt   time.Time
+}
+
+// WebhookEvent is the provider independent result of a payment callback.
+// Status is one of paid, failed or expired.
+type WebhookEvent struct {
+	ProviderRef string
+	Status      string
+	Amount      int
+}
+
+type RefundRequest struct {
+	ProviderRef string
+	Amount      int
+	Reason      string
+}
+
+// RefundResult reports the outcome of a refund. Status is one of succeeded,
+// pending or failed.
+type RefundResult struct {
+	RefundRef string
+	Status    string
+}
+
+// Provider is implemented by every payment gateway the clinic can take
+// online payments through.
+type Provider interface {
+	Name() string
+	CreateCharge(request ChargeRequest) (*Charge, error)
+	ParseWebhook(body []byte, signature string) (*WebhookEvent, error)
+	Refund(request RefundRequest) (*RefundResult, error)
+}
+
+// NewProvider returns the payment gateway called name, verifying webhooks
+// with secret.
+func NewProvider(name string, secret string) (Provider, error) {
+	switch name {
+	case "mock":
+		return &MockProvider{Secret: secret}, nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", name)
+}
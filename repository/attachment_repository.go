@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *model.Attachment) error
+	GetAttachmentById(ctx context.Context, id uint) (*model.Attachment, error)
+	GetAttachmentsByBookingId(ctx context.Context, bookingId uint) ([]model.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID uint, userID uint) error
+}
+
+type AttachmentRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *AttachmentRepositoryImpl) CreateAttachment(ctx context.Context, attachment *model.Attachment) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(attachment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *AttachmentRepositoryImpl) GetAttachmentById(ctx context.Context, id uint) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := r.DB.WithContext(ctx).First(&attachment, id).Error; err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *AttachmentRepositoryImpl) GetAttachmentsByBookingId(ctx context.Context, bookingId uint) ([]model.Attachment, error) {
+	var attachments []model.Attachment
+	if err := r.DB.WithContext(ctx).Where("booking_id = ?", bookingId).Order("created_at asc").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepositoryImpl) DeleteAttachment(ctx context.Context, attachmentID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	var attachment model.Attachment
+	if err := tx.First(&attachment, attachmentID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	attachment.UpdatedBy = userID
+
+	if err := tx.Save(&attachment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&model.Attachment{}, attachmentID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
@@ -2,21 +2,53 @@ package repository
 
 import (
 	"booking-klinik/model"
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// BookingConflictError is returned when the doctor, or one of the resources
+// of a booking, is already held by another booking at that time.
+type BookingConflictError struct {
+	// Resource is the resource in use, or nil when the doctor is booked.
+	Resource *model.Resource
+	// Until is when the conflicting booking ends.
+	Until time.Time
+}
+
+func (e *BookingConflictError) Error() string {
+	if e.Resource != nil {
+		return fmt.Sprintf("%s is already in use until %s", e.Resource.Name, e.Until.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("doctor is already booked until %s", e.Until.Format(time.RFC3339))
+}
+
+// PromoRedemption is the promo usage to record with a new booking and the
+// limits it has to stay within.
+type PromoRedemption struct {
+	Usage             model.PromoUsage
+	MaxUses           int
+	MaxUsesPerPatient int
+}
+
 type BookingRepository interface {
-	CreateBooking(booking *model.Booking) error
-	GetAllBookings(limit, offset int) ([]model.Booking, int64, error)
-	GetBookingById(id uint) (*model.Booking, error)
-	GetBookingsByUserId(userId uint, limit, offset int) ([]model.Booking, int64, error)
-	GetBookingsByDoctorId(doctorId uint, limit, offset int) ([]model.Booking, int64, error)
-	GetBookingsByDoctorAndDate(doctorId uint, bookingDate time.Time) ([]model.Booking, error)
-	UpdateBooking(bookingID uint, booking model.Booking) (*model.Booking, error)
-	DeleteBooking(bookingID uint, userID uint) error
+	CreateBooking(ctx context.Context, booking *model.Booking, redemption *PromoRedemption) error
+	GetAllBookings(ctx context.Context, clinicId uint, limit, offset int) ([]model.Booking, int64, error)
+	GetBookingById(ctx context.Context, id uint) (*model.Booking, error)
+	GetBookingsByUserId(ctx context.Context, userId, doctorId, clinicId uint, limit, offset int) ([]model.Booking, int64, error)
+	GetBookingsByDoctorId(ctx context.Context, doctorId, clinicId uint, limit, offset int) ([]model.Booking, int64, error)
+	UpdateBooking(ctx context.Context, bookingID uint, booking model.Booking) (*model.Booking, error)
+	RescheduleBooking(ctx context.Context, booking *model.Booking) error
+	ConfirmPendingBooking(ctx context.Context, bookingID uint, userID uint) error
+	DeleteBooking(ctx context.Context, bookingID uint, userID uint) error
+	GetActiveBookingsByDoctorsBetween(ctx context.Context, doctorIds []uint, from, to time.Time) ([]model.Booking, error)
+	CountOpenBookingsByDoctor(ctx context.Context, clinicId uint, from, to time.Time) (map[uint]int64, error)
+	HasPatientBooking(ctx context.Context, userId, doctorId, clinicId uint) (bool, error)
 }
 
 type BookingRepositoryImpl struct {
@@ -24,92 +56,211 @@ type BookingRepositoryImpl struct {
 	ServiceRepository ServiceRepository
 }
 
-func (r *BookingRepositoryImpl) CreateBooking(booking *model.Booking) error {
-	tx := r.DB.Begin()
-	defer tx.Commit()
+// CreateBooking stores the booking with its resources and promo usage in one
+// transaction. The doctor and the resources are checked for overlapping
+// bookings while locked, so concurrent requests cannot take the same slot.
+func (r *BookingRepositoryImpl) CreateBooking(ctx context.Context, booking *model.Booking, redemption *PromoRedemption) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSlot(tx, booking); err != nil {
+			return err
+		}
 
-	if err := tx.Create(booking).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+		// Resources already exist; only the booking_resources rows are written
+		if err := tx.Omit("Resources.*").Create(booking).Error; err != nil {
+			return err
+		}
 
-	if err := tx.Preload("User").Preload("Service").First(booking, booking.ID).Error; err != nil {
-		tx.Rollback()
-		return err
-	}
+		if redemption != nil {
+			redemption.Usage.BookingId = booking.ID
+			if err := createPromoUsage(tx, &redemption.Usage, redemption.MaxUses, redemption.MaxUsesPerPatient); err != nil {
+				return err
+			}
+		}
 
-	return nil
+		return tx.Preload("User").Preload("Service").First(booking, booking.ID).Error
+	})
 }
 
-func (r *BookingRepositoryImpl) GetAllBookings(limit, offset int) ([]model.Booking, int64, error) {
+func (r *BookingRepositoryImpl) GetAllBookings(ctx context.Context, clinicId uint, limit, offset int) ([]model.Booking, int64, error) {
 	var bookings []model.Booking
 	var totalRows int64
 
-	if err := r.DB.Model(&model.Booking{}).Count(&totalRows).Error; err != nil {
+	query := r.DB.WithContext(ctx).Model(&model.Booking{})
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.DB.Limit(limit).Offset(offset).Preload("User").Preload("Service").Find(&bookings).Error; err != nil {
+	if err := query.Limit(limit).Offset(offset).Preload("User").Preload("Service").Find(&bookings).Error; err != nil {
 		return nil, 0, err
 	}
 	return bookings, totalRows, nil
 }
 
-func (r *BookingRepositoryImpl) GetBookingById(id uint) (*model.Booking, error) {
+func (r *BookingRepositoryImpl) GetBookingById(ctx context.Context, id uint) (*model.Booking, error) {
 	var booking model.Booking
-	if err := r.DB.Preload("User").Preload("Service").First(&booking, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("User").Preload("Service").First(&booking, id).Error; err != nil {
 		return nil, err
 	}
 	return &booking, nil
 }
 
-func (r *BookingRepositoryImpl) GetBookingsByUserId(userId uint, limit, offset int) ([]model.Booking, int64, error) {
+// GetBookingsByUserId lists the patient's bookings with the doctor and at the
+// clinic; a zero doctorId or clinicId matches any.
+func (r *BookingRepositoryImpl) GetBookingsByUserId(ctx context.Context, userId, doctorId, clinicId uint, limit, offset int) ([]model.Booking, int64, error) {
 	var bookings []model.Booking
 	var totalRows int64
-	if err := r.DB.Model(&model.Booking{}).Where("user_id = ?", userId).Count(&totalRows).Error; err != nil {
+
+	query := r.DB.WithContext(ctx).Model(&model.Booking{}).Where("user_id = ?", userId)
+	if doctorId != 0 {
+		query = query.Where("doctor_id = ?", doctorId)
+	}
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
 		return nil, 0, err
 	}
-	if err := r.DB.Preload("Doctor").Preload("User").Preload("Service").Where("user_id = ?", userId).Limit(limit).Offset(offset).Find(&bookings).Error; err != nil {
+	if err := query.Preload("Doctor").Preload("User").Preload("Service").Limit(limit).Offset(offset).Find(&bookings).Error; err != nil {
 		return nil, 0, err
 	}
 	return bookings, totalRows, nil
 }
 
-func (r *BookingRepositoryImpl) GetBookingsByDoctorId(doctorId uint, limit, offset int) ([]model.Booking, int64, error) {
+// GetBookingsByDoctorId lists the doctor's bookings at the clinic, or at every
+// clinic when clinicId is 0.
+func (r *BookingRepositoryImpl) GetBookingsByDoctorId(ctx context.Context, doctorId, clinicId uint, limit, offset int) ([]model.Booking, int64, error) {
 	var bookings []model.Booking
 	var totalRows int64
-	if err := r.DB.Model(&model.Booking{}).Where("doctor_id = ?", doctorId).Count(&totalRows).Error; err != nil {
+
+	query := r.DB.WithContext(ctx).Model(&model.Booking{}).Where("doctor_id = ?", doctorId)
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
 		return nil, 0, err
 	}
-	if err := r.DB.Preload("Doctor").Preload("User").Preload("Service").Where("doctor_id = ?", doctorId).Limit(limit).Offset(offset).Find(&bookings).Error; err != nil {
+	if err := query.Preload("Doctor").Preload("User").Preload("Service").Limit(limit).Offset(offset).Find(&bookings).Error; err != nil {
 		return nil, 0, err
 	}
-	fmt.Println(doctorId)
 	return bookings, totalRows, nil
 }
 
-func (r *BookingRepositoryImpl) UpdateBooking(bookingID uint, booking model.Booking) (*model.Booking, error) {
-	tx := r.DB.Begin()
-	defer tx.Commit()
+func (r *BookingRepositoryImpl) UpdateBooking(ctx context.Context, bookingID uint, booking model.Booking) (*model.Booking, error) {
 	var existingBooking model.Booking
-	if err := r.DB.First(&existingBooking, bookingID).Error; err != nil {
-		tx.Rollback()
-		return nil, err
-	}
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&existingBooking, bookingID).Error; err != nil {
+			return err
+		}
 
-	existingBooking.Status = booking.Status
-	existingBooking.Notes = booking.Notes
+		existingBooking.Status = booking.Status
+		existingBooking.Notes = booking.Notes
+		existingBooking.UpdatedBy = booking.UpdatedBy
 
-	if err := r.DB.Save(&existingBooking).Error; err != nil {
-		tx.Rollback()
+		return tx.Save(&existingBooking).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &existingBooking, nil
 }
 
-func (r *BookingRepositoryImpl) DeleteBooking(bookingID uint, userID uint) error {
-	tx := r.DB.Begin()
+// ConfirmPendingBooking confirms the booking if it is still pending. The
+// status is checked in the update itself so a booking cancelled meanwhile
+// stays cancelled.
+func (r *BookingRepositoryImpl) ConfirmPendingBooking(ctx context.Context, bookingID uint, userID uint) error {
+	return r.DB.WithContext(ctx).Model(&model.Booking{}).Where("id = ? AND status = ?", bookingID, "pending").Updates(map[string]interface{}{
+		"status":     "confirmed",
+		"updated_by": userID,
+	}).Error
+}
+
+// RescheduleBooking moves a booking to its StartAt and EndAt, with the clinic
+// and resources of its new schedule. The slot is checked under the same locks
+// as in CreateBooking.
+func (r *BookingRepositoryImpl) RescheduleBooking(ctx context.Context, booking *model.Booking) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSlot(tx, booking); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.Booking{}).Where("id = ?", booking.ID).Updates(map[string]interface{}{
+			"start_at":   booking.StartAt,
+			"end_at":     booking.EndAt,
+			"clinic_id":  booking.ClinicId,
+			"notes":      booking.Notes,
+			"updated_by": booking.UpdatedBy,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM booking_resources WHERE booking_id = ?", booking.ID).Error; err != nil {
+			return err
+		}
+		for _, resource := range booking.Resources {
+			if err := tx.Exec("INSERT INTO booking_resources (booking_id, resource_id) VALUES (?, ?)", booking.ID, resource.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkSlot locks the booking's doctor and resources and fails with a
+// BookingConflictError when another active booking overlaps it. The doctor is
+// locked before the resources, in ID order, so transactions cannot deadlock.
+func checkSlot(tx *gorm.DB, booking *model.Booking) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Doctor{}, booking.DoctorId).Error; err != nil {
+		return err
+	}
+
+	var overlapping model.Booking
+	err := tx.Where("doctor_id = ? AND id != ? AND start_at < ? AND end_at > ? AND status != ?", booking.DoctorId, booking.ID, booking.EndAt, booking.StartAt, "cancelled").
+		Order("end_at desc").First(&overlapping).Error
+	if err == nil {
+		return &BookingConflictError{Until: overlapping.EndAt}
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if len(booking.Resources) == 0 {
+		return nil
+	}
+	resourceIds := make([]uint, 0, len(booking.Resources))
+	for _, resource := range booking.Resources {
+		resourceIds = append(resourceIds, resource.ID)
+	}
+	sort.Slice(resourceIds, func(i, j int) bool { return resourceIds[i] < resourceIds[j] })
+
+	var resources []model.Resource
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", resourceIds).Order("id").Find(&resources).Error; err != nil {
+		return err
+	}
+
+	var bookings []model.Booking
+	if err := tx.Where("id IN (?)", tx.Table("booking_resources").Select("booking_id").Where("resource_id IN ?", resourceIds)).
+		Where("id != ? AND start_at < ? AND end_at > ? AND status != ?", booking.ID, booking.EndAt, booking.StartAt, "cancelled").
+		Preload("Resources").Find(&bookings).Error; err != nil {
+		return err
+	}
+	for _, other := range bookings {
+		for _, resource := range other.Resources {
+			for _, wanted := range resources {
+				if resource.ID == wanted.ID {
+					return &BookingConflictError{Resource: &wanted, Until: other.EndAt}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (r *BookingRepositoryImpl) DeleteBooking(ctx context.Context, bookingID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 
 	var booking model.Booking
@@ -133,10 +284,51 @@ func (r *BookingRepositoryImpl) DeleteBooking(bookingID uint, userID uint) error
 	return nil
 }
 
-func (r *BookingRepositoryImpl) GetBookingsByDoctorAndDate(doctorId uint, bookingDate time.Time) ([]model.Booking, error) {
+// GetActiveBookingsByDoctorsBetween returns the active bookings of the doctors
+// overlapping [from, to).
+func (r *BookingRepositoryImpl) GetActiveBookingsByDoctorsBetween(ctx context.Context, doctorIds []uint, from, to time.Time) ([]model.Booking, error) {
 	var bookings []model.Booking
-	if err := r.DB.Preload("User").Preload("Service").Where("doctor_id = ? AND booking_date = ? AND status != ?", doctorId, bookingDate, "cancelled").Order("booking_time asc").Find(&bookings).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("doctor_id IN ? AND start_at < ? AND end_at > ? AND status != ?", doctorIds, to, from, "cancelled").Order("start_at asc").Find(&bookings).Error; err != nil {
 		return nil, err
 	}
 	return bookings, nil
 }
+
+// CountOpenBookingsByDoctor counts the pending and confirmed bookings at the
+// clinic starting in [from, to), per doctor.
+func (r *BookingRepositoryImpl) CountOpenBookingsByDoctor(ctx context.Context, clinicId uint, from, to time.Time) (map[uint]int64, error) {
+	var rows []struct {
+		DoctorId uint
+		Count    int64
+	}
+	if err := r.DB.WithContext(ctx).Model(&model.Booking{}).
+		Select("doctor_id, COUNT(*) AS count").
+		Where("clinic_id = ? AND start_at >= ? AND start_at < ? AND status IN ?", clinicId, from, to, []string{"pending", "confirmed"}).
+		Group("doctor_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.DoctorId] = row.Count
+	}
+	return counts, nil
+}
+
+// HasPatientBooking reports whether the patient has any booking with the
+// doctor and at the clinic; a zero doctorId or clinicId matches any.
+func (r *BookingRepositoryImpl) HasPatientBooking(ctx context.Context, userId, doctorId, clinicId uint) (bool, error) {
+	query := r.DB.WithContext(ctx).Model(&model.Booking{}).Where("user_id = ?", userId)
+	if doctorId != 0 {
+		query = query.Where("doctor_id = ?", doctorId)
+	}
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCreateBookingRejectsOverlappingDoctorBooking(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	existing := f.AddBooking(t, f.Doctor, f.Service, start, "confirmed")
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		conflict bool
+	}{
+		{"overlapping", start.Add(15 * time.Minute), true},
+		{"inside", start.Add(5 * time.Minute), true},
+		{"right after", start.Add(30 * time.Minute), false},
+		{"right before", start.Add(-30 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, ClinicId: f.Clinic.ID, StartAt: tt.start, EndAt: tt.start.Add(30 * time.Minute), Status: "pending"}
+			err := repo.CreateBooking(context.Background(), booking, nil)
+
+			var conflict *BookingConflictError
+			if !tt.conflict {
+				if err != nil {
+					t.Fatalf("CreateBooking() error = %v", err)
+				}
+				f.DB.Unscoped().Delete(booking)
+				return
+			}
+			if !errors.As(err, &conflict) {
+				t.Fatalf("CreateBooking() error = %v, want a BookingConflictError", err)
+			}
+			if conflict.Resource != nil || !conflict.Until.Equal(existing.EndAt) {
+				t.Errorf("conflict = %+v, want the doctor booked until %v", conflict, existing.EndAt)
+			}
+		})
+	}
+}
+
+func TestCreateBookingIgnoresCancelledBookings(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	f.AddBooking(t, f.Doctor, f.Service, start, "cancelled")
+
+	booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, ClinicId: f.Clinic.ID, StartAt: start, EndAt: start.Add(30 * time.Minute), Status: "pending"}
+	if err := repo.CreateBooking(context.Background(), booking, nil); err != nil {
+		t.Fatalf("CreateBooking() error = %v", err)
+	}
+}
+
+func TestCreateBookingRejectsResourceHeldByAnotherDoctor(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	xray := f.AddResource(t, "X-ray")
+	f.AddBooking(t, f.AddDoctor(t, "Budi", "Radiologist"), f.Service, start, "confirmed", xray)
+
+	booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, ClinicId: f.Clinic.ID, StartAt: start.Add(10 * time.Minute), EndAt: start.Add(40 * time.Minute), Status: "pending", Resources: []model.Resource{xray}}
+	err := repo.CreateBooking(context.Background(), booking, nil)
+
+	var conflict *BookingConflictError
+	if !errors.As(err, &conflict) || conflict.Resource == nil || conflict.Resource.ID != xray.ID {
+		t.Fatalf("CreateBooking() error = %v, want the X-ray in use", err)
+	}
+}
+
+func TestCreateBookingRollsBackWhenPromoIsUsedUp(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	var promo model.Promo
+	testutil.MustCreate(t, f.DB, &promo, model.Promo{Code: "ONCE", DiscountType: "fixed", DiscountValue: 10000, ValidFrom: time.Now(), ValidUntil: time.Now().AddDate(1, 0, 0), MaxUses: 1, IsActive: true})
+
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	for i, want := range []error{nil, ErrPromoUsageLimit} {
+		slotStart := start.Add(time.Duration(i) * time.Hour)
+		booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, ClinicId: f.Clinic.ID, StartAt: slotStart, EndAt: slotStart.Add(30 * time.Minute), Status: "pending"}
+		redemption := &PromoRedemption{Usage: model.PromoUsage{PromoId: promo.ID, UserId: f.Patient.ID, DiscountAmount: 10000}, MaxUses: promo.MaxUses}
+		if err := repo.CreateBooking(context.Background(), booking, redemption); !errors.Is(err, want) {
+			t.Fatalf("booking %d: CreateBooking() error = %v, want %v", i+1, err, want)
+		}
+	}
+
+	var bookings int64
+	f.DB.Model(&model.Booking{}).Count(&bookings)
+	if bookings != 1 {
+		t.Errorf("%d bookings stored, want only the one that redeemed the promo", bookings)
+	}
+}
+
+func TestRescheduleBookingMovesResources(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	oldRoom, newRoom := f.AddResource(t, "Room 1"), f.AddResource(t, "Room 2")
+	booking := f.AddBooking(t, f.Doctor, f.Service, start, "pending", oldRoom)
+
+	// Moving within its own slot must not conflict with itself
+	booking.StartAt = start.Add(15 * time.Minute)
+	booking.EndAt = booking.StartAt.Add(30 * time.Minute)
+	booking.Resources = []model.Resource{newRoom}
+	if err := repo.RescheduleBooking(context.Background(), &booking); err != nil {
+		t.Fatalf("RescheduleBooking() error = %v", err)
+	}
+
+	var stored model.Booking
+	if err := f.DB.Preload("Resources").First(&stored, booking.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !stored.StartAt.Equal(booking.StartAt) {
+		t.Errorf("StartAt = %v, want %v", stored.StartAt, booking.StartAt)
+	}
+	if len(stored.Resources) != 1 || stored.Resources[0].ID != newRoom.ID {
+		t.Errorf("Resources = %+v, want only %s", stored.Resources, newRoom.Name)
+	}
+}
+
+func TestConfirmPendingBookingLeavesCancelledBookings(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	pending := f.AddBooking(t, f.Doctor, f.Service, start, "pending")
+	cancelled := f.AddBooking(t, f.Doctor, f.Service, start.Add(time.Hour), "cancelled")
+
+	for _, booking := range []model.Booking{pending, cancelled} {
+		if err := repo.ConfirmPendingBooking(context.Background(), booking.ID, f.Patient.ID); err != nil {
+			t.Fatalf("ConfirmPendingBooking() error = %v", err)
+		}
+	}
+
+	for id, want := range map[uint]string{pending.ID: "confirmed", cancelled.ID: "cancelled"} {
+		var stored model.Booking
+		f.DB.First(&stored, id)
+		if stored.Status != want {
+			t.Errorf("booking %d status = %q, want %q", id, stored.Status, want)
+		}
+	}
+}
+
+func TestHasPatientBooking(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &BookingRepositoryImpl{DB: f.DB}
+	f.AddBooking(t, f.Doctor, f.Service, time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC), "completed")
+	other := f.AddDoctor(t, "Sari", "Dentist")
+
+	tests := []struct {
+		name               string
+		doctorId, clinicId uint
+		want               bool
+	}{
+		{"any", 0, 0, true},
+		{"own doctor", f.Doctor.ID, 0, true},
+		{"other doctor", other.ID, 0, false},
+		{"own clinic", 0, f.Clinic.ID, true},
+		{"other clinic", 0, f.Clinic.ID + 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.HasPatientBooking(context.Background(), f.Patient.ID, tt.doctorId, tt.clinicId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("HasPatientBooking() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
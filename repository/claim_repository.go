@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ClaimRepository interface {
+	CreateClaim(ctx context.Context, claim *model.InsuranceClaim) error
+	GetClaimById(ctx context.Context, id uint) (*model.InsuranceClaim, error)
+	GetClaimByBookingId(ctx context.Context, bookingId uint) (*model.InsuranceClaim, error)
+	GetClaims(ctx context.Context, status, payerType string, clinicId uint, limit, offset int) ([]model.InsuranceClaim, int64, error)
+	UpdateClaim(ctx context.Context, claim *model.InsuranceClaim) error
+	CreateBatch(ctx context.Context, batch *model.ClaimBatch, payerType, insurerName string) error
+	GetBatchById(ctx context.Context, id uint) (*model.ClaimBatch, error)
+}
+
+type ClaimRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *ClaimRepositoryImpl) CreateClaim(ctx context.Context, claim *model.InsuranceClaim) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(claim).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ClaimRepositoryImpl) GetClaimById(ctx context.Context, id uint) (*model.InsuranceClaim, error) {
+	var claim model.InsuranceClaim
+	if err := r.DB.WithContext(ctx).First(&claim, id).Error; err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *ClaimRepositoryImpl) GetClaimByBookingId(ctx context.Context, bookingId uint) (*model.InsuranceClaim, error) {
+	var claim model.InsuranceClaim
+	if err := r.DB.WithContext(ctx).Where("booking_id = ?", bookingId).First(&claim).Error; err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *ClaimRepositoryImpl) GetClaims(ctx context.Context, status, payerType string, clinicId uint, limit, offset int) ([]model.InsuranceClaim, int64, error) {
+	var claims []model.InsuranceClaim
+	var totalRows int64
+
+	query := r.DB.WithContext(ctx).Model(&model.InsuranceClaim{})
+	if status != "" {
+		query = query.Where("insurance_claims.status = ?", status)
+	}
+	if payerType != "" {
+		query = query.Where("insurance_claims.payer_type = ?", payerType)
+	}
+	if clinicId != 0 {
+		query = query.Joins("JOIN bookings ON bookings.id = insurance_claims.booking_id").Where("bookings.clinic_id = ?", clinicId)
+	}
+
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("insurance_claims.created_at asc").Limit(limit).Offset(offset).Find(&claims).Error; err != nil {
+		return nil, 0, err
+	}
+	return claims, totalRows, nil
+}
+
+func (r *ClaimRepositoryImpl) UpdateClaim(ctx context.Context, claim *model.InsuranceClaim) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Omit("Booking").Save(claim).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// CreateBatch collects every draft claim of the payer into a new batch and
+// marks them submitted. The batch totals are filled in from the claims.
+func (r *ClaimRepositoryImpl) CreateBatch(ctx context.Context, batch *model.ClaimBatch, payerType, insurerName string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var claims []model.InsuranceClaim
+		query := tx.Where("status = ? AND payer_type = ?", "draft", payerType)
+		if insurerName != "" {
+			query = query.Where("insurer_name = ?", insurerName)
+		}
+		if err := query.Find(&claims).Error; err != nil {
+			return err
+		}
+		if len(claims) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		batch.ClaimCount = len(claims)
+		batch.TotalAmount = 0
+		ids := make([]uint, 0, len(claims))
+		for _, claim := range claims {
+			batch.TotalAmount += claim.CoveredAmount
+			ids = append(ids, claim.ID)
+		}
+
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.InsuranceClaim{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       "submitted",
+			"batch_id":     batch.ID,
+			"submitted_at": time.Now(),
+			"updated_by":   batch.CreatedBy,
+		}).Error
+	})
+}
+
+func (r *ClaimRepositoryImpl) GetBatchById(ctx context.Context, id uint) (*model.ClaimBatch, error) {
+	var batch model.ClaimBatch
+	if err := r.DB.WithContext(ctx).Preload("Claims").Preload("Claims.Booking").Preload("Claims.Booking.User").Preload("Claims.Booking.Service").First(&batch, id).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
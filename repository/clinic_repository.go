@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type ClinicRepository interface {
+	CreateClinic(ctx context.Context, clinic *model.Clinic) error
+	GetAllClinics(ctx context.Context, activeOnly bool, limit, offset int) ([]model.Clinic, int64, error)
+	GetClinicById(ctx context.Context, id uint) (*model.Clinic, error)
+	UpdateClinic(ctx context.Context, clinic *model.Clinic) error
+	DeleteClinic(ctx context.Context, id uint, userID uint) error
+	AddDoctor(ctx context.Context, clinicID uint, doctorID uint) error
+	RemoveDoctor(ctx context.Context, clinicID uint, doctorID uint) error
+	HasDoctor(ctx context.Context, clinicID uint, doctorID uint) (bool, error)
+	GetClinicsByDoctorId(ctx context.Context, doctorID uint) ([]model.Clinic, error)
+	AssignAdmin(ctx context.Context, clinicID uint, userID uint) error
+}
+
+type ClinicRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *ClinicRepositoryImpl) CreateClinic(ctx context.Context, clinic *model.Clinic) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(clinic).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ClinicRepositoryImpl) GetAllClinics(ctx context.Context, activeOnly bool, limit, offset int) ([]model.Clinic, int64, error) {
+	var clinics []model.Clinic
+	var totalRows int64
+
+	query := r.DB.WithContext(ctx).Model(&model.Clinic{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("OpeningHours").Order("name asc").Limit(limit).Offset(offset).Find(&clinics).Error; err != nil {
+		return nil, 0, err
+	}
+	return clinics, totalRows, nil
+}
+
+func (r *ClinicRepositoryImpl) GetClinicById(ctx context.Context, id uint) (*model.Clinic, error) {
+	var clinic model.Clinic
+	if err := r.DB.WithContext(ctx).Preload("OpeningHours").First(&clinic, id).Error; err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
+// UpdateClinic saves the clinic and replaces its opening hours.
+func (r *ClinicRepositoryImpl) UpdateClinic(ctx context.Context, clinic *model.Clinic) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Omit("OpeningHours").Save(clinic).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("clinic_id = ?", clinic.ID).Delete(&model.ClinicOpeningHour{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range clinic.OpeningHours {
+		clinic.OpeningHours[i].ID = 0
+		clinic.OpeningHours[i].ClinicId = clinic.ID
+	}
+	if len(clinic.OpeningHours) > 0 {
+		if err := tx.Create(&clinic.OpeningHours).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ClinicRepositoryImpl) DeleteClinic(ctx context.Context, id uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Model(&model.Clinic{}).Where("id = ?", id).Update("updated_by", userID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&model.Clinic{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ClinicRepositoryImpl) AddDoctor(ctx context.Context, clinicID uint, doctorID uint) error {
+	clinic := model.Clinic{Model: gorm.Model{ID: clinicID}}
+	return r.DB.WithContext(ctx).Model(&clinic).Association("Doctors").Append(&model.Doctor{Model: gorm.Model{ID: doctorID}})
+}
+
+func (r *ClinicRepositoryImpl) RemoveDoctor(ctx context.Context, clinicID uint, doctorID uint) error {
+	clinic := model.Clinic{Model: gorm.Model{ID: clinicID}}
+	return r.DB.WithContext(ctx).Model(&clinic).Association("Doctors").Delete(&model.Doctor{Model: gorm.Model{ID: doctorID}})
+}
+
+func (r *ClinicRepositoryImpl) HasDoctor(ctx context.Context, clinicID uint, doctorID uint) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Table("doctor_clinics").Where("clinic_id = ? AND doctor_id = ?", clinicID, doctorID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *ClinicRepositoryImpl) GetClinicsByDoctorId(ctx context.Context, doctorID uint) ([]model.Clinic, error) {
+	var clinics []model.Clinic
+	if err := r.DB.WithContext(ctx).Joins("JOIN doctor_clinics ON doctor_clinics.clinic_id = clinics.id").Where("doctor_clinics.doctor_id = ?", doctorID).Find(&clinics).Error; err != nil {
+		return nil, err
+	}
+	return clinics, nil
+}
+
+func (r *ClinicRepositoryImpl) AssignAdmin(ctx context.Context, clinicID uint, userID uint) error {
+	return r.DB.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("clinic_id", clinicID).Error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type CoverageRepository interface {
+	CreateCoverage(ctx context.Context, coverage *model.ServiceCoverage) error
+	GetCoveragesByServiceId(ctx context.Context, serviceId uint) ([]model.ServiceCoverage, error)
+	FindCoverage(ctx context.Context, serviceId uint, payerType, insurerName string) (*model.ServiceCoverage, error)
+	DeleteCoverage(ctx context.Context, coverageID uint, userID uint) error
+}
+
+type CoverageRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *CoverageRepositoryImpl) CreateCoverage(ctx context.Context, coverage *model.ServiceCoverage) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(coverage).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *CoverageRepositoryImpl) GetCoveragesByServiceId(ctx context.Context, serviceId uint) ([]model.ServiceCoverage, error) {
+	var coverages []model.ServiceCoverage
+	if err := r.DB.WithContext(ctx).Where("service_id = ?", serviceId).Find(&coverages).Error; err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+// FindCoverage returns the rule for the given insurer, falling back to the
+// rule that applies to every insurer of the payer type.
+func (r *CoverageRepositoryImpl) FindCoverage(ctx context.Context, serviceId uint, payerType, insurerName string) (*model.ServiceCoverage, error) {
+	var coverage model.ServiceCoverage
+	if err := r.DB.WithContext(ctx).Where("service_id = ? AND payer_type = ? AND (insurer_name = ? OR insurer_name = '')", serviceId, payerType, insurerName).
+		Order("insurer_name desc").First(&coverage).Error; err != nil {
+		return nil, err
+	}
+	return &coverage, nil
+}
+
+func (r *CoverageRepositoryImpl) DeleteCoverage(ctx context.Context, coverageID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	var coverage model.ServiceCoverage
+	if err := tx.First(&coverage, coverageID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	coverage.UpdatedBy = userID
+
+	if err := tx.Save(&coverage).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&model.ServiceCoverage{}, coverageID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
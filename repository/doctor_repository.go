@@ -2,25 +2,33 @@ package repository
 
 import (
 	"booking-klinik/model"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type DoctorRepository interface {
-	CreateDoctor(doctor *model.Doctor) error
-	GetAllDoctors(limit, offset int) ([]model.Doctor, int64, error)
-	GetDoctorById(id uint) (*model.Doctor, error)
-	GetDoctorIDbyUserID(userID uint) (uint, error)
-	UpdateDoctor(doctorID uint, doctor model.Doctor) (*model.Doctor, error)
-	DeleteDoctor(doctorID uint, userID uint) error
+	CreateDoctor(ctx context.Context, doctor *model.Doctor) error
+	GetAllDoctors(ctx context.Context, clinicId uint, limit, offset int) ([]model.Doctor, int64, error)
+	GetDoctorById(ctx context.Context, id uint) (*model.Doctor, error)
+	GetDoctorIDbyUserID(ctx context.Context, userID uint) (uint, error)
+	UpdateDoctor(ctx context.Context, doctorID uint, doctor model.Doctor) (*model.Doctor, error)
+	DeleteDoctor(ctx context.Context, doctorID uint, userID uint) error
+	AddService(ctx context.Context, doctorID uint, serviceID uint) error
+	RemoveService(ctx context.Context, doctorID uint, serviceID uint) error
+	GetServicesByDoctorId(ctx context.Context, doctorID uint) ([]model.Service, error)
+	HasService(ctx context.Context, doctorID uint, serviceID uint) (bool, error)
+	GetDoctorDirectory(ctx context.Context, specialization string, serviceID uint, clinicID uint, limit, offset int) ([]model.Doctor, int64, error)
+	GetDoctorsWithLicenseExpiringBefore(ctx context.Context, date time.Time) ([]model.Doctor, error)
 }
 
 type DoctorRepositoryImpl struct {
 	DB *gorm.DB
 }
 
-func (r *DoctorRepositoryImpl) CreateDoctor(doctor *model.Doctor) error {
-	tx := r.DB.Begin()
+func (r *DoctorRepositoryImpl) CreateDoctor(ctx context.Context, doctor *model.Doctor) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 	if err := tx.Create(doctor).Error; err != nil {
 		tx.Rollback()
@@ -32,16 +40,21 @@ func (r *DoctorRepositoryImpl) CreateDoctor(doctor *model.Doctor) error {
 
 // GetAllDoctors gets all doctors with the given limit and offset.
 //
-// This function will return a list of doctors with the given limit and offset.
+// This function will return a list of doctors with the given limit and offset,
+// limited to the doctors practising at clinicId unless it is 0.
 // If the query is not successful, it will return an error.
-func (r *DoctorRepositoryImpl) GetAllDoctors(limit, offset int) ([]model.Doctor, int64, error) {
+func (r *DoctorRepositoryImpl) GetAllDoctors(ctx context.Context, clinicId uint, limit, offset int) ([]model.Doctor, int64, error) {
 	var totalRows int64
 	var doctors []model.Doctor
-	if err := r.DB.Model(&model.Doctor{}).Count(&totalRows).Error; err != nil {
+	query := r.DB.WithContext(ctx).Model(&model.Doctor{})
+	if clinicId != 0 {
+		query = query.Joins("JOIN doctor_clinics ON doctor_clinics.doctor_id = doctors.id").Where("doctor_clinics.clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.DB.Limit(limit).Offset(offset).Preload("User").Find(&doctors).Error; err != nil {
+	if err := query.Limit(limit).Offset(offset).Preload("User").Find(&doctors).Error; err != nil {
 		return nil, 0, err
 	}
 	return doctors, totalRows, nil
@@ -51,50 +64,60 @@ func (r *DoctorRepositoryImpl) GetAllDoctors(limit, offset int) ([]model.Doctor,
 //
 // This function will return a doctor that matches the given ID.
 // If the doctor is not found, it will return an error.
-func (r *DoctorRepositoryImpl) GetDoctorById(id uint) (*model.Doctor, error) {
+func (r *DoctorRepositoryImpl) GetDoctorById(ctx context.Context, id uint) (*model.Doctor, error) {
 	var doctor model.Doctor
-	if err := r.DB.Preload("User").First(&doctor, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("User").First(&doctor, id).Error; err != nil {
 		return nil, err
 	}
 	return &doctor, nil
 }
 
-// UpdateDoctor updates the specialization of a doctor with the given doctorID.
+// UpdateDoctor updates the profile and licenses of a doctor with the given doctorID.
 //
 // This function retrieves the existing doctor from the database by doctorID,
-// updates the specialization, and saves the changes. If any errors occur during
+// updates the profile and license details, and saves the changes. If any errors occur during
 // the process, it rolls back the transaction and returns an error. Upon
 // successful update, it commits the transaction and returns the updated doctor.
 //
 // Parameters:
 //  - doctorID: the ID of the doctor to be updated.
-//  - doctor: a model.Doctor object containing the new specialization details.
+//  - doctor: a model.Doctor object containing the new profile details.
 //
 // Returns:
 //  - A pointer to the updated model.Doctor object.
 //  - An error if the update process fails.
 
-func (r *DoctorRepositoryImpl) UpdateDoctor(doctorID uint, doctor model.Doctor) (*model.Doctor, error) {
-	tx := r.DB.Begin()
+func (r *DoctorRepositoryImpl) UpdateDoctor(ctx context.Context, doctorID uint, doctor model.Doctor) (*model.Doctor, error) {
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 
 	var existingDoctor model.Doctor
-	if err := r.DB.First(&existingDoctor, doctorID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&existingDoctor, doctorID).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
 	existingDoctor.Specialization = doctor.Specialization
-
-	if err := r.DB.Save(&existingDoctor).Error; err != nil {
+	existingDoctor.Bio = doctor.Bio
+	existingDoctor.PhotoURL = doctor.PhotoURL
+	existingDoctor.Languages = doctor.Languages
+	existingDoctor.Education = doctor.Education
+	existingDoctor.PracticeSince = doctor.PracticeSince
+	existingDoctor.STRNumber = doctor.STRNumber
+	existingDoctor.STRExpiresAt = doctor.STRExpiresAt
+	existingDoctor.SIPNumber = doctor.SIPNumber
+	existingDoctor.SIPExpiresAt = doctor.SIPExpiresAt
+	existingDoctor.UpdatedBy = doctor.UpdatedBy
+
+	if err := r.DB.WithContext(ctx).Save(&existingDoctor).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 	return &existingDoctor, nil
 }
 
-func (r *DoctorRepositoryImpl) DeleteDoctor(doctorID uint, userID uint) error {
-	tx := r.DB.Begin()
+func (r *DoctorRepositoryImpl) DeleteDoctor(ctx context.Context, doctorID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 
 	var doctor model.Doctor
@@ -118,10 +141,73 @@ func (r *DoctorRepositoryImpl) DeleteDoctor(doctorID uint, userID uint) error {
 	return nil
 }
 
-func (r *DoctorRepositoryImpl) GetDoctorIDbyUserID(userID uint) (uint, error) {
+func (r *DoctorRepositoryImpl) GetDoctorIDbyUserID(ctx context.Context, userID uint) (uint, error) {
 	var doctor model.Doctor
-	if err := r.DB.Preload("User").First(&doctor, "user_id = ?", userID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("User").First(&doctor, "user_id = ?", userID).Error; err != nil {
 		return 0, err
 	}
 	return doctor.ID, nil
 }
+
+func (r *DoctorRepositoryImpl) AddService(ctx context.Context, doctorID uint, serviceID uint) error {
+	doctor := model.Doctor{Model: gorm.Model{ID: doctorID}}
+	return r.DB.WithContext(ctx).Model(&doctor).Association("Services").Append(&model.Service{Model: gorm.Model{ID: serviceID}})
+}
+
+func (r *DoctorRepositoryImpl) RemoveService(ctx context.Context, doctorID uint, serviceID uint) error {
+	doctor := model.Doctor{Model: gorm.Model{ID: doctorID}}
+	return r.DB.WithContext(ctx).Model(&doctor).Association("Services").Delete(&model.Service{Model: gorm.Model{ID: serviceID}})
+}
+
+func (r *DoctorRepositoryImpl) GetServicesByDoctorId(ctx context.Context, doctorID uint) ([]model.Service, error) {
+	var services []model.Service
+	doctor := model.Doctor{Model: gorm.Model{ID: doctorID}}
+	if err := r.DB.WithContext(ctx).Model(&doctor).Where("is_active = ?", true).Association("Services").Find(&services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (r *DoctorRepositoryImpl) HasService(ctx context.Context, doctorID uint, serviceID uint) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Table("doctor_services").Where("doctor_id = ? AND service_id = ?", doctorID, serviceID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetDoctorDirectory lists doctors for the public directory, optionally
+// filtered by specialization, by a service they provide and by clinic.
+func (r *DoctorRepositoryImpl) GetDoctorDirectory(ctx context.Context, specialization string, serviceID uint, clinicID uint, limit, offset int) ([]model.Doctor, int64, error) {
+	var totalRows int64
+	var doctors []model.Doctor
+
+	query := r.DB.WithContext(ctx).Model(&model.Doctor{})
+	if specialization != "" {
+		query = query.Where("specialization = ?", specialization)
+	}
+	if serviceID != 0 {
+		query = query.Joins("JOIN doctor_services ON doctor_services.doctor_id = doctors.id").Where("doctor_services.service_id = ?", serviceID)
+	}
+	if clinicID != 0 {
+		query = query.Joins("JOIN doctor_clinics ON doctor_clinics.doctor_id = doctors.id").Where("doctor_clinics.clinic_id = ?", clinicID)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User").Order("doctors.id asc").Limit(limit).Offset(offset).Find(&doctors).Error; err != nil {
+		return nil, 0, err
+	}
+	return doctors, totalRows, nil
+}
+
+// GetDoctorsWithLicenseExpiringBefore returns doctors whose STR or SIP
+// expires before the given date, including licenses that already expired.
+func (r *DoctorRepositoryImpl) GetDoctorsWithLicenseExpiringBefore(ctx context.Context, date time.Time) ([]model.Doctor, error) {
+	var doctors []model.Doctor
+	if err := r.DB.WithContext(ctx).Preload("User").Where("str_expires_at < ? OR sip_expires_at < ?", date, date).Find(&doctors).Error; err != nil {
+		return nil, err
+	}
+	return doctors, nil
+}
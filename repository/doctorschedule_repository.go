@@ -2,25 +2,34 @@ package repository
 
 import (
 	"booking-klinik/model"
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type DoctorScheduleRepository interface {
-	CreateDoctorSchedule(doctorSchedule *model.DoctorSchedule) error
-	GetDoctorSchedulesByDoctorId(doctorId uint) ([]model.DoctorSchedule, error)
-	GetDoctorSchedulesById(scheduleId uint) (*model.DoctorSchedule, error)
-	GetAllDoctorSchedules(limit, offset int) ([]model.DoctorSchedule, error)
-	UpdateDoctorSchedule(doctorSchedule *model.DoctorSchedule) error
-	DeleteDoctorSchedule(scheduleId uint, userID uint) error
+	CreateDoctorSchedule(ctx context.Context, doctorSchedule *model.DoctorSchedule) error
+	GetDoctorSchedulesByDoctorId(ctx context.Context, doctorId uint) ([]model.DoctorSchedule, error)
+	GetDoctorSchedulesById(ctx context.Context, scheduleId uint) (*model.DoctorSchedule, error)
+	GetAllDoctorSchedules(ctx context.Context, clinicId uint, limit, offset int) ([]model.DoctorSchedule, error)
+	UpdateDoctorSchedule(ctx context.Context, doctorSchedule *model.DoctorSchedule) error
+	DeleteDoctorSchedule(ctx context.Context, scheduleId uint, userID uint) error
+	GetNextScheduleDatesByDoctor(ctx context.Context, doctorIds []uint, serviceId uint, clinicId uint, from time.Time) (map[uint]time.Time, error)
+	GetNextScheduleDatesByService(ctx context.Context, serviceIds []uint, clinicId uint, from time.Time) (map[uint]time.Time, error)
+	GetBookableSchedules(ctx context.Context, serviceId uint, specialization string, clinicId uint, from, to time.Time) ([]model.DoctorSchedule, error)
+	GetSchedulesByRoomAndDate(ctx context.Context, roomId uint, date time.Time) ([]model.DoctorSchedule, error)
+	GetScheduleCovering(ctx context.Context, doctorId uint, serviceId uint, startAt, endAt time.Time) (*model.DoctorSchedule, error)
 }
 
 type DoctorScheduleRepositoryImpl struct {
 	DB *gorm.DB
 }
 
-func (r *DoctorScheduleRepositoryImpl) CreateDoctorSchedule(doctorSchedule *model.DoctorSchedule) error {
-	tx := r.DB.Begin()
+func (r *DoctorScheduleRepositoryImpl) CreateDoctorSchedule(ctx context.Context, doctorSchedule *model.DoctorSchedule) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	if err := tx.Create(doctorSchedule).Error; err != nil {
 		tx.Rollback()
 		return err
@@ -34,34 +43,35 @@ func (r *DoctorScheduleRepositoryImpl) CreateDoctorSchedule(doctorSchedule *mode
 	return nil
 }
 
-func (r *DoctorScheduleRepositoryImpl) GetDoctorSchedulesByDoctorId(doctorId uint) ([]model.DoctorSchedule, error) {
+func (r *DoctorScheduleRepositoryImpl) GetDoctorSchedulesByDoctorId(ctx context.Context, doctorId uint) ([]model.DoctorSchedule, error) {
 	var doctorSchedules []model.DoctorSchedule
-	if err := r.DB.Where("doctor_id = ?", doctorId).Find(&doctorSchedules).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("doctor_id = ?", doctorId).Find(&doctorSchedules).Error; err != nil {
 		return nil, err
 	}
 	return doctorSchedules, nil
 }
 
-func (r *DoctorScheduleRepositoryImpl) GetDoctorSchedulesById(scheduleId uint) (*model.DoctorSchedule, error) {
+func (r *DoctorScheduleRepositoryImpl) GetDoctorSchedulesById(ctx context.Context, scheduleId uint) (*model.DoctorSchedule, error) {
 	var doctorSchedule model.DoctorSchedule
-	if err := r.DB.First(&doctorSchedule, scheduleId).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&doctorSchedule, scheduleId).Error; err != nil {
 		return nil, err
 	}
 	return &doctorSchedule, nil
 }
 
-func (r *DoctorScheduleRepositoryImpl) UpdateDoctorSchedule(doctorSchedule *model.DoctorSchedule) error {
+func (r *DoctorScheduleRepositoryImpl) UpdateDoctorSchedule(ctx context.Context, doctorSchedule *model.DoctorSchedule) error {
 	var existingDoctorSchedule model.DoctorSchedule
-	if err := r.DB.First(&existingDoctorSchedule, doctorSchedule.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&existingDoctorSchedule, doctorSchedule.ID).Error; err != nil {
 		return err
 	}
 
 	existingDoctorSchedule.Date = doctorSchedule.Date
 	existingDoctorSchedule.StartTime = doctorSchedule.StartTime
 	existingDoctorSchedule.EndTime = doctorSchedule.EndTime
+	existingDoctorSchedule.RoomId = doctorSchedule.RoomId
 	existingDoctorSchedule.UpdatedBy = doctorSchedule.Doctor.User.ID
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 
 	if err := tx.Save(&existingDoctorSchedule).Error; err != nil {
 		tx.Rollback()
@@ -71,9 +81,9 @@ func (r *DoctorScheduleRepositoryImpl) UpdateDoctorSchedule(doctorSchedule *mode
 	return nil
 }
 
-func (r *DoctorScheduleRepositoryImpl) DeleteDoctorSchedule(id uint, userID uint) error {
+func (r *DoctorScheduleRepositoryImpl) DeleteDoctorSchedule(ctx context.Context, id uint, userID uint) error {
 	var doctorSchedule model.DoctorSchedule
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 
 	if err := tx.First(&doctorSchedule, id).Error; err != nil {
 		tx.Rollback()
@@ -96,10 +106,145 @@ func (r *DoctorScheduleRepositoryImpl) DeleteDoctorSchedule(id uint, userID uint
 	return nil
 }
 
-func (r *DoctorScheduleRepositoryImpl) GetAllDoctorSchedules(limit, offset int) ([]model.DoctorSchedule, error) {
+func (r *DoctorScheduleRepositoryImpl) GetAllDoctorSchedules(ctx context.Context, clinicId uint, limit, offset int) ([]model.DoctorSchedule, error) {
 	var doctorSchedules []model.DoctorSchedule
-	if err := r.DB.Limit(limit).Offset(offset).Preload("Doctor").Find(&doctorSchedules).Error; err != nil {
+	query := r.DB
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Limit(limit).Offset(offset).Preload("Doctor").Find(&doctorSchedules).Error; err != nil {
+		return nil, err
+	}
+	return doctorSchedules, nil
+}
+
+type nextScheduleDate struct {
+	Id       uint
+	NextDate aggregateTime
+}
+
+// aggregateTime scans MIN or MAX of a time column, which SQLite returns as
+// text rather than as a time.
+type aggregateTime struct {
+	time.Time
+}
+
+var aggregateTimeFormats = []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+func (t *aggregateTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		return t.Scan(string(v))
+	case string:
+		for _, format := range aggregateTimeFormats {
+			if parsed, err := time.Parse(format, v); err == nil {
+				t.Time = parsed.UTC()
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid time %q", v)
+	case nil:
+		t.Time = time.Time{}
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into a time", value)
+}
+
+func (t aggregateTime) Value() (driver.Value, error) {
+	return t.Time, nil
+}
+
+// GetNextScheduleDatesByDoctor returns, per doctor, the first schedule date on
+// or after from. A non-zero serviceId or clinicId only considers schedules for
+// that service or at that clinic.
+func (r *DoctorScheduleRepositoryImpl) GetNextScheduleDatesByDoctor(ctx context.Context, doctorIds []uint, serviceId uint, clinicId uint, from time.Time) (map[uint]time.Time, error) {
+	var rows []nextScheduleDate
+	query := r.DB.WithContext(ctx).Model(&model.DoctorSchedule{}).
+		Select("doctor_id AS id, MIN(date) AS next_date").
+		Where("doctor_id IN ? AND date >= ?", doctorIds, from)
+	if serviceId != 0 {
+		query = query.Where("service_id = ?", serviceId)
+	}
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Group("doctor_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	nextDates := make(map[uint]time.Time, len(rows))
+	for _, row := range rows {
+		nextDates[row.Id] = row.NextDate.Time
+	}
+	return nextDates, nil
+}
+
+// GetNextScheduleDatesByService returns, per service, the first schedule date
+// on or after from of any doctor still providing it, optionally at one clinic.
+func (r *DoctorScheduleRepositoryImpl) GetNextScheduleDatesByService(ctx context.Context, serviceIds []uint, clinicId uint, from time.Time) (map[uint]time.Time, error) {
+	var rows []nextScheduleDate
+	query := r.DB.WithContext(ctx).Model(&model.DoctorSchedule{})
+	if clinicId != 0 {
+		query = query.Where("doctor_schedules.clinic_id = ?", clinicId)
+	}
+	if err := query.
+		Select("doctor_schedules.service_id AS id, MIN(doctor_schedules.date) AS next_date").
+		Joins("JOIN doctors ON doctors.id = doctor_schedules.doctor_id AND doctors.deleted_at IS NULL").
+		Joins("JOIN doctor_services ON doctor_services.doctor_id = doctor_schedules.doctor_id AND doctor_services.service_id = doctor_schedules.service_id").
+		Where("doctor_schedules.service_id IN ? AND doctor_schedules.date >= ?", serviceIds, from).
+		Group("doctor_schedules.service_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	nextDates := make(map[uint]time.Time, len(rows))
+	for _, row := range rows {
+		nextDates[row.Id] = row.NextDate.Time
+	}
+	return nextDates, nil
+}
+
+// GetBookableSchedules returns the schedules between from and to (inclusive
+// dates) of active services that their doctors still provide, optionally
+// limited to one service, one specialization and one clinic.
+func (r *DoctorScheduleRepositoryImpl) GetBookableSchedules(ctx context.Context, serviceId uint, specialization string, clinicId uint, from, to time.Time) ([]model.DoctorSchedule, error) {
+	var doctorSchedules []model.DoctorSchedule
+	query := r.DB.WithContext(ctx).
+		Joins("JOIN doctors ON doctors.id = doctor_schedules.doctor_id AND doctors.deleted_at IS NULL").
+		Joins("JOIN doctor_services ON doctor_services.doctor_id = doctor_schedules.doctor_id AND doctor_services.service_id = doctor_schedules.service_id").
+		Joins("JOIN services ON services.id = doctor_schedules.service_id AND services.deleted_at IS NULL AND services.is_active = ? AND services.duration_minutes > 0", true).
+		Where("doctor_schedules.date BETWEEN ? AND ?", from, to)
+	if serviceId != 0 {
+		query = query.Where("doctor_schedules.service_id = ?", serviceId)
+	}
+	if specialization != "" {
+		query = query.Where("doctors.specialization = ?", specialization)
+	}
+	if clinicId != 0 {
+		query = query.Where("doctor_schedules.clinic_id = ?", clinicId)
+	}
+	if err := query.Preload("Doctor.User").Preload("Service").Order("doctor_schedules.start_time asc").Find(&doctorSchedules).Error; err != nil {
 		return nil, err
 	}
 	return doctorSchedules, nil
 }
+
+func (r *DoctorScheduleRepositoryImpl) GetSchedulesByRoomAndDate(ctx context.Context, roomId uint, date time.Time) ([]model.DoctorSchedule, error) {
+	var doctorSchedules []model.DoctorSchedule
+	if err := r.DB.WithContext(ctx).Where("room_id = ? AND date = ?", roomId, date).Find(&doctorSchedules).Error; err != nil {
+		return nil, err
+	}
+	return doctorSchedules, nil
+}
+
+// GetScheduleCovering returns the doctor's schedule for the service that
+// contains the whole of [startAt, endAt].
+func (r *DoctorScheduleRepositoryImpl) GetScheduleCovering(ctx context.Context, doctorId uint, serviceId uint, startAt, endAt time.Time) (*model.DoctorSchedule, error) {
+	var doctorSchedule model.DoctorSchedule
+	if err := r.DB.WithContext(ctx).Where("doctor_id = ? AND service_id = ? AND start_time <= ? AND end_time >= ?", doctorId, serviceId, startAt, endAt).First(&doctorSchedule).Error; err != nil {
+		return nil, err
+	}
+	return &doctorSchedule, nil
+}
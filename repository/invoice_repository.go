@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InvoiceRepository interface {
+	CreateInvoice(ctx context.Context, invoice *model.Invoice) error
+	GetInvoiceById(ctx context.Context, id uint) (*model.Invoice, error)
+	GetInvoiceByBookingId(ctx context.Context, bookingId uint) (*model.Invoice, error)
+	GetInvoicesByUserId(ctx context.Context, userId uint, limit, offset int) ([]model.Invoice, int64, error)
+	GetInvoicesByStatus(ctx context.Context, status string, clinicId uint, limit, offset int) ([]model.Invoice, int64, error)
+	AddInvoiceItem(ctx context.Context, item *model.InvoiceItem) error
+	UpdateInvoice(ctx context.Context, invoice *model.Invoice) error
+}
+
+type InvoiceRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// CreateInvoice assigns the next invoice number for the month the invoice is
+// issued in and stores the invoice with its items in one transaction.
+func (r *InvoiceRepositoryImpl) CreateInvoice(ctx context.Context, invoice *model.Invoice) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		period := invoice.IssuedAt.Format("200601")
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.InvoiceSequence{Period: period}).Error; err != nil {
+			return err
+		}
+
+		var sequence model.InvoiceSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sequence, "period = ?", period).Error; err != nil {
+			return err
+		}
+
+		sequence.LastNumber++
+		if err := tx.Save(&sequence).Error; err != nil {
+			return err
+		}
+
+		invoice.InvoiceNumber = fmt.Sprintf("INV-%s-%05d", period, sequence.LastNumber)
+		return tx.Create(invoice).Error
+	})
+}
+
+func (r *InvoiceRepositoryImpl) GetInvoiceById(ctx context.Context, id uint) (*model.Invoice, error) {
+	var invoice model.Invoice
+	if err := r.DB.WithContext(ctx).Preload("Items").First(&invoice, id).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *InvoiceRepositoryImpl) GetInvoiceByBookingId(ctx context.Context, bookingId uint) (*model.Invoice, error) {
+	var invoice model.Invoice
+	if err := r.DB.WithContext(ctx).Preload("Items").Where("booking_id = ?", bookingId).First(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *InvoiceRepositoryImpl) GetInvoicesByUserId(ctx context.Context, userId uint, limit, offset int) ([]model.Invoice, int64, error) {
+	var invoices []model.Invoice
+	var totalRows int64
+	if err := r.DB.WithContext(ctx).Model(&model.Invoice{}).Where("user_id = ?", userId).Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.DB.WithContext(ctx).Preload("Items").Where("user_id = ?", userId).Order("issued_at desc").Limit(limit).Offset(offset).Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+	return invoices, totalRows, nil
+}
+
+func (r *InvoiceRepositoryImpl) GetInvoicesByStatus(ctx context.Context, status string, clinicId uint, limit, offset int) ([]model.Invoice, int64, error) {
+	var invoices []model.Invoice
+	var totalRows int64
+	query := r.DB.WithContext(ctx).Model(&model.Invoice{}).Where("invoices.status = ?", status)
+	if clinicId != 0 {
+		query = query.Joins("JOIN bookings ON bookings.id = invoices.booking_id").Where("bookings.clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Items").Order("invoices.issued_at asc").Limit(limit).Offset(offset).Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+	return invoices, totalRows, nil
+}
+
+func (r *InvoiceRepositoryImpl) AddInvoiceItem(ctx context.Context, item *model.InvoiceItem) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(item).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *InvoiceRepositoryImpl) UpdateInvoice(ctx context.Context, invoice *model.Invoice) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Omit("Items").Save(invoice).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
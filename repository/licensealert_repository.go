@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type LicenseAlertRepository interface {
+	CreateLicenseAlert(ctx context.Context, alert *model.LicenseAlert) (bool, error)
+	GetOpenLicenseAlerts(ctx context.Context, limit, offset int) ([]model.LicenseAlert, int64, error)
+}
+
+type LicenseAlertRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+// CreateLicenseAlert stores the alert unless the same alert was already raised
+// for that license and expiry date. It reports whether a new alert was created.
+func (r *LicenseAlertRepositoryImpl) CreateLicenseAlert(ctx context.Context, alert *model.LicenseAlert) (bool, error) {
+	result := r.DB.WithContext(ctx).Where(model.LicenseAlert{
+		DoctorId:  alert.DoctorId,
+		License:   alert.License,
+		Kind:      alert.Kind,
+		ExpiresAt: alert.ExpiresAt,
+	}).FirstOrCreate(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetOpenLicenseAlerts returns alerts whose license has not been renewed since,
+// i.e. the doctor's current expiry date still matches the alerted one.
+func (r *LicenseAlertRepositoryImpl) GetOpenLicenseAlerts(ctx context.Context, limit, offset int) ([]model.LicenseAlert, int64, error) {
+	var alerts []model.LicenseAlert
+	var totalRows int64
+
+	query := r.DB.WithContext(ctx).Model(&model.LicenseAlert{}).
+		Joins("JOIN doctors ON doctors.id = license_alerts.doctor_id AND doctors.deleted_at IS NULL").
+		Where("(license_alerts.license = 'STR' AND doctors.str_expires_at = license_alerts.expires_at) OR (license_alerts.license = 'SIP' AND doctors.sip_expires_at = license_alerts.expires_at)")
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Doctor.User").Order("license_alerts.expires_at asc").Limit(limit).Offset(offset).Find(&alerts).Error; err != nil {
+		return nil, 0, err
+	}
+	return alerts, totalRows, nil
+}
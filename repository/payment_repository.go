@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *model.Payment) error
+	GetPaymentById(ctx context.Context, id uint) (*model.Payment, error)
+	GetPaymentByProviderRef(ctx context.Context, provider, providerRef string) (*model.Payment, error)
+	GetPaymentsByBookingId(ctx context.Context, bookingId uint) ([]model.Payment, error)
+	UpdatePayment(ctx context.Context, payment *model.Payment) error
+	UpdatePaymentStatus(ctx context.Context, payment *model.Payment, fromStatus string) (bool, error)
+}
+
+type PaymentRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *PaymentRepositoryImpl) CreatePayment(ctx context.Context, payment *model.Payment) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(payment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *PaymentRepositoryImpl) GetPaymentById(ctx context.Context, id uint) (*model.Payment, error) {
+	var payment model.Payment
+	if err := r.DB.WithContext(ctx).First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepositoryImpl) GetPaymentByProviderRef(ctx context.Context, provider, providerRef string) (*model.Payment, error) {
+	var payment model.Payment
+	if err := r.DB.WithContext(ctx).Where("provider = ? AND provider_ref = ?", provider, providerRef).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepositoryImpl) GetPaymentsByBookingId(ctx context.Context, bookingId uint) ([]model.Payment, error) {
+	var payments []model.Payment
+	if err := r.DB.WithContext(ctx).Where("booking_id = ?", bookingId).Order("created_at asc").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *PaymentRepositoryImpl) UpdatePayment(ctx context.Context, payment *model.Payment) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Save(payment).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// UpdatePaymentStatus moves the payment to its Status and PaidAt if it is
+// still in fromStatus. The status is checked in the update itself, so of two
+// callbacks settling the same payment only one succeeds. It reports whether
+// the payment was updated.
+func (r *PaymentRepositoryImpl) UpdatePaymentStatus(ctx context.Context, payment *model.Payment, fromStatus string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&model.Payment{}).Where("id = ? AND status = ?", payment.ID, fromStatus).Updates(map[string]interface{}{
+		"status":     payment.Status,
+		"paid_at":    payment.PaidAt,
+		"updated_by": payment.UpdatedBy,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPromoUsageLimit = errors.New("promo code usage limit reached")
+
+type PromoRepository interface {
+	CreatePromo(ctx context.Context, promo *model.Promo) error
+	GetAllPromos(ctx context.Context, limit, offset int) ([]model.Promo, int64, error)
+	GetPromoById(ctx context.Context, id uint) (*model.Promo, error)
+	GetPromoByCode(ctx context.Context, code string) (*model.Promo, error)
+	UpdatePromo(ctx context.Context, promo *model.Promo) error
+	DeletePromo(ctx context.Context, promoID uint, userID uint) error
+	CountUsages(ctx context.Context, promoId uint) (int64, error)
+	CountUsagesByUserId(ctx context.Context, promoId uint, userId uint) (int64, error)
+	CountVisitsByUserId(ctx context.Context, userId uint) (int64, error)
+}
+
+type PromoRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *PromoRepositoryImpl) CreatePromo(ctx context.Context, promo *model.Promo) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(promo).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *PromoRepositoryImpl) GetAllPromos(ctx context.Context, limit, offset int) ([]model.Promo, int64, error) {
+	var promos []model.Promo
+	var totalRows int64
+	if err := r.DB.WithContext(ctx).Model(&model.Promo{}).Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.DB.WithContext(ctx).Order("valid_from desc").Limit(limit).Offset(offset).Find(&promos).Error; err != nil {
+		return nil, 0, err
+	}
+	return promos, totalRows, nil
+}
+
+func (r *PromoRepositoryImpl) GetPromoById(ctx context.Context, id uint) (*model.Promo, error) {
+	var promo model.Promo
+	if err := r.DB.WithContext(ctx).First(&promo, id).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *PromoRepositoryImpl) GetPromoByCode(ctx context.Context, code string) (*model.Promo, error) {
+	var promo model.Promo
+	if err := r.DB.WithContext(ctx).Where("code = ?", code).First(&promo).Error; err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (r *PromoRepositoryImpl) UpdatePromo(ctx context.Context, promo *model.Promo) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Save(promo).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *PromoRepositoryImpl) DeletePromo(ctx context.Context, promoID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	var promo model.Promo
+	if err := tx.First(&promo, promoID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	promo.UpdatedBy = userID
+
+	if err := tx.Save(&promo).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&model.Promo{}, promoID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// activeUsages limits promo usages to bookings that were not cancelled, so a
+// cancelled booking gives its usage back.
+func activeUsages(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.PromoUsage{}).
+		Joins("JOIN bookings ON bookings.id = promo_usages.booking_id AND bookings.deleted_at IS NULL").
+		Where("bookings.status != ?", "cancelled")
+}
+
+func (r *PromoRepositoryImpl) CountUsages(ctx context.Context, promoId uint) (int64, error) {
+	var count int64
+	if err := activeUsages(r.DB.WithContext(ctx)).Where("promo_usages.promo_id = ?", promoId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PromoRepositoryImpl) CountUsagesByUserId(ctx context.Context, promoId uint, userId uint) (int64, error) {
+	var count int64
+	if err := activeUsages(r.DB.WithContext(ctx)).Where("promo_usages.promo_id = ? AND promo_usages.user_id = ?", promoId, userId).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PromoRepositoryImpl) CountVisitsByUserId(ctx context.Context, userId uint) (int64, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&model.Booking{}).Where("user_id = ? AND status != ?", userId, "cancelled").Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// createPromoUsage records a redemption while holding a lock on the promo row
+// so concurrent bookings cannot exceed the usage limits. A limit of 0 means
+// unlimited. tx must be a transaction.
+func createPromoUsage(tx *gorm.DB, usage *model.PromoUsage, maxUses, maxUsesPerPatient int) error {
+	var promo model.Promo
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&promo, usage.PromoId).Error; err != nil {
+		return err
+	}
+
+	if maxUses > 0 {
+		var count int64
+		if err := activeUsages(tx).Where("promo_usages.promo_id = ?", usage.PromoId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxUses) {
+			return ErrPromoUsageLimit
+		}
+	}
+
+	if maxUsesPerPatient > 0 {
+		var count int64
+		if err := activeUsages(tx).Where("promo_usages.promo_id = ? AND promo_usages.user_id = ?", usage.PromoId, usage.UserId).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxUsesPerPatient) {
+			return ErrPromoUsageLimit
+		}
+	}
+
+	return tx.Create(usage).Error
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPromoUsagesOfCancelledBookingsAreGivenBack(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &PromoRepositoryImpl{DB: f.DB}
+	var promo model.Promo
+	testutil.MustCreate(t, f.DB, &promo, model.Promo{Code: "HEMAT", DiscountType: "percent", DiscountValue: 10, ValidFrom: time.Now(), ValidUntil: time.Now().AddDate(1, 0, 0), IsActive: true})
+
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	for i, status := range []string{"confirmed", "cancelled"} {
+		booking := f.AddBooking(t, f.Doctor, f.Service, start.Add(time.Duration(i)*time.Hour), status)
+		var usage model.PromoUsage
+		testutil.MustCreate(t, f.DB, &usage, model.PromoUsage{PromoId: promo.ID, BookingId: booking.ID, UserId: f.Patient.ID, DiscountAmount: 10000})
+	}
+
+	used, err := repo.CountUsages(context.Background(), promo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used != 1 {
+		t.Errorf("CountUsages() = %d, want 1", used)
+	}
+	used, err = repo.CountUsagesByUserId(context.Background(), promo.ID, f.Patient.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used != 1 {
+		t.Errorf("CountUsagesByUserId() = %d, want 1", used)
+	}
+	visits, err := repo.CountVisitsByUserId(context.Background(), f.Patient.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visits != 1 {
+		t.Errorf("CountVisitsByUserId() = %d, want 1", visits)
+	}
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var ErrBookingCancelled = errors.New("booking already cancelled")
+
+type RefundRepository interface {
+	CreateRefund(ctx context.Context, refund *model.Refund) error
+	GetRefundById(ctx context.Context, id uint) (*model.Refund, error)
+	GetRefundsByStatus(ctx context.Context, status string, clinicId uint, limit, offset int) ([]model.Refund, int64, error)
+	GetRefundsByPaymentId(ctx context.Context, paymentId uint) ([]model.Refund, error)
+	GetRefundsByBookingId(ctx context.Context, bookingId uint) ([]model.Refund, error)
+	CancelBookingWithRefunds(ctx context.Context, booking *model.Booking, percent int, reason string) ([]model.Refund, error)
+	UpdateRefund(ctx context.Context, refund *model.Refund) error
+}
+
+type RefundRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *RefundRepositoryImpl) CreateRefund(ctx context.Context, refund *model.Refund) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(refund).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *RefundRepositoryImpl) GetRefundById(ctx context.Context, id uint) (*model.Refund, error) {
+	var refund model.Refund
+	if err := r.DB.WithContext(ctx).Preload("Payment").First(&refund, id).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *RefundRepositoryImpl) GetRefundsByStatus(ctx context.Context, status string, clinicId uint, limit, offset int) ([]model.Refund, int64, error) {
+	var refunds []model.Refund
+	var totalRows int64
+	query := r.DB.WithContext(ctx).Model(&model.Refund{}).Where("refunds.status = ?", status)
+	if clinicId != 0 {
+		query = query.Joins("JOIN bookings ON bookings.id = refunds.booking_id").Where("bookings.clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("refunds.created_at asc").Limit(limit).Offset(offset).Find(&refunds).Error; err != nil {
+		return nil, 0, err
+	}
+	return refunds, totalRows, nil
+}
+
+func (r *RefundRepositoryImpl) GetRefundsByPaymentId(ctx context.Context, paymentId uint) ([]model.Refund, error) {
+	var refunds []model.Refund
+	if err := r.DB.WithContext(ctx).Where("payment_id = ?", paymentId).Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *RefundRepositoryImpl) GetRefundsByBookingId(ctx context.Context, bookingId uint) ([]model.Refund, error) {
+	var refunds []model.Refund
+	if err := r.DB.WithContext(ctx).Preload("Payment").Where("booking_id = ?", bookingId).Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// CancelBookingWithRefunds saves the cancelled booking's status and notes
+// and creates a refund of percent of every settled payment, in one
+// transaction. The payments are read after the booking row is updated, so a
+// payment settling at the same time is either refunded here or sees the
+// booking cancelled. It fails with ErrBookingCancelled when the booking was
+// cancelled in the meantime, so refunds are only ever created once.
+func (r *RefundRepositoryImpl) CancelBookingWithRefunds(ctx context.Context, booking *model.Booking, percent int, reason string) ([]model.Refund, error) {
+	var refunds []model.Refund
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Booking{}).Where("id = ? AND status != ?", booking.ID, "cancelled").Updates(map[string]interface{}{
+			"status":     "cancelled",
+			"notes":      booking.Notes,
+			"updated_by": booking.UpdatedBy,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrBookingCancelled
+		}
+
+		if percent == 0 {
+			return nil
+		}
+		var payments []model.Payment
+		if err := tx.Where("booking_id = ? AND status = ?", booking.ID, "paid").Find(&payments).Error; err != nil {
+			return err
+		}
+		for _, paid := range payments {
+			refund := model.Refund{
+				PaymentId: paid.ID,
+				BookingId: booking.ID,
+				Amount:    paid.Amount * percent / 100,
+				Percent:   percent,
+				Reason:    reason,
+				Status:    "pending_approval",
+				CreatedBy: booking.UpdatedBy,
+				UpdatedBy: booking.UpdatedBy,
+				Payment:   paid,
+			}
+			if refund.Amount == 0 {
+				continue
+			}
+			if err := tx.Omit("Payment").Create(&refund).Error; err != nil {
+				return err
+			}
+			refunds = append(refunds, refund)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *RefundRepositoryImpl) UpdateRefund(ctx context.Context, refund *model.Refund) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Omit("Payment").Save(refund).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCancelBookingWithRefundsRefundsPaidPaymentsOnce(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &RefundRepositoryImpl{DB: f.DB}
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC), "confirmed")
+	paid := f.AddPayment(t, booking, 100000, "mock", "paid")
+	f.AddPayment(t, booking, 100000, "mock", "expired")
+
+	booking.UpdatedBy = f.Patient.ID
+	refunds, err := repo.CancelBookingWithRefunds(context.Background(), &booking, 50, "sick")
+	if err != nil {
+		t.Fatalf("CancelBookingWithRefunds() error = %v", err)
+	}
+	if len(refunds) != 1 {
+		t.Fatalf("got %d refunds, want one for the paid payment", len(refunds))
+	}
+	if refund := refunds[0]; refund.PaymentId != paid.ID || refund.Amount != 50000 || refund.Status != "pending_approval" {
+		t.Errorf("refund = %+v, want 50000 of payment %d pending approval", refund, paid.ID)
+	}
+
+	var stored model.Booking
+	f.DB.First(&stored, booking.ID)
+	if stored.Status != "cancelled" {
+		t.Errorf("booking status = %q, want cancelled", stored.Status)
+	}
+
+	// Cancelling again must not refund twice
+	if _, err := repo.CancelBookingWithRefunds(context.Background(), &booking, 50, "sick"); !errors.Is(err, ErrBookingCancelled) {
+		t.Fatalf("second CancelBookingWithRefunds() error = %v, want ErrBookingCancelled", err)
+	}
+	var count int64
+	f.DB.Model(&model.Refund{}).Count(&count)
+	if count != 1 {
+		t.Errorf("%d refunds stored, want 1", count)
+	}
+}
+
+func TestCancelBookingWithRefundsWithoutRefund(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &RefundRepositoryImpl{DB: f.DB}
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC), "confirmed")
+	f.AddPayment(t, booking, 100000, "mock", "paid")
+
+	refunds, err := repo.CancelBookingWithRefunds(context.Background(), &booking, 0, "late")
+	if err != nil {
+		t.Fatalf("CancelBookingWithRefunds() error = %v", err)
+	}
+	if len(refunds) != 0 {
+		t.Errorf("got %d refunds, want none at 0 percent", len(refunds))
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ResourceRepository interface {
+	CreateResource(ctx context.Context, resource *model.Resource) error
+	GetAllResources(ctx context.Context, resourceType string, clinicId uint, limit, offset int) ([]model.Resource, int64, error)
+	GetResourceById(ctx context.Context, id uint) (*model.Resource, error)
+	UpdateResource(ctx context.Context, resource *model.Resource) error
+	DeleteResource(ctx context.Context, id uint, userID uint) error
+	AddServiceResource(ctx context.Context, serviceID uint, resourceID uint) error
+	RemoveServiceResource(ctx context.Context, serviceID uint, resourceID uint) error
+	GetResourcesByServiceId(ctx context.Context, serviceID uint) ([]model.Resource, error)
+	GetBookingsUsingResources(ctx context.Context, resourceIds []uint, from, to time.Time) ([]model.Booking, error)
+}
+
+type ResourceRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *ResourceRepositoryImpl) CreateResource(ctx context.Context, resource *model.Resource) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(resource).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ResourceRepositoryImpl) GetAllResources(ctx context.Context, resourceType string, clinicId uint, limit, offset int) ([]model.Resource, int64, error) {
+	var resources []model.Resource
+	var totalRows int64
+
+	query := r.DB.WithContext(ctx).Model(&model.Resource{})
+	if resourceType != "" {
+		query = query.Where("type = ?", resourceType)
+	}
+	if clinicId != 0 {
+		query = query.Where("clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("name asc").Limit(limit).Offset(offset).Find(&resources).Error; err != nil {
+		return nil, 0, err
+	}
+	return resources, totalRows, nil
+}
+
+func (r *ResourceRepositoryImpl) GetResourceById(ctx context.Context, id uint) (*model.Resource, error) {
+	var resource model.Resource
+	if err := r.DB.WithContext(ctx).First(&resource, id).Error; err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *ResourceRepositoryImpl) UpdateResource(ctx context.Context, resource *model.Resource) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Save(resource).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ResourceRepositoryImpl) DeleteResource(ctx context.Context, id uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Model(&model.Resource{}).Where("id = ?", id).Update("updated_by", userID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&model.Resource{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ResourceRepositoryImpl) AddServiceResource(ctx context.Context, serviceID uint, resourceID uint) error {
+	service := model.Service{Model: gorm.Model{ID: serviceID}}
+	return r.DB.WithContext(ctx).Model(&service).Association("Resources").Append(&model.Resource{Model: gorm.Model{ID: resourceID}})
+}
+
+func (r *ResourceRepositoryImpl) RemoveServiceResource(ctx context.Context, serviceID uint, resourceID uint) error {
+	service := model.Service{Model: gorm.Model{ID: serviceID}}
+	return r.DB.WithContext(ctx).Model(&service).Association("Resources").Delete(&model.Resource{Model: gorm.Model{ID: resourceID}})
+}
+
+func (r *ResourceRepositoryImpl) GetResourcesByServiceId(ctx context.Context, serviceID uint) ([]model.Resource, error) {
+	var resources []model.Resource
+	service := model.Service{Model: gorm.Model{ID: serviceID}}
+	if err := r.DB.WithContext(ctx).Model(&service).Association("Resources").Find(&resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// GetBookingsUsingResources returns the active bookings overlapping [from, to)
+// that hold any of the resources, with the resources they hold.
+func (r *ResourceRepositoryImpl) GetBookingsUsingResources(ctx context.Context, resourceIds []uint, from, to time.Time) ([]model.Booking, error) {
+	var bookings []model.Booking
+	if err := r.DB.WithContext(ctx).
+		Where("id IN (?)", r.DB.WithContext(ctx).Table("booking_resources").Select("booking_id").Where("resource_id IN ?", resourceIds)).
+		Where("start_at < ? AND end_at > ? AND status != ?", to, from, "cancelled").
+		Preload("Resources").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type ReviewRepository interface {
+	CreateReview(ctx context.Context, review *model.Review) error
+	GetReviewById(ctx context.Context, id uint) (*model.Review, error)
+	GetReviewByBookingId(ctx context.Context, bookingId uint) (*model.Review, error)
+	GetReviewsByDoctorId(ctx context.Context, doctorId uint, includeHidden bool, limit, offset int) ([]model.Review, int64, error)
+	UpdateReview(ctx context.Context, review *model.Review) error
+	GetRatingSummaries(ctx context.Context, doctorIds []uint) (map[uint]model.RatingSummary, error)
+}
+
+type ReviewRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *ReviewRepositoryImpl) CreateReview(ctx context.Context, review *model.Review) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(review).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ReviewRepositoryImpl) GetReviewById(ctx context.Context, id uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.DB.WithContext(ctx).Preload("User").First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepositoryImpl) GetReviewByBookingId(ctx context.Context, bookingId uint) (*model.Review, error) {
+	var review model.Review
+	if err := r.DB.WithContext(ctx).Where("booking_id = ?", bookingId).First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepositoryImpl) GetReviewsByDoctorId(ctx context.Context, doctorId uint, includeHidden bool, limit, offset int) ([]model.Review, int64, error) {
+	var reviews []model.Review
+	var totalRows int64
+
+	query := r.DB.WithContext(ctx).Model(&model.Review{}).Where("doctor_id = ?", doctorId)
+	if !includeHidden {
+		query = query.Where("is_hidden = ?", false)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("User").Order("created_at desc").Limit(limit).Offset(offset).Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	return reviews, totalRows, nil
+}
+
+func (r *ReviewRepositoryImpl) UpdateReview(ctx context.Context, review *model.Review) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Save(review).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+// GetRatingSummaries returns the average rating and number of visible reviews
+// of each given doctor. Doctors without reviews are left out.
+func (r *ReviewRepositoryImpl) GetRatingSummaries(ctx context.Context, doctorIds []uint) (map[uint]model.RatingSummary, error) {
+	var rows []model.RatingSummary
+	if err := r.DB.WithContext(ctx).Model(&model.Review{}).
+		Select("doctor_id, AVG(rating) AS average_rating, COUNT(*) AS review_count").
+		Where("doctor_id IN ? AND is_hidden = ?", doctorIds, false).
+		Group("doctor_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	summaries := make(map[uint]model.RatingSummary, len(rows))
+	for _, row := range rows {
+		summaries[row.DoctorId] = row
+	}
+	return summaries, nil
+}
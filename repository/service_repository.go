@@ -2,24 +2,27 @@ package repository
 
 import (
 	"booking-klinik/model"
+	"context"
 
 	"gorm.io/gorm"
 )
 
 type ServiceRepository interface {
-	CreateService(service *model.Service) error
-	GetAllServices(limit, offset int) ([]model.Service, error)
-	GetServiceById(id uint) (*model.Service, error)
-	UpdateService(serviceID uint, service model.Service) (*model.Service, error)
-	DeleteService(serviceID uint, deletedBy uint) error
+	CreateService(ctx context.Context, service *model.Service) error
+	GetAllServices(ctx context.Context, clinicId uint, limit, offset int) ([]model.Service, error)
+	GetServiceById(ctx context.Context, id uint) (*model.Service, error)
+	UpdateService(ctx context.Context, serviceID uint, service model.Service) (*model.Service, error)
+	DeleteService(ctx context.Context, serviceID uint, deletedBy uint) error
+	GetDoctorsByServiceId(ctx context.Context, serviceID uint) ([]model.Doctor, error)
+	GetActiveServices(ctx context.Context, clinicId uint, limit, offset int) ([]model.Service, int64, error)
 }
 
 type ServiceRepositoryImpl struct {
 	DB *gorm.DB
 }
 
-func (r *ServiceRepositoryImpl) CreateService(service *model.Service) error {
-	tx := r.DB.Begin()
+func (r *ServiceRepositoryImpl) CreateService(ctx context.Context, service *model.Service) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	if err := tx.Create(service).Error; err != nil {
 		tx.Rollback()
 		return err
@@ -28,27 +31,33 @@ func (r *ServiceRepositoryImpl) CreateService(service *model.Service) error {
 	return nil
 }
 
-func (r *ServiceRepositoryImpl) GetAllServices(limit, offset int) ([]model.Service, error) {
+// GetAllServices lists services; a non-zero clinicId keeps the services offered
+// at every clinic plus the ones specific to that clinic.
+func (r *ServiceRepositoryImpl) GetAllServices(ctx context.Context, clinicId uint, limit, offset int) ([]model.Service, error) {
 	var services []model.Service
-	if err := r.DB.Limit(limit).Offset(offset).Find(&services).Error; err != nil {
+	query := r.DB
+	if clinicId != 0 {
+		query = query.Where("clinic_id IS NULL OR clinic_id = ?", clinicId)
+	}
+	if err := query.Limit(limit).Offset(offset).Find(&services).Error; err != nil {
 		return nil, err
 	}
 	return services, nil
 }
 
-func (r *ServiceRepositoryImpl) GetServiceById(id uint) (*model.Service, error) {
+func (r *ServiceRepositoryImpl) GetServiceById(ctx context.Context, id uint) (*model.Service, error) {
 	var service model.Service
-	if err := r.DB.First(&service, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&service, id).Error; err != nil {
 		return nil, err
 	}
 	return &service, nil
 }
 
-func (r *ServiceRepositoryImpl) UpdateService(serviceID uint, service model.Service) (*model.Service, error) {
-	tx := r.DB.Begin()
+func (r *ServiceRepositoryImpl) UpdateService(ctx context.Context, serviceID uint, service model.Service) (*model.Service, error) {
+	tx := r.DB.WithContext(ctx).Begin()
 
 	var existingService model.Service
-	if err := r.DB.First(&existingService, serviceID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&existingService, serviceID).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -60,7 +69,7 @@ func (r *ServiceRepositoryImpl) UpdateService(serviceID uint, service model.Serv
 	existingService.IsActive = service.IsActive
 	existingService.UpdatedBy = service.UpdatedBy
 
-	if err := r.DB.Save(&existingService).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Save(&existingService).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -69,18 +78,18 @@ func (r *ServiceRepositoryImpl) UpdateService(serviceID uint, service model.Serv
 	return &existingService, nil
 }
 
-func (r *ServiceRepositoryImpl) DeleteService(serviceID uint, deletedBy uint) error {
-	tx := r.DB.Begin()
+func (r *ServiceRepositoryImpl) DeleteService(ctx context.Context, serviceID uint, deletedBy uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
 
 	var service model.Service
-	if err := r.DB.First(&service, serviceID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&service, serviceID).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	service.UpdatedBy = deletedBy
 
-	if err := r.DB.Save(&service).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Save(&service).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -92,3 +101,28 @@ func (r *ServiceRepositoryImpl) DeleteService(serviceID uint, deletedBy uint) er
 	tx.Commit()
 	return nil
 }
+
+func (r *ServiceRepositoryImpl) GetDoctorsByServiceId(ctx context.Context, serviceID uint) ([]model.Doctor, error) {
+	var doctors []model.Doctor
+	if err := r.DB.WithContext(ctx).Joins("JOIN doctor_services ON doctor_services.doctor_id = doctors.id").
+		Where("doctor_services.service_id = ?", serviceID).Preload("User").Find(&doctors).Error; err != nil {
+		return nil, err
+	}
+	return doctors, nil
+}
+
+func (r *ServiceRepositoryImpl) GetActiveServices(ctx context.Context, clinicId uint, limit, offset int) ([]model.Service, int64, error) {
+	var services []model.Service
+	var totalRows int64
+	query := r.DB.WithContext(ctx).Model(&model.Service{}).Where("is_active = ?", true)
+	if clinicId != 0 {
+		query = query.Where("clinic_id IS NULL OR clinic_id = ?", clinicId)
+	}
+	if err := query.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("name asc").Limit(limit).Offset(offset).Find(&services).Error; err != nil {
+		return nil, 0, err
+	}
+	return services, totalRows, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ServicePriceRepository interface {
+	CreateServicePrice(ctx context.Context, servicePrice *model.ServicePrice) error
+	GetServicePriceById(ctx context.Context, id uint) (*model.ServicePrice, error)
+	GetServicePricesByServiceId(ctx context.Context, serviceId uint) ([]model.ServicePrice, error)
+	GetEffectivePrice(ctx context.Context, serviceId uint, doctorId *uint, at time.Time) (*model.ServicePrice, error)
+	DeleteServicePrice(ctx context.Context, priceID uint, userID uint) error
+}
+
+type ServicePriceRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *ServicePriceRepositoryImpl) CreateServicePrice(ctx context.Context, servicePrice *model.ServicePrice) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(servicePrice).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *ServicePriceRepositoryImpl) GetServicePriceById(ctx context.Context, id uint) (*model.ServicePrice, error) {
+	var servicePrice model.ServicePrice
+	if err := r.DB.WithContext(ctx).First(&servicePrice, id).Error; err != nil {
+		return nil, err
+	}
+	return &servicePrice, nil
+}
+
+func (r *ServicePriceRepositoryImpl) GetServicePricesByServiceId(ctx context.Context, serviceId uint) ([]model.ServicePrice, error) {
+	var servicePrices []model.ServicePrice
+	if err := r.DB.WithContext(ctx).Where("service_id = ?", serviceId).Order("effective_from desc").Find(&servicePrices).Error; err != nil {
+		return nil, err
+	}
+	return servicePrices, nil
+}
+
+// GetEffectivePrice returns the latest entry in effect at the given time. A
+// doctor specific entry wins over the general price of the service.
+func (r *ServicePriceRepositoryImpl) GetEffectivePrice(ctx context.Context, serviceId uint, doctorId *uint, at time.Time) (*model.ServicePrice, error) {
+	var servicePrice model.ServicePrice
+
+	if doctorId != nil {
+		err := r.DB.WithContext(ctx).Where("service_id = ? AND doctor_id = ? AND effective_from <= ?", serviceId, *doctorId, at).
+			Order("effective_from desc").First(&servicePrice).Error
+		if err == nil {
+			return &servicePrice, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	if err := r.DB.WithContext(ctx).Where("service_id = ? AND doctor_id IS NULL AND effective_from <= ?", serviceId, at).
+		Order("effective_from desc").First(&servicePrice).Error; err != nil {
+		return nil, err
+	}
+	return &servicePrice, nil
+}
+
+func (r *ServicePriceRepositoryImpl) DeleteServicePrice(ctx context.Context, priceID uint, userID uint) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	var servicePrice model.ServicePrice
+	if err := tx.First(&servicePrice, priceID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	servicePrice.UpdatedBy = userID
+
+	if err := tx.Save(&servicePrice).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&model.ServicePrice{}, priceID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return nil
+}
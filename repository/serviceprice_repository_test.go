@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetEffectivePrice(t *testing.T) {
+	f := testutil.NewFixture(t)
+	repo := &ServicePriceRepositoryImpl{DB: f.DB}
+	january := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2030, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, price := range []model.ServicePrice{
+		{ServiceId: f.Service.ID, Price: 100000, EffectiveFrom: january},
+		{ServiceId: f.Service.ID, Price: 120000, EffectiveFrom: march},
+		{ServiceId: f.Service.ID, DoctorId: &f.Doctor.ID, Price: 150000, EffectiveFrom: march},
+	} {
+		var stored model.ServicePrice
+		testutil.MustCreate(t, f.DB, &stored, price)
+	}
+	other := f.AddDoctor(t, "Budi", "General Practitioner")
+
+	tests := []struct {
+		name     string
+		doctorId *uint
+		at       time.Time
+		want     int
+	}{
+		{"before the increase", nil, march.Add(-time.Hour), 100000},
+		{"after the increase", nil, march, 120000},
+		{"doctor price", &f.Doctor.ID, march.AddDate(0, 1, 0), 150000},
+		{"doctor price not yet in effect", &f.Doctor.ID, march.Add(-time.Hour), 100000},
+		{"doctor without own price", &other.ID, march, 120000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := repo.GetEffectivePrice(context.Background(), f.Service.ID, tt.doctorId, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price.Price != tt.want {
+				t.Errorf("price = %d, want %d", price.Price, tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.GetEffectivePrice(context.Background(), f.Service.ID, nil, january.Add(-time.Hour)); err == nil {
+		t.Error("GetEffectivePrice() before any price succeeded, want an error")
+	}
+}
@@ -2,23 +2,24 @@ package repository
 
 import (
 	"booking-klinik/model"
+	"context"
 
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	CreateUser(user *model.User) error
-	GetUserByEmail(email string) (*model.User, error)
-	GetUserById(id uint) (*model.User, error)
-	UpdatePassword(userID uint, newPassword string) error
+	CreateUser(ctx context.Context, user *model.User) error
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserById(ctx context.Context, id uint) (*model.User, error)
+	UpdatePassword(ctx context.Context, userID uint, newPassword string) error
 }
 
 type UserRepositoryImpl struct {
 	DB *gorm.DB
 }
 
-func (r *UserRepositoryImpl) CreateUser(user *model.User) error {
-	tx := r.DB.Begin()
+func (r *UserRepositoryImpl) CreateUser(ctx context.Context, user *model.User) error {
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 
 	if err := tx.Create(user).Error; err != nil {
@@ -28,25 +29,25 @@ func (r *UserRepositoryImpl) CreateUser(user *model.User) error {
 	return nil
 }
 
-func (r *UserRepositoryImpl) GetUserByEmail(email string) (*model.User, error) {
+func (r *UserRepositoryImpl) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
-	if err := r.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepositoryImpl) GetUserById(id uint) (*model.User, error) {
+func (r *UserRepositoryImpl) GetUserById(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.DB.First(&user, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepositoryImpl) UpdatePassword(userID uint, newPassword string) error {
+func (r *UserRepositoryImpl) UpdatePassword(ctx context.Context, userID uint, newPassword string) error {
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	defer tx.Commit()
 	var user model.User
 	if err := tx.First(&user, userID).Error; err != nil {
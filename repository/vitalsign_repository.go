@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"booking-klinik/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type VitalSignRepository interface {
+	CreateVitalSign(ctx context.Context, vitalSign *model.VitalSign) error
+	GetLatestVitalSignByBookingId(ctx context.Context, bookingId uint) (*model.VitalSign, error)
+	GetVitalSignsByUserId(ctx context.Context, userId uint, limit, offset int) ([]model.VitalSign, int64, error)
+}
+
+type VitalSignRepositoryImpl struct {
+	DB *gorm.DB
+}
+
+func (r *VitalSignRepositoryImpl) CreateVitalSign(ctx context.Context, vitalSign *model.VitalSign) error {
+	tx := r.DB.WithContext(ctx).Begin()
+	defer tx.Commit()
+
+	if err := tx.Create(vitalSign).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	return nil
+}
+
+func (r *VitalSignRepositoryImpl) GetLatestVitalSignByBookingId(ctx context.Context, bookingId uint) (*model.VitalSign, error) {
+	var vitalSign model.VitalSign
+	if err := r.DB.WithContext(ctx).Where("booking_id = ?", bookingId).Order("recorded_at desc").First(&vitalSign).Error; err != nil {
+		return nil, err
+	}
+	return &vitalSign, nil
+}
+
+func (r *VitalSignRepositoryImpl) GetVitalSignsByUserId(ctx context.Context, userId uint, limit, offset int) ([]model.VitalSign, int64, error) {
+	var vitalSigns []model.VitalSign
+	var totalRows int64
+	if err := r.DB.WithContext(ctx).Model(&model.VitalSign{}).Where("user_id = ?", userId).Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.DB.WithContext(ctx).Where("user_id = ?", userId).Order("recorded_at asc").Limit(limit).Offset(offset).Find(&vitalSigns).Error; err != nil {
+		return nil, 0, err
+	}
+	return vitalSigns, totalRows, nil
+}
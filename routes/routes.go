@@ -1,37 +1,128 @@
 package routes
 
 import (
+	"booking-klinik/config"
 	"booking-klinik/controllers"
+	"booking-klinik/metrics"
 	"booking-klinik/middleware"
+	"booking-klinik/payment"
 	"booking-klinik/repository"
 	"booking-klinik/services"
+	"booking-klinik/storage"
+	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB) *gin.Engine {
-	r := gin.Default()
+// SetupRouter builds the API router. Its request, booking and database
+// metrics are recorded on m, which is served on its own listener.
+func SetupRouter(db *gorm.DB, cfg *config.Config, m *metrics.Metrics) (*gin.Engine, error) {
+	r := gin.New()
+	r.Use(middleware.RequestIDMiddleware(), middleware.TracingMiddleware(cfg.Tracing.ServiceName), middleware.RequestLogger(), middleware.MetricsMiddleware(m), middleware.RecoveryMiddleware())
+	r.Use(middleware.CORSMiddleware(cfg.Server.CORSAllowedOrigins))
 
 	userRepository := &repository.UserRepositoryImpl{DB: db}
 	serviceRepository := &repository.ServiceRepositoryImpl{DB: db}
 	doctorScheduleRepository := &repository.DoctorScheduleRepositoryImpl{DB: db}
 	bookingRepository := &repository.BookingRepositoryImpl{DB: db}
 	doctorRepository := &repository.DoctorRepositoryImpl{DB: db}
+	vitalSignRepository := &repository.VitalSignRepositoryImpl{DB: db}
+	attachmentRepository := &repository.AttachmentRepositoryImpl{DB: db}
+	invoiceRepository := &repository.InvoiceRepositoryImpl{DB: db}
+	paymentRepository := &repository.PaymentRepositoryImpl{DB: db}
+	refundRepository := &repository.RefundRepositoryImpl{DB: db}
+	coverageRepository := &repository.CoverageRepositoryImpl{DB: db}
+	claimRepository := &repository.ClaimRepositoryImpl{DB: db}
+	promoRepository := &repository.PromoRepositoryImpl{DB: db}
+	servicePriceRepository := &repository.ServicePriceRepositoryImpl{DB: db}
+	licenseAlertRepository := &repository.LicenseAlertRepositoryImpl{DB: db}
+	reviewRepository := &repository.ReviewRepositoryImpl{DB: db}
+	resourceRepository := &repository.ResourceRepositoryImpl{DB: db}
+	clinicRepository := &repository.ClinicRepositoryImpl{DB: db}
 
-	userService := &services.UserServicesImpl{UserRepository: userRepository}
+	attachmentStorage := &storage.LocalStorage{BaseDir: cfg.Attachments.Dir}
+
+	paymentProvider, err := payment.NewProvider(cfg.Payment.Provider, cfg.Payment.WebhookSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	userService := &services.UserServicesImpl{UserRepository: userRepository, JWTSecret: cfg.JWT.Secret, JWTExpiresIn: cfg.JWT.ExpiresIn()}
+	invoiceService := &services.InvoiceServiceImpl{
+		InvoiceRepository: invoiceRepository,
+		BookingRepository: bookingRepository,
+		DoctorRepository:  doctorRepository,
+		PaymentRepository: paymentRepository,
+		TaxPercent:        cfg.Rules.InvoiceTaxPercent,
+	}
+	refundService := &services.RefundServiceImpl{
+		RefundRepository:  refundRepository,
+		PaymentRepository: paymentRepository,
+		BookingRepository: bookingRepository,
+		Provider:          paymentProvider,
+		Rules:             cfg.Rules.RefundRules,
+	}
+	coverageService := &services.CoverageServiceImpl{CoverageRepository: coverageRepository, ServiceRepository: serviceRepository}
+	claimService := &services.ClaimServiceImpl{ClaimRepository: claimRepository, CoverageRepository: coverageRepository, BookingRepository: bookingRepository}
+	promoService := &services.PromoServiceImpl{PromoRepository: promoRepository}
+	servicePriceService := &services.ServicePriceServiceImpl{ServicePriceRepository: servicePriceRepository, ServiceRepository: serviceRepository, DoctorRepository: doctorRepository}
+	licenseService := &services.LicenseServiceImpl{DoctorRepository: doctorRepository, LicenseAlertRepository: licenseAlertRepository, WarningDays: cfg.Rules.LicenseAlertDays}
 	doctorService := &services.DoctorServicesImpl{
-		DoctorRepository: doctorRepository,
-		UserRepository:   userRepository,
+		DoctorRepository:         doctorRepository,
+		UserRepository:           userRepository,
+		ServiceRepository:        serviceRepository,
+		DoctorScheduleRepository: doctorScheduleRepository,
 	}
+	resourceService := &services.ResourceServiceImpl{ResourceRepository: resourceRepository, ServiceRepository: serviceRepository, ClinicRepository: clinicRepository}
 	bookingService := &services.BookingServicesImpl{
 		BookingRepository:        bookingRepository,
 		DoctorRepository:         doctorRepository,
 		ServiceRepository:        serviceRepository,
 		DoctorScheduleRepository: doctorScheduleRepository,
-		UserRepository:           userRepository}
-	doctorScheduleService := &services.DoctorScheduleServiceImpl{DoctorScheduleRepository: doctorScheduleRepository, DoctorRepository: doctorRepository, ServiceRepository: serviceRepository}
-	serviceService := &services.ServiceServiceImpl{ServiceRepository: serviceRepository}
+		UserRepository:           userRepository,
+		InvoiceService:           invoiceService,
+		RefundService:            refundService,
+		ClaimService:             claimService,
+		PromoService:             promoService,
+		ServicePriceService:      servicePriceService,
+		ResourceService:          resourceService,
+		ClinicRepository:         clinicRepository,
+		Metrics:                  m,
+		CancellationCutoff:       time.Duration(cfg.Rules.CancellationCutoffHours) * time.Hour}
+	doctorScheduleService := &services.DoctorScheduleServiceImpl{DoctorScheduleRepository: doctorScheduleRepository, DoctorRepository: doctorRepository, ServiceRepository: serviceRepository, ResourceRepository: resourceRepository, ClinicRepository: clinicRepository}
+	clinicService := &services.ClinicServiceImpl{ClinicRepository: clinicRepository, DoctorRepository: doctorRepository, UserRepository: userRepository}
+	reviewService := &services.ReviewServiceImpl{ReviewRepository: reviewRepository, BookingRepository: bookingRepository, DoctorRepository: doctorRepository, WindowDays: cfg.Rules.ReviewWindowDays}
+	availabilityService := &services.AvailabilityServiceImpl{DoctorScheduleRepository: doctorScheduleRepository, BookingRepository: bookingRepository, ServiceRepository: serviceRepository, ResourceRepository: resourceRepository, ClinicRepository: clinicRepository, HorizonDays: cfg.Rules.AvailabilityHorizonDays}
+	serviceService := &services.ServiceServiceImpl{ServiceRepository: serviceRepository, ServicePriceService: servicePriceService, DoctorScheduleRepository: doctorScheduleRepository}
+	vitalSignService := &services.VitalSignServiceImpl{VitalSignRepository: vitalSignRepository, BookingRepository: bookingRepository, DoctorRepository: doctorRepository, BookingService: bookingService}
+	attachmentService := &services.AttachmentServiceImpl{AttachmentRepository: attachmentRepository, BookingService: bookingService, Storage: attachmentStorage}
+	paymentService := &services.PaymentServiceImpl{
+		PaymentRepository: paymentRepository,
+		BookingRepository: bookingRepository,
+		BookingService:    bookingService,
+		RefundService:     refundService,
+		InvoiceService:    invoiceService,
+		Provider:          paymentProvider,
+	}
+
+	//Health Routes
+	healthController := &controllers.HealthController{HealthService: &services.HealthServiceImpl{DB: db}}
+	r.GET("/healthz", healthController.Liveness)
+	r.GET("/readyz", healthController.Readiness)
+
+	//Database and queue metrics
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("getting database handle: %w", err)
+	}
+	if err := m.RegisterDB(sqlDB, cfg.Database.Name); err != nil {
+		return nil, fmt.Errorf("registering database metrics: %w", err)
+	}
+	if err := m.RegisterQueue(bookingService); err != nil {
+		return nil, fmt.Errorf("registering queue metrics: %w", err)
+	}
 
 	//User Routes
 	userController := &controllers.UserController{UserService: userService}
@@ -39,15 +130,21 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 	r.POST("/login", userController.LoginUser)
 
 	userGroup := r.Group("/user")
-	userGroup.Use(middleware.AuthMiddleware())
+	userGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
 	{
 		userGroup.PUT("/password", userController.UpdatePassword)
+		userGroup.POST("/doctor", middleware.RoleCheckMiddleware("admin"), userController.RegisterDoctor)
+		userGroup.POST("/nurse", middleware.RoleCheckMiddleware("admin"), userController.RegisterNurse)
 	}
 
 	//Booking Routes
-	bookingController := &controllers.BookingController{BookingService: bookingService, DoctorService: doctorService, UserService: userService}
+	bookingController := &controllers.BookingController{BookingService: bookingService, DoctorService: doctorService, UserService: userService, VitalSignService: vitalSignService, ClinicService: clinicService}
+	vitalSignController := &controllers.VitalSignController{VitalSignService: vitalSignService}
+	attachmentController := &controllers.AttachmentController{AttachmentService: attachmentService}
+	paymentController := &controllers.PaymentController{PaymentService: paymentService}
+	reviewController := &controllers.ReviewController{ReviewService: reviewService}
 	bookingGroup := r.Group("/booking")
-	bookingGroup.Use(middleware.AuthMiddleware())
+	bookingGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
 	{
 		bookingGroup.POST("/", bookingController.CreateBooking)
 		bookingGroup.GET("/", bookingController.GetAllBookings)
@@ -56,26 +153,134 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		bookingGroup.GET("/doctor/:doctor_id", bookingController.GetBookingsByDoctorId)
 		bookingGroup.PUT("/:id", bookingController.UpdateBooking)
 		bookingGroup.DELETE("/:id", bookingController.DeleteBooking)
+		bookingGroup.POST("/:id/cancel", bookingController.CancelBooking)
+		bookingGroup.POST("/:id/vitals", middleware.RoleCheckMiddleware("admin", "doctor", "nurse"), vitalSignController.RecordVitalSign)
+		bookingGroup.POST("/:id/attachments", attachmentController.UploadAttachment)
+		bookingGroup.GET("/:id/attachments", attachmentController.GetAttachmentsByBookingId)
+		bookingGroup.GET("/:id/attachments/:attachment_id", attachmentController.DownloadAttachment)
+		bookingGroup.DELETE("/:id/attachments/:attachment_id", attachmentController.DeleteAttachment)
+		bookingGroup.POST("/:id/payment", middleware.RoleCheckMiddleware("patient"), paymentController.CreateCharge)
+		bookingGroup.POST("/:id/payment/manual", middleware.RoleCheckMiddleware("admin"), paymentController.RecordManualPayment)
+		bookingGroup.GET("/:id/payment", paymentController.GetPaymentsByBookingId)
+		bookingGroup.POST("/:id/review", middleware.RoleCheckMiddleware("patient"), reviewController.CreateReview)
+	}
+
+	//Payment Routes
+	r.POST("/payment/webhook", paymentController.HandleWebhook)
+
+	//Refund Routes
+	refundController := &controllers.RefundController{RefundService: refundService}
+	refundGroup := r.Group("/refund")
+	refundGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin"))
+	{
+		refundGroup.GET("/", refundController.GetRefunds)
+		refundGroup.POST("/:id/approve", refundController.ApproveRefund)
+		refundGroup.POST("/:id/reject", refundController.RejectRefund)
+	}
+
+	//Promo Routes
+	promoController := &controllers.PromoController{PromoService: promoService}
+	promoGroup := r.Group("/promo")
+	promoGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin"))
+	{
+		promoGroup.POST("/", promoController.CreatePromo)
+		promoGroup.GET("/", promoController.GetAllPromos)
+		promoGroup.GET("/:id", promoController.GetPromoById)
+		promoGroup.PUT("/:id", promoController.UpdatePromo)
+		promoGroup.DELETE("/:id", promoController.DeletePromo)
+	}
+
+	//Claim Routes
+	claimController := &controllers.ClaimController{ClaimService: claimService, ClinicService: clinicService}
+	claimGroup := r.Group("/claim")
+	claimGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin"))
+	{
+		claimGroup.GET("/", claimController.GetClaims)
+		claimGroup.PUT("/:id/status", claimController.UpdateClaimStatus)
+		claimGroup.POST("/batch", claimController.CreateBatch)
+		claimGroup.GET("/batch/:id/export", claimController.ExportBatch)
+	}
+
+	//Invoice Routes
+	invoiceController := &controllers.InvoiceController{InvoiceService: invoiceService}
+	invoiceGroup := r.Group("/invoice")
+	invoiceGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		invoiceGroup.GET("/", invoiceController.GetInvoices)
+		invoiceGroup.GET("/:id", invoiceController.GetInvoiceById)
+		invoiceGroup.POST("/:id/items", middleware.RoleCheckMiddleware("admin", "doctor"), invoiceController.AddInvoiceItem)
+		invoiceGroup.PUT("/:id", middleware.RoleCheckMiddleware("admin"), invoiceController.UpdateInvoice)
+	}
+
+	//Vital Sign Routes
+	vitalSignGroup := r.Group("/vitals")
+	vitalSignGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		vitalSignGroup.GET("/patient/:user_id", vitalSignController.GetVitalSignTrend)
 	}
 
 	//Doctor Routes
 
 	doctorController := &controllers.DoctorController{DoctorService: doctorService}
+	licenseController := &controllers.LicenseController{LicenseService: licenseService}
 	doctorGroup := r.Group("/doctor")
-	doctorGroup.Use(middleware.AuthMiddleware(), middleware.RoleCheckMiddleware("admin", "doctor"))
+	doctorGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin", "doctor"))
 	{
 		doctorGroup.POST("/", doctorController.CreateDoctor)
 		doctorGroup.GET("/", doctorController.GetAllDoctors)
 		doctorGroup.GET("/:id", doctorController.GetDoctorById)
 		doctorGroup.PUT("/:id", doctorController.UpdateDoctor)
 		doctorGroup.DELETE("/:id", doctorController.DeleteDoctor)
+		doctorGroup.POST("/:id/services", middleware.RoleCheckMiddleware("admin"), doctorController.AddService)
+		doctorGroup.DELETE("/:id/services/:service_id", middleware.RoleCheckMiddleware("admin"), doctorController.RemoveService)
+	}
+	r.GET("/doctor/:id/services", middleware.AuthMiddleware(cfg.JWT.Secret), doctorController.GetServicesByDoctorId)
+	r.GET("/doctor/license-alerts", middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin"), licenseController.GetLicenseAlerts)
+
+	//Review Routes
+	reviewGroup := r.Group("/review")
+	reviewGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		reviewGroup.PUT("/:id/reply", middleware.RoleCheckMiddleware("admin", "doctor"), reviewController.ReplyToReview)
+		reviewGroup.PUT("/:id/moderation", middleware.RoleCheckMiddleware("admin"), reviewController.ModerateReview)
+		reviewGroup.GET("/doctor/:id", middleware.RoleCheckMiddleware("admin"), reviewController.GetDoctorReviews)
 	}
 
+	//Clinic Routes
+	clinicController := &controllers.ClinicController{ClinicService: clinicService}
+	clinicGroup := r.Group("/clinic")
+	clinicGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
+	{
+		clinicGroup.POST("/", middleware.SuperAdminMiddleware(), clinicController.CreateClinic)
+		clinicGroup.GET("/", clinicController.GetAllClinics)
+		clinicGroup.GET("/:id", clinicController.GetClinicById)
+		clinicGroup.PUT("/:id", middleware.RoleCheckMiddleware("admin"), clinicController.UpdateClinic)
+		clinicGroup.DELETE("/:id", middleware.SuperAdminMiddleware(), clinicController.DeleteClinic)
+		clinicGroup.POST("/:id/doctors", middleware.RoleCheckMiddleware("admin"), clinicController.AddDoctor)
+		clinicGroup.DELETE("/:id/doctors/:doctor_id", middleware.RoleCheckMiddleware("admin"), clinicController.RemoveDoctor)
+		clinicGroup.POST("/:id/admins", middleware.SuperAdminMiddleware(), clinicController.AssignAdmin)
+	}
+
+	//Directory Routes (public)
+	directoryController := &controllers.DirectoryController{DoctorService: doctorService, ServiceService: serviceService, ReviewService: reviewService}
+	directoryGroup := r.Group("/directory")
+	directoryGroup.Use(middleware.ETagMiddleware(300))
+	{
+		directoryGroup.GET("/doctors", directoryController.GetDoctorDirectory)
+		directoryGroup.GET("/services", directoryController.GetServiceCatalogue)
+		directoryGroup.GET("/clinics", clinicController.GetActiveClinics)
+		directoryGroup.GET("/doctors/:id/reviews", reviewController.GetDoctorReviews)
+	}
+
+	//Availability Routes
+	availabilityController := &controllers.AvailabilityController{AvailabilityService: availabilityService, ClinicService: clinicService}
+	r.GET("/availability/earliest", middleware.AuthMiddleware(cfg.JWT.Secret), availabilityController.GetEarliestAvailability)
+
 	//Doctor Schedule Routes
 
-	doctorScheduleController := &controllers.DoctorScheduleController{DoctorScheduleService: doctorScheduleService}
+	doctorScheduleController := &controllers.DoctorScheduleController{DoctorScheduleService: doctorScheduleService, ClinicService: clinicService}
 	doctorScheduleGroup := r.Group("/doctorschedule")
-	doctorScheduleGroup.Use(middleware.AuthMiddleware(), middleware.RoleCheckMiddleware("admin", "doctor"))
+	doctorScheduleGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin", "doctor"))
 	{
 		doctorScheduleGroup.POST("/", doctorScheduleController.CreateDoctorSchedule)
 		doctorScheduleGroup.GET("/", doctorScheduleController.GetAllDoctorSchedules)
@@ -84,22 +289,46 @@ func SetupRouter(db *gorm.DB) *gin.Engine {
 		doctorScheduleGroup.DELETE("/:id", doctorScheduleController.DeleteDoctorSchedule)
 	}
 
+	//Resource Routes
+	resourceController := &controllers.ResourceController{ResourceService: resourceService}
+	resourceGroup := r.Group("/resource")
+	resourceGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret), middleware.RoleCheckMiddleware("admin"))
+	{
+		resourceGroup.POST("/", resourceController.CreateResource)
+		resourceGroup.GET("/", resourceController.GetAllResources)
+		resourceGroup.GET("/:id", resourceController.GetResourceById)
+		resourceGroup.PUT("/:id", resourceController.UpdateResource)
+		resourceGroup.DELETE("/:id", resourceController.DeleteResource)
+	}
+
 	//Service Routes
 
 	serviceController := &controllers.ServiceController{ServiceService: serviceService}
+	coverageController := &controllers.CoverageController{CoverageService: coverageService}
+	servicePriceController := &controllers.ServicePriceController{ServicePriceService: servicePriceService}
 
 	serviceGroup := r.Group("/service")
-	serviceGroup.Use(middleware.AuthMiddleware())
+	serviceGroup.Use(middleware.AuthMiddleware(cfg.JWT.Secret))
 	{
 		serviceGroup.GET("/", serviceController.GetAllServices)
 		serviceGroup.GET("/:id", serviceController.GetServiceById)
+		serviceGroup.GET("/:id/doctors", serviceController.GetDoctorsByServiceId)
 		serviceGroup.Use(middleware.RoleCheckMiddleware("admin"))
 		{
 			serviceGroup.POST("/", serviceController.CreateService)
 			serviceGroup.PUT("/:id", serviceController.UpdateService)
 			serviceGroup.DELETE("/:id", serviceController.DeleteService)
+			serviceGroup.GET("/:id/coverage", coverageController.GetCoveragesByServiceId)
+			serviceGroup.POST("/:id/coverage", coverageController.CreateCoverage)
+			serviceGroup.DELETE("/:id/coverage/:coverage_id", coverageController.DeleteCoverage)
+			serviceGroup.GET("/:id/prices", servicePriceController.GetPriceHistory)
+			serviceGroup.POST("/:id/prices", servicePriceController.SchedulePrice)
+			serviceGroup.DELETE("/:id/prices/:price_id", servicePriceController.CancelScheduledPrice)
+			serviceGroup.GET("/:id/resources", resourceController.GetServiceResources)
+			serviceGroup.POST("/:id/resources", resourceController.AddServiceResource)
+			serviceGroup.DELETE("/:id/resources/:resource_id", resourceController.RemoveServiceResource)
 		}
 	}
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"booking-klinik/utils"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// demoPassword is shared by every demo account.
+const demoPassword = "demo1234"
+
+// runSeed fills a database with demo data: a super admin, a patient, two
+// services and two doctors with a week of schedules at the first clinic.
+// Doctors and schedules go through the importer, so they are validated like
+// any other.
+func runSeed(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	demo := flags.Bool("demo", false, "create demo accounts, services, doctors and schedules")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*demo {
+		return errors.New("usage: seed --demo")
+	}
+
+	commands, err := connectForCommand(cfg)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	admin, err := commands.User.RegisterUser(ctx, &model.User{Name: "Demo Admin", Email: "admin@demo.test", Password: demoPassword, Role: "superadmin"})
+	if err != nil {
+		return fmt.Errorf("demo data looks seeded already: %w", err)
+	}
+	if _, err := commands.User.RegisterUser(ctx, &model.User{Name: "Demo Patient", Email: "patient@demo.test", Password: demoPassword, Role: "patient", CreatedBy: admin.ID}); err != nil {
+		return err
+	}
+
+	clinics, _, err := commands.Clinic.GetAllClinics(ctx, true, 1, 0)
+	if err != nil {
+		return err
+	}
+	var clinic *model.Clinic
+	if len(clinics) > 0 {
+		clinic = &clinics[0]
+	} else if clinic, err = commands.Clinic.CreateClinic(ctx, model.ClinicRequest{Name: "Main Clinic"}, admin.ID); err != nil {
+		return err
+	}
+	loc, err := commands.Clinic.GetLocation(ctx, clinic.ID)
+	if err != nil {
+		return err
+	}
+
+	checkUp, err := commands.Service.CreateService(ctx, model.Service{Name: "General Check Up", Description: "Consultation with a general practitioner", Price: 150000, DurationMinutes: 30, CreatedBy: admin.ID})
+	if err != nil {
+		return err
+	}
+	dental, err := commands.Service.CreateService(ctx, model.Service{Name: "Dental Cleaning", Description: "Scaling and polishing", Price: 300000, DurationMinutes: 45, CreatedBy: admin.ID})
+	if err != nil {
+		return err
+	}
+
+	clinicID := strconv.FormatUint(uint64(clinic.ID), 10)
+	doctors := [][]string{
+		{"email", "name", "password", "specialization", "languages", "practice_since", "clinic_id", "service_ids"},
+		{"andi@demo.test", "Andi Wijaya", demoPassword, "General Practitioner", "Indonesian, English", "2012", clinicID, strconv.FormatUint(uint64(checkUp.ID), 10)},
+		{"sari@demo.test", "Sari Lestari", demoPassword, "Dentist", "Indonesian", "2016", clinicID, strconv.FormatUint(uint64(dental.ID), 10)},
+	}
+	fmt.Println("Doctors:")
+	report, err := commands.Importer.ImportDoctors(ctx, csvReader(doctors), admin.ID)
+	if err != nil {
+		return err
+	}
+	if err := printImportReport(report); err != nil {
+		return err
+	}
+
+	// Mornings for the week starting tomorrow at the clinic
+	schedules := [][]string{{"doctor_email", "service_id", "clinic_id", "date", "start_time", "end_time"}}
+	today := utils.DateIn(time.Now(), loc)
+	for day := 1; day <= 7; day++ {
+		date := today.AddDate(0, 0, day).Format("2006-01-02")
+		schedules = append(schedules,
+			[]string{"andi@demo.test", strconv.FormatUint(uint64(checkUp.ID), 10), clinicID, date, "09:00", "12:00"},
+			[]string{"sari@demo.test", strconv.FormatUint(uint64(dental.ID), 10), clinicID, date, "13:00", "16:00"},
+		)
+	}
+	fmt.Println("Schedules:")
+	report, err = commands.Importer.ImportSchedules(ctx, csvReader(schedules), admin.ID)
+	if err != nil {
+		return err
+	}
+	if err := printImportReport(report); err != nil {
+		return err
+	}
+
+	fmt.Printf("Demo accounts admin@demo.test, patient@demo.test, andi@demo.test and sari@demo.test use the password %s\n", demoPassword)
+	return nil
+}
+
+func csvReader(records [][]string) *bytes.Buffer {
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.WriteAll(records)
+	return &buffer
+}
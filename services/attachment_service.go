@@ -0,0 +1,197 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/storage"
+	"booking-klinik/tracing"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// MaxAttachmentSize is the largest file accepted for a single upload.
+const MaxAttachmentSize int64 = 10 << 20
+
+var allowedAttachmentTypes = []string{"application/pdf", "image/jpeg", "image/png"}
+
+var attachmentCategories = map[string]bool{
+	"referral":   true,
+	"lab_result": true,
+	"image":      true,
+	"other":      true,
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint, fileName string, size int64, category string, file io.Reader) (*model.Attachment, error)
+	GetAttachmentsByBookingId(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) ([]model.Attachment, error)
+	DownloadAttachment(ctx context.Context, bookingID uint, attachmentID uint, userID uint, userRole string, clinicID uint) (*model.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, bookingID uint, attachmentID uint, userID uint, userRole string, clinicID uint) error
+}
+
+type AttachmentServiceImpl struct {
+	AttachmentRepository repository.AttachmentRepository
+	BookingService       BookingService
+	Storage              storage.BlobStorage
+}
+
+func (s *AttachmentServiceImpl) UploadAttachment(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint, fileName string, size int64, category string, file io.Reader) (*model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.UploadAttachment")
+	defer span.End()
+
+	// Access to attachments follows the same ownership rules as the booking itself
+	booking, err := s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		return nil, errors.New("file is empty")
+	}
+	if size > MaxAttachmentSize {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d MB", MaxAttachmentSize>>20)
+	}
+
+	if category == "" {
+		category = "other"
+	}
+	if !attachmentCategories[category] {
+		return nil, errors.New("invalid attachment category")
+	}
+
+	// Detect the type from the content instead of trusting the client
+	header := make([]byte, 3072)
+	n, err := io.ReadFull(file, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	header = header[:n]
+
+	contentType := mimetype.Detect(header)
+	if !mimetype.EqualsAny(contentType.String(), allowedAttachmentTypes...) {
+		return nil, fmt.Errorf("file type %s is not allowed", contentType.String())
+	}
+
+	key, err := attachmentKey(booking.ID, contentType.Extension())
+	if err != nil {
+		return nil, err
+	}
+
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(header), file), MaxAttachmentSize)
+	if err := s.Storage.Put(key, body); err != nil {
+		return nil, err
+	}
+
+	attachment := model.Attachment{
+		BookingId:   booking.ID,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType.String(),
+		Size:        size,
+		StorageKey:  key,
+		Category:    category,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if err := s.AttachmentRepository.CreateAttachment(ctx, &attachment); err != nil {
+		s.Storage.Delete(key)
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (s *AttachmentServiceImpl) GetAttachmentsByBookingId(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) ([]model.Attachment, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.GetAttachmentsByBookingId")
+	defer span.End()
+
+	if _, err := s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.AttachmentRepository.GetAttachmentsByBookingId(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (s *AttachmentServiceImpl) DownloadAttachment(ctx context.Context, bookingID uint, attachmentID uint, userID uint, userRole string, clinicID uint) (*model.Attachment, io.ReadCloser, error) {
+	ctx, span := tracing.Start(ctx, "AttachmentService.DownloadAttachment")
+	defer span.End()
+
+	attachment, err := s.getBookingAttachment(ctx, bookingID, attachmentID, userID, userRole, clinicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := s.Storage.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, file, nil
+}
+
+func (s *AttachmentServiceImpl) DeleteAttachment(ctx context.Context, bookingID uint, attachmentID uint, userID uint, userRole string, clinicID uint) error {
+	ctx, span := tracing.Start(ctx, "AttachmentService.DeleteAttachment")
+	defer span.End()
+
+	attachment, err := s.getBookingAttachment(ctx, bookingID, attachmentID, userID, userRole, clinicID)
+	if err != nil {
+		return err
+	}
+
+	if userRole != "admin" && attachment.CreatedBy != userID {
+		return errors.New("you can only delete attachments you uploaded")
+	}
+
+	if err := s.AttachmentRepository.DeleteAttachment(ctx, attachment.ID, userID); err != nil {
+		return err
+	}
+	return s.Storage.Delete(attachment.StorageKey)
+}
+
+func (s *AttachmentServiceImpl) getBookingAttachment(ctx context.Context, bookingID uint, attachmentID uint, userID uint, userRole string, clinicID uint) (*model.Attachment, error) {
+	if _, err := s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.AttachmentRepository.GetAttachmentById(ctx, attachmentID)
+	if err != nil {
+		return nil, errors.New("attachment not found")
+	}
+	if attachment.BookingId != bookingID {
+		return nil, errors.New("attachment not found")
+	}
+	return attachment, nil
+}
+
+func attachmentKey(bookingID uint, extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("bookings/%d/%s%s", bookingID, hex.EncodeToString(random), extension), nil
+}
+
+func sanitizeFileName(fileName string) string {
+	name := filepath.Base(strings.ReplaceAll(fileName, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == '/' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." {
+		return "attachment"
+	}
+	return name
+}
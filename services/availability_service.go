@@ -0,0 +1,209 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+type AvailabilityService interface {
+	FindEarliestSlots(ctx context.Context, serviceID uint, specialization string, clinicID uint, from time.Time, limit int) ([]model.AvailableSlot, error)
+}
+
+type AvailabilityServiceImpl struct {
+	DoctorScheduleRepository repository.DoctorScheduleRepository
+	BookingRepository        repository.BookingRepository
+	ServiceRepository        repository.ServiceRepository
+	ResourceRepository       repository.ResourceRepository
+	ClinicRepository         repository.ClinicRepository
+	// HorizonDays is how far ahead the earliest-slot search looks.
+	HorizonDays int
+}
+
+// FindEarliestSlots returns up to limit open slots, earliest first, across
+// every doctor providing the service or, when serviceID is 0, every service of
+// doctors with the specialization; optionally at a single clinic. Each slot
+// lasts as long as the service of its schedule. Schedules and bookings of the
+// whole horizon are loaded in a few queries and matched in memory.
+func (s *AvailabilityServiceImpl) FindEarliestSlots(ctx context.Context, serviceID uint, specialization string, clinicID uint, from time.Time, limit int) ([]model.AvailableSlot, error) {
+	ctx, span := tracing.Start(ctx, "AvailabilityService.FindEarliestSlots")
+	defer span.End()
+
+	if serviceID == 0 && specialization == "" {
+		return nil, errors.New("service_id or specialization is required")
+	}
+	if serviceID != 0 {
+		service, err := s.ServiceRepository.GetServiceById(ctx, serviceID)
+		if err != nil || !service.IsActive {
+			return nil, errors.New("service is inactive or not found")
+		}
+		if service.DurationMinutes <= 0 {
+			return nil, errors.New("service has no duration")
+		}
+	}
+
+	// Schedule dates are calendar days in each clinic's timezone; starting a
+	// day early covers clinics where it is already the next day. Slots before
+	// from are skipped below.
+	firstDay := utils.DateIn(from, time.UTC).AddDate(0, 0, -1)
+	lastDay := firstDay.AddDate(0, 0, s.HorizonDays+1)
+
+	schedules, err := s.DoctorScheduleRepository.GetBookableSchedules(ctx, serviceID, specialization, clinicID, firstDay, lastDay)
+	if err != nil {
+		return nil, err
+	}
+	if len(schedules) == 0 {
+		return []model.AvailableSlot{}, nil
+	}
+
+	doctorIds := []uint{}
+	seen := map[uint]bool{}
+	for _, schedule := range schedules {
+		if !seen[schedule.DoctorId] {
+			seen[schedule.DoctorId] = true
+			doctorIds = append(doctorIds, schedule.DoctorId)
+		}
+	}
+
+	bookings, err := s.BookingRepository.GetActiveBookingsByDoctorsBetween(ctx, doctorIds, firstDay, lastDay.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	bookingsByDoctor := map[uint][]model.Booking{}
+	for _, booking := range bookings {
+		bookingsByDoctor[booking.DoctorId] = append(bookingsByDoctor[booking.DoctorId], booking)
+	}
+
+	// Bookings of other doctors still block a slot when they hold a room or
+	// piece of equipment the slot would need.
+	resourcesByService := map[uint][]model.Resource{}
+	resourceIds := []uint{}
+	for _, schedule := range schedules {
+		if _, ok := resourcesByService[schedule.ServiceId]; !ok {
+			serviceResources, err := s.ResourceRepository.GetResourcesByServiceId(ctx, schedule.ServiceId)
+			if err != nil {
+				return nil, err
+			}
+			resourcesByService[schedule.ServiceId] = serviceResources
+			for _, resource := range serviceResources {
+				resourceIds = append(resourceIds, resource.ID)
+			}
+		}
+		if schedule.RoomId != nil {
+			resourceIds = append(resourceIds, *schedule.RoomId)
+		}
+	}
+	resourceBookings := []model.Booking{}
+	if len(resourceIds) > 0 {
+		resourceBookings, err = s.ResourceRepository.GetBookingsUsingResources(ctx, resourceIds, firstDay, lastDay.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slots := []model.AvailableSlot{}
+	for _, schedule := range schedules {
+		if checkLicenseValidOn(&schedule.Doctor, schedule.Date) != nil {
+			continue
+		}
+
+		duration := time.Duration(schedule.Service.DurationMinutes) * time.Minute
+		blocking := bookingsByDoctor[schedule.DoctorId]
+		for _, booking := range resourceBookings {
+			if booking.DoctorId != schedule.DoctorId && holdsAny(booking, resourcesByService[schedule.ServiceId], schedule.RoomId) {
+				blocking = append(blocking, booking)
+			}
+		}
+
+		// A schedule never contributes more than limit slots, so the merged
+		// list below only needs limit slots per schedule to be correct.
+		found := 0
+		start := schedule.StartTime
+		for found < limit && !start.Add(duration).After(schedule.EndTime) {
+			if start.Before(from) {
+				start = start.Add(duration)
+				continue
+			}
+
+			end := start.Add(duration)
+			if busyUntil, busy := bookedUntil(blocking, start, end); busy {
+				start = busyUntil
+				continue
+			}
+
+			slots = append(slots, model.AvailableSlot{
+				DoctorID:       schedule.DoctorId,
+				DoctorName:     schedule.Doctor.User.Name,
+				Specialization: schedule.Doctor.Specialization,
+				ServiceID:      schedule.ServiceId,
+				ClinicID:       schedule.ClinicId,
+				StartTime:      start,
+				EndTime:        end,
+			})
+			found++
+			start = end
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartTime.Before(slots[j].StartTime)
+	})
+	if len(slots) > limit {
+		slots = slots[:limit]
+	}
+
+	// Show each slot in the timezone of its clinic
+	locations := map[uint]*time.Location{}
+	for i := range slots {
+		loc, ok := locations[slots[i].ClinicID]
+		if !ok {
+			loc = time.UTC
+			if clinic, err := s.ClinicRepository.GetClinicById(ctx, slots[i].ClinicID); err == nil {
+				if clinicLoc, err := time.LoadLocation(clinic.Timezone); err == nil {
+					loc = clinicLoc
+				}
+			}
+			locations[slots[i].ClinicID] = loc
+		}
+		slots[i].StartTime = slots[i].StartTime.In(loc)
+		slots[i].EndTime = slots[i].EndTime.In(loc)
+	}
+	return slots, nil
+}
+
+// bookedUntil reports whether any booking overlaps [start, end) and, if so,
+// when the latest overlapping booking ends.
+func bookedUntil(bookings []model.Booking, start, end time.Time) (time.Time, bool) {
+	var until time.Time
+	busy := false
+	for _, booking := range bookings {
+		if booking.StartAt.Before(end) && booking.EndAt.After(start) {
+			busy = true
+			if booking.EndAt.After(until) {
+				until = booking.EndAt
+			}
+		}
+	}
+	return until, busy
+}
+
+// holdsAny reports whether the booking holds one of the service's resources
+// or the given room.
+func holdsAny(booking model.Booking, resources []model.Resource, roomId *uint) bool {
+	for _, held := range booking.Resources {
+		if roomId != nil && held.ID == *roomId {
+			return true
+		}
+		for _, resource := range resources {
+			if held.ID == resource.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
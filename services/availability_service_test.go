@@ -0,0 +1,142 @@
+package services
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func newAvailabilityService(f *testutil.Fixture) *AvailabilityServiceImpl {
+	return &AvailabilityServiceImpl{
+		DoctorScheduleRepository: &repository.DoctorScheduleRepositoryImpl{DB: f.DB},
+		BookingRepository:        &repository.BookingRepositoryImpl{DB: f.DB},
+		ServiceRepository:        &repository.ServiceRepositoryImpl{DB: f.DB},
+		ResourceRepository:       &repository.ResourceRepositoryImpl{DB: f.DB},
+		ClinicRepository:         &repository.ClinicRepositoryImpl{DB: f.DB},
+		HorizonDays:              30,
+	}
+}
+
+var jakarta = time.FixedZone("WIB", 7*60*60)
+
+// at is the time on 7 January 2030 at the clinic.
+func at(hour, minute int) time.Time {
+	return time.Date(2030, 1, 7, hour, minute, 0, 0, jakarta)
+}
+
+// slotTimes lists the start and end of each slot as clock times.
+func slotTimes(slots []model.AvailableSlot) []string {
+	times := []string{}
+	for _, slot := range slots {
+		times = append(times, slot.StartTime.Format("15:04")+"-"+slot.EndTime.Format("15:04"))
+	}
+	return times
+}
+
+func checkSlots(t *testing.T, slots []model.AvailableSlot, want ...string) {
+	t.Helper()
+	got := slotTimes(slots)
+	if len(got) != len(want) {
+		t.Fatalf("slots = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("slots = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFindEarliestSlotsSkipsBookedTime(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newAvailabilityService(f)
+	f.AddSchedule(t, f.Doctor, f.Service, at(9, 0), at(11, 0))
+	f.AddBooking(t, f.Doctor, f.Service, at(9, 30), "confirmed")
+	f.AddBooking(t, f.Doctor, f.Service, at(10, 0), "cancelled")
+
+	slots, err := s.FindEarliestSlots(context.Background(), f.Service.ID, "", 0, at(0, 0), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSlots(t, slots, "09:00-09:30", "10:00-10:30", "10:30-11:00")
+	if slots[0].DoctorName != "Andi" || slots[0].StartTime.Location().String() != "Asia/Jakarta" {
+		t.Errorf("slot = %+v, want Andi's in the clinic timezone", slots[0])
+	}
+}
+
+func TestFindEarliestSlotsMergesDoctorsAndHonoursLimitAndFrom(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newAvailabilityService(f)
+	budi := f.AddDoctor(t, "Budi", "General Practitioner", f.Service)
+	f.AddSchedule(t, f.Doctor, f.Service, at(9, 0), at(11, 0))
+	f.AddSchedule(t, budi, f.Service, at(8, 45), at(10, 15))
+
+	slots, err := s.FindEarliestSlots(context.Background(), f.Service.ID, "", 0, at(9, 10), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSlots(t, slots, "09:15-09:45", "09:30-10:00", "09:45-10:15")
+}
+
+func TestFindEarliestSlotsSkipsResourcesHeldByOtherDoctors(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newAvailabilityService(f)
+	var xray model.Resource
+	testutil.MustCreate(t, f.DB, &xray, model.Resource{Name: "X-ray", Type: "equipment", IsActive: true, ClinicId: f.Clinic.ID})
+	if err := f.DB.Model(&f.Service).Association("Resources").Append(&xray); err != nil {
+		t.Fatal(err)
+	}
+	budi := f.AddDoctor(t, "Budi", "Radiologist", f.Service)
+	f.AddSchedule(t, f.Doctor, f.Service, at(9, 0), at(10, 0))
+	f.AddBooking(t, budi, f.Service, at(9, 0), "confirmed", xray)
+
+	slots, err := s.FindEarliestSlots(context.Background(), f.Service.ID, "General Practitioner", 0, at(0, 0), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSlots(t, slots, "09:30-10:00")
+}
+
+func TestFindEarliestSlotsBySpecializationUsesEachServiceDuration(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newAvailabilityService(f)
+	dental := f.AddService(t, "Dental Cleaning", 45)
+	sari := f.AddDoctor(t, "Sari", "Dentist", dental)
+	f.AddSchedule(t, sari, dental, at(9, 0), at(10, 30))
+	f.AddSchedule(t, f.Doctor, f.Service, at(8, 0), at(9, 0))
+	inactive := f.AddService(t, "Whitening", 60)
+	f.DB.Model(&inactive).Update("is_active", false)
+	if err := f.DB.Model(&sari).Association("Services").Append(&inactive); err != nil {
+		t.Fatal(err)
+	}
+	f.AddSchedule(t, sari, inactive, at(11, 0), at(12, 0))
+
+	slots, err := s.FindEarliestSlots(context.Background(), 0, "Dentist", 0, at(0, 0), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSlots(t, slots, "09:00-09:45", "09:45-10:30")
+	if slots[0].ServiceID != dental.ID {
+		t.Errorf("ServiceID = %d, want %d", slots[0].ServiceID, dental.ID)
+	}
+
+	if _, err := s.FindEarliestSlots(context.Background(), 0, "", 0, at(0, 0), 5); err == nil {
+		t.Error("FindEarliestSlots() without a service or specialization succeeded")
+	}
+}
+
+func TestFindEarliestSlotsSkipsExpiredLicenses(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newAvailabilityService(f)
+	f.AddSchedule(t, f.Doctor, f.Service, at(9, 0), at(10, 0))
+	expired := at(0, 0).AddDate(0, 0, -1)
+	f.DB.Model(&f.Doctor).Update("sip_expires_at", expired)
+
+	slots, err := s.FindEarliestSlots(context.Background(), f.Service.ID, "", 0, at(0, 0), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSlots(t, slots)
+}
@@ -1,23 +1,31 @@
 package services
 
 import (
+	"booking-klinik/metrics"
 	"booking-klinik/model"
 	"booking-klinik/repository"
+	"booking-klinik/tracing"
 	"booking-klinik/utils"
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type BookingService interface {
-	CreateBooking(booking model.Booking) (*model.Booking, error)
-	GetAllBookings(limit, offset int, userRole string, userId uint) ([]model.Booking, *utils.Paginator, error)
-	GetBookingById(id uint, userID uint, userRole string) (*model.Booking, error)
-	GetBookingsByUserId(userId uint, limit, offset int) ([]model.Booking, *utils.Paginator, error)
-	GetBookingsByDoctorId(doctorId uint, limit, offset int) ([]model.Booking, *utils.Paginator, error)
-	GetDoctorName(doctorId uint) (string, error)
-	UpdateBooking(bookingID uint, booking model.Booking, userRole string) (*model.Booking, error)
-	DeleteBooking(bookingID uint, userRole string, userID uint) error
+	CreateBooking(ctx context.Context, booking model.Booking) (*model.Booking, error)
+	GetAllBookings(ctx context.Context, limit, offset int, userRole string, userId uint, clinicId uint) ([]model.Booking, *utils.Paginator, error)
+	GetBookingById(ctx context.Context, id uint, userID uint, userRole string, clinicID uint) (*model.Booking, error)
+	GetBookingsByUserId(ctx context.Context, patientID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.Booking, *utils.Paginator, error)
+	GetBookingsByDoctorId(ctx context.Context, doctorID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.Booking, *utils.Paginator, error)
+	GetDoctorName(ctx context.Context, doctorId uint) (string, error)
+	UpdateBooking(ctx context.Context, bookingID uint, booking model.Booking, userRole string, clinicID uint) (*model.Booking, error)
+	DeleteBooking(ctx context.Context, bookingID uint, userRole string, userID uint, clinicID uint) error
+	CancelBooking(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint, reason string) (*model.Booking, error)
+	GetTodayQueueLengths(ctx context.Context, now time.Time) (map[uint]int64, error)
 }
 
 type BookingServicesImpl struct {
@@ -26,84 +34,109 @@ type BookingServicesImpl struct {
 	ServiceRepository        repository.ServiceRepository
 	DoctorScheduleRepository repository.DoctorScheduleRepository
 	UserRepository           repository.UserRepository
+	InvoiceService           InvoiceService
+	RefundService            RefundService
+	ClaimService             ClaimService
+	PromoService             PromoService
+	ServicePriceService      ServicePriceService
+	ResourceService          ResourceService
+	ClinicRepository         repository.ClinicRepository
+	Metrics                  *metrics.Metrics
+	// CancellationCutoff is how close to the appointment a patient may still
+	// cancel it; zero allows cancelling until it starts.
+	CancellationCutoff time.Duration
 }
 
-func (s *BookingServicesImpl) CreateBooking(booking model.Booking) (*model.Booking, error) {
+func (s *BookingServicesImpl) CreateBooking(ctx context.Context, booking model.Booking) (*model.Booking, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.CreateBooking")
+	defer span.End()
+
 	// Validate the booking
-	doctor, err := s.DoctorRepository.GetDoctorById(booking.DoctorId)
+	doctor, err := s.DoctorRepository.GetDoctorById(ctx, booking.DoctorId)
 	if err != nil {
-		return nil, errors.New("doctor not found")
+		return nil, s.rejectBooking("doctor_not_found", errors.New("doctor not found"))
 	}
-	service, err := s.ServiceRepository.GetServiceById(booking.ServiceId)
+	service, err := s.ServiceRepository.GetServiceById(ctx, booking.ServiceId)
 	if err != nil || !service.IsActive {
-		return nil, errors.New("service is inactive or not found")
+		return nil, s.rejectBooking("inactive_service", errors.New("service is inactive or not found"))
 	}
 
-	schedules, err := s.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(doctor.ID)
+	hasService, err := s.DoctorRepository.HasService(ctx, doctor.ID, service.ID)
 	if err != nil {
-		return nil, errors.New("doctor schedule not found")
+		return nil, err
 	}
-
-	if len(schedules) == 0 {
-		return nil, errors.New("doctor schedule not found")
+	if !hasService {
+		return nil, s.rejectBooking("service_not_offered", errors.New("doctor does not provide this service"))
 	}
 
-	available := false
-
-	for _, schedule := range schedules {
-		fmt.Println("Schedule date:", schedule.Date.Format("2006-01-02"))
-		fmt.Println("Booking date :", booking.BookingDate.Format("2006-01-02"))
-		fmt.Println("Schedule start:", schedule.StartTime)
-		fmt.Println("Booking time  :", booking.BookingTime)
-		if schedule.Date.Format("2006-01-02") == booking.BookingDate.Format("2006-01-02") {
-			if (booking.BookingTime.After(schedule.StartTime) || booking.BookingTime.Equal(schedule.StartTime)) && (booking.BookingTime.Before(schedule.EndTime) || booking.BookingTime.Equal(schedule.EndTime)) {
-				available = true
-				break
-			}
-		}
+	if err := s.ClaimService.ValidatePayer(ctx, &booking); err != nil {
+		return nil, s.rejectBooking("payer", err)
 	}
 
-	if !available {
-		return nil, errors.New("doctor is not available at this date and time")
+	// The whole appointment, not just its start, has to fit in a schedule
+	booking.EndAt = booking.StartAt.Add(time.Duration(service.DurationMinutes) * time.Minute)
+	if err := s.resolveSlot(ctx, &booking); err != nil {
+		return nil, err
 	}
 
-	conflict, nextAvailableTime, err := s.CheckBookingConflict(booking.DoctorId, booking.BookingDate, booking.BookingTime, service.DurationMinutes)
+	// Keep the price on the booking so later price changes don't alter it
+	booking.Price, err = s.ServicePriceService.ResolvePrice(ctx, service, &booking.DoctorId, booking.StartAt)
 	if err != nil {
-		return nil, errors.New("error checking booking conflict")
+		return nil, err
 	}
+	booking.DiscountAmount = 0
 
-	if conflict {
-		return nil, fmt.Errorf("doctor is already booked at this time. Next available slot starts from %s", nextAvailableTime)
+	var redemption *repository.PromoRedemption
+	if booking.PromoCode != "" {
+		promo, err := s.PromoService.ApplyPromo(ctx, &booking)
+		if err != nil {
+			return nil, s.rejectBooking("promo", err)
+		}
+		redemption = &repository.PromoRedemption{
+			Usage: model.PromoUsage{
+				PromoId:        promo.ID,
+				UserId:         booking.UserId,
+				DiscountAmount: booking.DiscountAmount,
+			},
+			MaxUses:           promo.MaxUses,
+			MaxUsesPerPatient: promo.MaxUsesPerPatient,
+		}
 	}
+	booking.FinalPrice = booking.Price - booking.DiscountAmount
 
-	// Create the booking
-	if err := s.BookingRepository.CreateBooking(&booking); err != nil {
-		return nil, err
+	// Create the booking; the slot is checked while the doctor is locked
+	if err := s.BookingRepository.CreateBooking(ctx, &booking, redemption); err != nil {
+		return nil, s.slotError(ctx, &booking, err)
 	}
 
+	s.Metrics.BookingCreated()
+	slog.InfoContext(ctx, "booking created", "booking_id", booking.ID, "doctor_id", booking.DoctorId, "service_id", booking.ServiceId, "user_id", booking.UserId)
 	return &booking, nil
 
 }
 
-func (s *BookingServicesImpl) GetAllBookings(limit, offset int, userRole string, userId uint) ([]model.Booking, *utils.Paginator, error) {
+func (s *BookingServicesImpl) GetAllBookings(ctx context.Context, limit, offset int, userRole string, userId uint, clinicId uint) ([]model.Booking, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetAllBookings")
+	defer span.End()
+
 	var bookings []model.Booking
 	var totalRows int64
 	var err error
 
 	switch userRole {
 	case "patient":
-		bookings, totalRows, err = s.BookingRepository.GetBookingsByUserId(userId, limit, offset)
+		bookings, totalRows, err = s.BookingRepository.GetBookingsByUserId(ctx, userId, 0, 0, limit, offset)
 	case "doctor":
-		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(userId)
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userId)
 		if err != nil {
 			return nil, nil, err
 		}
-		bookings, totalRows, err = s.BookingRepository.GetBookingsByDoctorId(doctorID, limit, offset)
+		bookings, totalRows, err = s.BookingRepository.GetBookingsByDoctorId(ctx, doctorID, 0, limit, offset)
 		if err != nil {
 			return nil, nil, err
 		}
 	case "admin":
-		bookings, totalRows, err = s.BookingRepository.GetAllBookings(limit, offset)
+		bookings, totalRows, err = s.BookingRepository.GetAllBookings(ctx, clinicId, limit, offset)
 	default:
 		return nil, nil, errors.New("invalid user role")
 	}
@@ -118,9 +151,15 @@ func (s *BookingServicesImpl) GetAllBookings(limit, offset int, userRole string,
 	return bookings, pagination, nil
 }
 
-func (s *BookingServicesImpl) GetBookingById(id uint, userID uint, userRole string) (*model.Booking, error) {
+// GetBookingById returns a booking the caller may see: their own as a
+// patient, their patients' as a doctor, and as an admin any booking of
+// clinicID, or of every clinic when it is 0.
+func (s *BookingServicesImpl) GetBookingById(ctx context.Context, id uint, userID uint, userRole string, clinicID uint) (*model.Booking, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetBookingById")
+	defer span.End()
+
 	if userRole == "patient" {
-		booking, err := s.BookingRepository.GetBookingById(id)
+		booking, err := s.BookingRepository.GetBookingById(ctx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -129,11 +168,11 @@ func (s *BookingServicesImpl) GetBookingById(id uint, userID uint, userRole stri
 		}
 		return booking, nil
 	} else if userRole == "doctor" {
-		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(userID)
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
 		if err != nil {
 			return nil, err
 		}
-		booking, err := s.BookingRepository.GetBookingById(id)
+		booking, err := s.BookingRepository.GetBookingById(ctx, id)
 		if err != nil {
 			return nil, err
 		}
@@ -142,13 +181,43 @@ func (s *BookingServicesImpl) GetBookingById(id uint, userID uint, userRole stri
 		}
 		return booking, nil
 	} else if userRole == "admin" {
-		return s.BookingRepository.GetBookingById(id)
+		booking, err := s.BookingRepository.GetBookingById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkBookingClinic(booking, clinicID); err != nil {
+			return nil, err
+		}
+		return booking, nil
 	}
 	return nil, errors.New("invalid user role")
 }
 
-func (s *BookingServicesImpl) GetBookingsByUserId(userId uint, limit, offset int) ([]model.Booking, *utils.Paginator, error) {
-	bookings, totalRows, err := s.BookingRepository.GetBookingsByUserId(userId, limit, offset)
+// GetBookingsByUserId lists a patient's bookings: to the patient themselves,
+// to a doctor only those with that doctor, and to an admin those of clinicID,
+// or of every clinic when it is 0.
+func (s *BookingServicesImpl) GetBookingsByUserId(ctx context.Context, patientID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.Booking, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetBookingsByUserId")
+	defer span.End()
+
+	var doctorID uint
+	switch userRole {
+	case "patient":
+		if patientID != userID {
+			return nil, nil, errors.New("you can only access your own bookings")
+		}
+	case "doctor":
+		var err error
+		doctorID, err = s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "admin":
+	default:
+		return nil, nil, errors.New("invalid user role")
+	}
+
+	bookings, totalRows, err := s.BookingRepository.GetBookingsByUserId(ctx, patientID, doctorID, clinicID, limit, offset)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -158,8 +227,38 @@ func (s *BookingServicesImpl) GetBookingsByUserId(userId uint, limit, offset int
 	return bookings, pagination, nil
 }
 
-func (s *BookingServicesImpl) GetBookingsByDoctorId(doctorId uint, limit, offset int) ([]model.Booking, *utils.Paginator, error) {
-	booking, totalRows, err := s.BookingRepository.GetBookingsByDoctorId(doctorId, limit, offset)
+// GetBookingsByDoctorId lists a doctor's bookings: to a patient only their
+// own, to a doctor only when they are that doctor, and to an admin those of
+// clinicID, or of every clinic when it is 0.
+func (s *BookingServicesImpl) GetBookingsByDoctorId(ctx context.Context, doctorID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.Booking, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetBookingsByDoctorId")
+	defer span.End()
+
+	var booking []model.Booking
+	var totalRows int64
+	var err error
+
+	switch userRole {
+	case "patient":
+		booking, totalRows, err = s.BookingRepository.GetBookingsByUserId(ctx, userID, doctorID, clinicID, limit, offset)
+	case "doctor":
+		ownDoctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ownDoctorID != doctorID {
+			return nil, nil, errors.New("you can only access your patients bookings")
+		}
+		booking, totalRows, err = s.BookingRepository.GetBookingsByDoctorId(ctx, doctorID, clinicID, limit, offset)
+		if err != nil {
+			return nil, nil, err
+		}
+	case "admin":
+		booking, totalRows, err = s.BookingRepository.GetBookingsByDoctorId(ctx, doctorID, clinicID, limit, offset)
+	default:
+		return nil, nil, errors.New("invalid user role")
+	}
+
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,15 +270,16 @@ func (s *BookingServicesImpl) GetBookingsByDoctorId(doctorId uint, limit, offset
 	return booking, pagination, nil
 }
 
-func (s *BookingServicesImpl) UpdateBooking(bookingID uint, booking model.Booking, userRole string) (*model.Booking, error) {
-	existingBooking, err := s.BookingRepository.GetBookingById(bookingID)
+func (s *BookingServicesImpl) UpdateBooking(ctx context.Context, bookingID uint, booking model.Booking, userRole string, clinicID uint) (*model.Booking, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.UpdateBooking")
+	defer span.End()
+
+	existingBooking, err := s.GetBookingById(ctx, bookingID, booking.UserId, userRole, clinicID)
 	if err != nil {
 		return nil, err
 	}
 
-	if userRole == "patient" && existingBooking.UserId != booking.UserId {
-		return nil, errors.New("you can only update your own bookings")
-	}
+	previousStatus := existingBooking.Status
 
 	if existingBooking.Status == "confirmed" && userRole == "patient" {
 		return nil, errors.New("booking already confirmed")
@@ -187,35 +287,58 @@ func (s *BookingServicesImpl) UpdateBooking(bookingID uint, booking model.Bookin
 
 	if userRole == "patient" {
 		existingBooking.Notes = booking.Notes
-		if !booking.BookingTime.IsZero() {
-			existingBooking.BookingTime = booking.BookingTime
-		}
-		if !booking.BookingDate.IsZero() {
-			existingBooking.BookingDate = booking.BookingDate
+		if !booking.StartAt.IsZero() && !booking.StartAt.Equal(existingBooking.StartAt) {
+			return s.rescheduleBooking(ctx, existingBooking, booking.StartAt, booking.UserId)
 		}
 	} else if userRole == "doctor" || userRole == "admin" {
-		existingBooking.Status = booking.Status
+		if booking.Status != "" && booking.Status != existingBooking.Status {
+			if err := checkStatusChange(existingBooking, booking.Status, time.Now()); err != nil {
+				return nil, err
+			}
+			existingBooking.Status = booking.Status
+		}
 		existingBooking.Notes = booking.Notes
 	}
 
 	existingBooking.UpdatedBy = booking.UserId
-	existingBooking, err = s.BookingRepository.UpdateBooking(bookingID, *existingBooking)
+	if previousStatus != "cancelled" && existingBooking.Status == "cancelled" {
+		if _, err := s.RefundService.CancelWithRefunds(ctx, existingBooking, "cancelled by "+userRole, booking.UserId); err != nil {
+			return nil, err
+		}
+		s.Metrics.RecordBookingStatus(existingBooking.Status)
+		return existingBooking, nil
+	}
+
+	existingBooking, err = s.BookingRepository.UpdateBooking(ctx, bookingID, *existingBooking)
 	if err != nil {
 		return nil, err
 	}
 
+	if previousStatus != existingBooking.Status {
+		s.Metrics.RecordBookingStatus(existingBooking.Status)
+	}
+
+	if previousStatus != "completed" && existingBooking.Status == "completed" {
+		if _, err := s.InvoiceService.CreateInvoiceForBooking(ctx, existingBooking.ID, booking.UserId); err != nil {
+			return nil, err
+		}
+		if _, err := s.ClaimService.CreateClaimForBooking(ctx, existingBooking.ID, booking.UserId); err != nil {
+			return nil, err
+		}
+	}
+
 	return existingBooking, nil
 }
 
-func (s *BookingServicesImpl) DeleteBooking(bookingID uint, userRole string, userID uint) error {
-	booking, err := s.BookingRepository.GetBookingById(bookingID)
+func (s *BookingServicesImpl) DeleteBooking(ctx context.Context, bookingID uint, userRole string, userID uint, clinicID uint) error {
+	ctx, span := tracing.Start(ctx, "BookingService.DeleteBooking")
+	defer span.End()
+
+	booking, err := s.GetBookingById(ctx, bookingID, userID, userRole, clinicID)
 	if err != nil {
 		return err
 	}
 	if booking.Status == "confirmed" {
-		if userRole == "patient" && booking.UserId != userID {
-			return errors.New("you can only delete your own bookings")
-		}
 		return errors.New("booking already confirmed")
 	}
 
@@ -223,45 +346,186 @@ func (s *BookingServicesImpl) DeleteBooking(bookingID uint, userRole string, use
 		return errors.New("doctors cannot delete bookings")
 	}
 
-	err = s.BookingRepository.DeleteBooking(bookingID, userID)
+	err = s.BookingRepository.DeleteBooking(ctx, bookingID, userID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *BookingServicesImpl) CheckBookingConflict(doctorId uint, bookingDate time.Time, bookingTime time.Time, durationMinutes int) (bool, time.Time, error) {
-	bookings, err := s.BookingRepository.GetBookingsByDoctorAndDate(doctorId, bookingDate)
+// CancelBooking cancels a booking on behalf of its patient, doctor or an admin
+// and refunds any payment according to the refund policy. Cancelling an
+// already cancelled booking retries the refunds the provider failed.
+func (s *BookingServicesImpl) CancelBooking(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint, reason string) (*model.Booking, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.CancelBooking")
+	defer span.End()
+
+	booking, err := s.GetBookingById(ctx, bookingID, userID, userRole, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch booking.Status {
+	case "cancelled":
+		if _, err := s.RefundService.RetryFailedRefunds(ctx, booking.ID, userID); err != nil {
+			return nil, err
+		}
+		return booking, nil
+	case "completed", "no_show":
+		return nil, errors.New("booking can no longer be cancelled")
+	}
+	if userRole == "patient" && s.CancellationCutoff > 0 && time.Until(booking.StartAt) < s.CancellationCutoff {
+		return nil, fmt.Errorf("bookings can no longer be cancelled less than %d hours before the appointment", int(s.CancellationCutoff.Hours()))
+	}
+
+	if _, err := s.RefundService.CancelWithRefunds(ctx, booking, reason, userID); err != nil {
+		return nil, err
+	}
+
+	s.Metrics.RecordBookingStatus(booking.Status)
+	slog.InfoContext(ctx, "booking cancelled", "booking_id", booking.ID, "user_id", userID, "role", userRole)
+	return booking, nil
+}
+
+// GetTodayQueueLengths counts, per doctor, the bookings still pending or
+// confirmed that start today in the timezone of their clinic.
+func (s *BookingServicesImpl) GetTodayQueueLengths(ctx context.Context, now time.Time) (map[uint]int64, error) {
+	clinics, _, err := s.ClinicRepository.GetAllClinics(ctx, true, -1, -1)
 	if err != nil {
-		return false, time.Time{}, err
+		return nil, err
 	}
 
-	newEnd := bookingTime.Add(time.Duration(durationMinutes) * time.Minute)
-	for _, booking := range bookings {
-		service, err := s.ServiceRepository.GetServiceById(booking.ServiceId)
+	lengths := map[uint]int64{}
+	for _, clinic := range clinics {
+		loc, err := time.LoadLocation(clinic.Timezone)
 		if err != nil {
-			return false, time.Time{}, err
+			return nil, err
+		}
+		local := now.In(loc)
+		dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		counts, err := s.BookingRepository.CountOpenBookingsByDoctor(ctx, clinic.ID, dayStart, dayStart.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, err
 		}
+		for doctorID, count := range counts {
+			lengths[doctorID] += count
+		}
+	}
+	return lengths, nil
+}
+
+// bookingTransitions lists the statuses a doctor or admin may move a booking
+// to from each status. Cancelling always goes through CancelWithRefunds.
+var bookingTransitions = map[string][]string{
+	"pending":   {"confirmed", "cancelled"},
+	"confirmed": {"completed", "no_show", "cancelled"},
+}
+
+// checkStatusChange fails unless the booking may move to status at now. A
+// booking can only be completed or marked a no-show once it has started.
+func checkStatusChange(booking *model.Booking, status string, now time.Time) error {
+	allowed := false
+	for _, next := range bookingTransitions[booking.Status] {
+		allowed = allowed || next == status
+	}
+	if !allowed {
+		return fmt.Errorf("a %s booking cannot be set to %s", booking.Status, status)
+	}
+	if (status == "completed" || status == "no_show") && now.Before(booking.StartAt) {
+		return fmt.Errorf("a booking cannot be set to %s before it starts", status)
+	}
+	return nil
+}
+
+// rejectBooking counts a booking refused by one of the booking rules and
+// returns the error explaining why.
+func (s *BookingServicesImpl) rejectBooking(reason string, err error) error {
+	s.Metrics.BookingRejected(reason)
+	return err
+}
+
+// rescheduleBooking moves a booking to startAt, keeping its length. The new
+// slot goes through the same schedule, booking and resource checks as a new
+// booking.
+func (s *BookingServicesImpl) rescheduleBooking(ctx context.Context, booking *model.Booking, startAt time.Time, userID uint) (*model.Booking, error) {
+	booking.EndAt = startAt.Add(booking.EndAt.Sub(booking.StartAt))
+	booking.StartAt = startAt
+	booking.UpdatedBy = userID
+	if err := s.resolveSlot(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	if err := s.BookingRepository.RescheduleBooking(ctx, booking); err != nil {
+		return nil, s.slotError(ctx, booking, err)
+	}
+	return s.BookingRepository.GetBookingById(ctx, booking.ID)
+}
+
+// resolveSlot finds the doctor's schedule covering the whole booking and sets
+// the clinic and the resources the booking holds: those of its service and
+// the schedule's room.
+func (s *BookingServicesImpl) resolveSlot(ctx context.Context, booking *model.Booking) error {
+	matchedSchedule, err := s.DoctorScheduleRepository.GetScheduleCovering(ctx, booking.DoctorId, booking.ServiceId, booking.StartAt, booking.EndAt)
+	if err != nil {
+		return s.rejectBooking("no_schedule", errors.New("doctor is not available at this date and time"))
+	}
+
+	// Rooms and equipment can only be used by one booking at a time
+	resources, err := s.ResourceService.GetResourcesByServiceId(ctx, booking.ServiceId)
+	if err != nil {
+		return err
+	}
+	if matchedSchedule.RoomId != nil {
+		resources = append(resources, model.Resource{Model: gorm.Model{ID: *matchedSchedule.RoomId}})
+	}
+	booking.Resources = resources
+	booking.ClinicId = matchedSchedule.ClinicId
+	return nil
+}
 
-		existingEnd := booking.BookingTime.Add(time.Duration(service.DurationMinutes) * time.Minute)
+// slotError explains a slot taken by another booking to the patient, with
+// times in the timezone of the booking's clinic.
+func (s *BookingServicesImpl) slotError(ctx context.Context, booking *model.Booking, err error) error {
+	var conflict *repository.BookingConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
 
-		if bookingTime.Before(existingEnd) && newEnd.After(booking.BookingTime) {
-			return true, existingEnd, nil
+	until := conflict.Until.UTC()
+	if clinic, err := s.ClinicRepository.GetClinicById(ctx, booking.ClinicId); err == nil {
+		if loc, err := time.LoadLocation(clinic.Timezone); err == nil {
+			until = until.In(loc)
 		}
 	}
 
-	return false, time.Time{}, nil
+	if conflict.Resource != nil {
+		return s.rejectBooking("resource_conflict", fmt.Errorf("%s is already in use at this time until %s", conflict.Resource.Name, until.Format(time.RFC3339)))
+	}
+	return s.rejectBooking("conflict", fmt.Errorf("doctor is already booked at this time. Next available slot starts from %s", until.Format(time.RFC3339)))
 }
 
-func (s *BookingServicesImpl) GetDoctorName(doctorId uint) (string, error) {
-	doctor, err := s.DoctorRepository.GetDoctorById(doctorId)
+func (s *BookingServicesImpl) GetDoctorName(ctx context.Context, doctorId uint) (string, error) {
+	ctx, span := tracing.Start(ctx, "BookingService.GetDoctorName")
+	defer span.End()
+
+	doctor, err := s.DoctorRepository.GetDoctorById(ctx, doctorId)
 	if err != nil {
 		return "", err
 	}
 
-	user, err := s.UserRepository.GetUserById(doctor.UserId)
+	user, err := s.UserRepository.GetUserById(ctx, doctor.UserId)
 	if err != nil {
 		return "", err
 	}
 	return user.Name, nil
 }
+
+// bookingAmount returns the price and discount fixed when the booking was
+// made. Bookings created before prices were stored fall back to the current
+// service price.
+func bookingAmount(booking *model.Booking) (int, int) {
+	if booking.Price == 0 && booking.FinalPrice == 0 {
+		return booking.Service.Price, 0
+	}
+	return booking.Price, booking.DiscountAmount
+}
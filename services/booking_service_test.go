@@ -0,0 +1,40 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"testing"
+	"time"
+)
+
+func TestCheckStatusChange(t *testing.T) {
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+	before, after := start.Add(-time.Hour), start.Add(time.Minute)
+
+	tests := []struct {
+		from, to string
+		now      time.Time
+		wantErr  bool
+	}{
+		{"pending", "confirmed", before, false},
+		{"pending", "cancelled", before, false},
+		{"confirmed", "completed", after, false},
+		{"confirmed", "no_show", after, false},
+		{"confirmed", "completed", before, true},
+		{"confirmed", "no_show", before, true},
+		{"pending", "completed", after, true},
+		{"cancelled", "completed", after, true},
+		{"cancelled", "confirmed", before, true},
+		{"completed", "confirmed", after, true},
+		{"no_show", "completed", after, true},
+		{"confirmed", "pending", before, true},
+		{"confirmed", "done", after, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			booking := &model.Booking{Status: tt.from, StartAt: start}
+			if err := checkStatusChange(booking, tt.to, tt.now); (err != nil) != tt.wantErr {
+				t.Errorf("checkStatusChange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
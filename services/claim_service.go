@@ -0,0 +1,223 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var bpjsNumberPattern = regexp.MustCompile(`^\d{13}$`)
+
+type ClaimService interface {
+	ValidatePayer(ctx context.Context, booking *model.Booking) error
+	CreateClaimForBooking(ctx context.Context, bookingID uint, userID uint) (*model.InsuranceClaim, error)
+	GetClaims(ctx context.Context, status, payerType string, clinicID uint, limit, offset int) ([]model.InsuranceClaim, *utils.Paginator, error)
+	UpdateClaimStatus(ctx context.Context, claimID uint, request model.ClaimStatusRequest, userID uint, clinicID uint) (*model.InsuranceClaim, error)
+	CreateBatch(ctx context.Context, request model.ClaimBatchRequest, userID uint) (*model.ClaimBatch, error)
+	GetBatchById(ctx context.Context, batchID uint) (*model.ClaimBatch, error)
+}
+
+type ClaimServiceImpl struct {
+	ClaimRepository    repository.ClaimRepository
+	CoverageRepository repository.CoverageRepository
+	BookingRepository  repository.BookingRepository
+}
+
+// ValidatePayer checks the payer details of a new booking and that the
+// booked service is covered by the payer. Self-pay bookings always pass.
+func (s *ClaimServiceImpl) ValidatePayer(ctx context.Context, booking *model.Booking) error {
+	ctx, span := tracing.Start(ctx, "ClaimService.ValidatePayer")
+	defer span.End()
+
+	switch booking.PayerType {
+	case "", "self_pay":
+		booking.PayerType = "self_pay"
+		booking.InsurerName = ""
+		booking.PolicyNumber = ""
+		return nil
+	case "bpjs":
+		if !bpjsNumberPattern.MatchString(booking.PolicyNumber) {
+			return errors.New("bpjs card number must be 13 digits")
+		}
+		booking.InsurerName = ""
+	case "insurer":
+		if booking.InsurerName == "" || booking.PolicyNumber == "" {
+			return errors.New("insurer name and policy number are required")
+		}
+	default:
+		return errors.New("payer type must be self_pay, bpjs or insurer")
+	}
+
+	if _, err := s.CoverageRepository.FindCoverage(ctx, booking.ServiceId, booking.PayerType, booking.InsurerName); err != nil {
+		return errors.New("service is not covered by the selected payer")
+	}
+	return nil
+}
+
+// CreateClaimForBooking opens a draft claim for a completed booking that is
+// paid by BPJS or an insurer. Self-pay bookings get no claim.
+func (s *ClaimServiceImpl) CreateClaimForBooking(ctx context.Context, bookingID uint, userID uint) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "ClaimService.CreateClaimForBooking")
+	defer span.End()
+
+	booking, err := s.BookingRepository.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if booking.PayerType == "" || booking.PayerType == "self_pay" {
+		return nil, nil
+	}
+
+	if claim, err := s.ClaimRepository.GetClaimByBookingId(ctx, booking.ID); err == nil {
+		return claim, nil
+	}
+
+	coverage, err := s.CoverageRepository.FindCoverage(ctx, booking.ServiceId, booking.PayerType, booking.InsurerName)
+	if err != nil {
+		return nil, errors.New("service is not covered by the selected payer")
+	}
+
+	price, discount := bookingAmount(booking)
+	billed := price - discount
+	covered := billed * coverage.CoveragePercent / 100
+	if coverage.MaxAmount > 0 && covered > coverage.MaxAmount {
+		covered = coverage.MaxAmount
+	}
+
+	claim := model.InsuranceClaim{
+		BookingId:     booking.ID,
+		UserId:        booking.UserId,
+		ServiceId:     booking.ServiceId,
+		PayerType:     booking.PayerType,
+		InsurerName:   booking.InsurerName,
+		PolicyNumber:  booking.PolicyNumber,
+		BilledAmount:  billed,
+		CoveredAmount: covered,
+		Status:        "draft",
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	}
+
+	if err := s.ClaimRepository.CreateClaim(ctx, &claim); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (s *ClaimServiceImpl) GetClaims(ctx context.Context, status, payerType string, clinicID uint, limit, offset int) ([]model.InsuranceClaim, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "ClaimService.GetClaims")
+	defer span.End()
+
+	claims, totalRows, err := s.ClaimRepository.GetClaims(ctx, status, payerType, clinicID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return claims, pagination, nil
+}
+
+// UpdateClaimStatus moves a claim along draft -> submitted -> approved or
+// rejected. A rejected claim can be put back to draft to be corrected and
+// submitted again.
+func (s *ClaimServiceImpl) UpdateClaimStatus(ctx context.Context, claimID uint, request model.ClaimStatusRequest, userID uint, clinicID uint) (*model.InsuranceClaim, error) {
+	ctx, span := tracing.Start(ctx, "ClaimService.UpdateClaimStatus")
+	defer span.End()
+
+	claim, err := s.ClaimRepository.GetClaimById(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingClinicById(ctx, s.BookingRepository, claim.BookingId, clinicID); err != nil {
+		return nil, err
+	}
+
+	allowed := map[string][]string{
+		"draft":     {"submitted"},
+		"submitted": {"approved", "rejected"},
+		"rejected":  {"draft"},
+	}
+
+	valid := false
+	for _, next := range allowed[claim.Status] {
+		if next == request.Status {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("cannot change claim status from %s to %s", claim.Status, request.Status)
+	}
+
+	now := time.Now()
+	switch request.Status {
+	case "submitted":
+		claim.SubmittedAt = &now
+	case "approved":
+		claim.DecidedAt = &now
+	case "rejected":
+		if request.RejectionReason == "" {
+			return nil, errors.New("rejection reason is required")
+		}
+		claim.DecidedAt = &now
+		claim.RejectionReason = request.RejectionReason
+	case "draft":
+		claim.BatchId = nil
+		claim.SubmittedAt = nil
+		claim.DecidedAt = nil
+	}
+
+	claim.Status = request.Status
+	claim.UpdatedBy = userID
+	if err := s.ClaimRepository.UpdateClaim(ctx, claim); err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+func (s *ClaimServiceImpl) CreateBatch(ctx context.Context, request model.ClaimBatchRequest, userID uint) (*model.ClaimBatch, error) {
+	ctx, span := tracing.Start(ctx, "ClaimService.CreateBatch")
+	defer span.End()
+
+	if request.PayerType != "bpjs" && request.PayerType != "insurer" {
+		return nil, errors.New("payer type must be bpjs or insurer")
+	}
+
+	batch := model.ClaimBatch{
+		BatchNumber: fmt.Sprintf("BATCH-%s-%s", request.PayerType, time.Now().Format("20060102150405")),
+		PayerType:   request.PayerType,
+		InsurerName: request.InsurerName,
+		CreatedBy:   userID,
+	}
+
+	if err := s.ClaimRepository.CreateBatch(ctx, &batch, request.PayerType, request.InsurerName); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("no draft claims to submit")
+		}
+		return nil, err
+	}
+
+	return s.ClaimRepository.GetBatchById(ctx, batch.ID)
+}
+
+func (s *ClaimServiceImpl) GetBatchById(ctx context.Context, batchID uint) (*model.ClaimBatch, error) {
+	ctx, span := tracing.Start(ctx, "ClaimService.GetBatchById")
+	defer span.End()
+
+	batch, err := s.ClaimRepository.GetBatchById(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"context"
+	"errors"
+)
+
+// ErrOtherClinic is returned when an admin limited to one clinic reaches for
+// a record of another.
+var ErrOtherClinic = errors.New("you can only access your own clinic")
+
+// checkBookingClinic fails when clinicID is set and the booking belongs to
+// another clinic. Services take clinicID as the clinic of the caller when
+// that is a branch admin, and 0 for everyone else.
+func checkBookingClinic(booking *model.Booking, clinicID uint) error {
+	if clinicID != 0 && booking.ClinicId != clinicID {
+		return ErrOtherClinic
+	}
+	return nil
+}
+
+// checkBookingClinicById is checkBookingClinic for records that only carry
+// the ID of their booking.
+func checkBookingClinicById(ctx context.Context, bookings repository.BookingRepository, bookingID uint, clinicID uint) error {
+	if clinicID == 0 {
+		return nil
+	}
+	booking, err := bookings.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	return checkBookingClinic(booking, clinicID)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+type ClinicService interface {
+	CreateClinic(ctx context.Context, request model.ClinicRequest, userID uint) (*model.Clinic, error)
+	GetAllClinics(ctx context.Context, activeOnly bool, limit, offset int) ([]model.Clinic, *utils.Paginator, error)
+	GetClinicById(ctx context.Context, id uint) (*model.Clinic, error)
+	UpdateClinic(ctx context.Context, id uint, request model.ClinicRequest, userID uint) (*model.Clinic, error)
+	DeleteClinic(ctx context.Context, id uint, userID uint) error
+	AddDoctor(ctx context.Context, clinicID uint, doctorID uint) error
+	RemoveDoctor(ctx context.Context, clinicID uint, doctorID uint) error
+	AssignAdmin(ctx context.Context, clinicID uint, userID uint) error
+	GetLocation(ctx context.Context, clinicID uint) (*time.Location, error)
+	ResolveLocation(ctx context.Context, clinicID uint, doctorID uint) (*time.Location, error)
+}
+
+type ClinicServiceImpl struct {
+	ClinicRepository repository.ClinicRepository
+	DoctorRepository repository.DoctorRepository
+	UserRepository   repository.UserRepository
+}
+
+func (s *ClinicServiceImpl) CreateClinic(ctx context.Context, request model.ClinicRequest, userID uint) (*model.Clinic, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.CreateClinic")
+	defer span.End()
+
+	clinic := model.Clinic{
+		Name:         strings.TrimSpace(request.Name),
+		Address:      request.Address,
+		Phone:        request.Phone,
+		Timezone:     request.Timezone,
+		IsActive:     true,
+		OpeningHours: request.OpeningHours,
+		CreatedBy:    userID,
+		UpdatedBy:    userID,
+	}
+	if clinic.Timezone == "" {
+		clinic.Timezone = utils.DefaultTimezone
+	}
+	if err := validateClinic(&clinic); err != nil {
+		return nil, err
+	}
+
+	if err := s.ClinicRepository.CreateClinic(ctx, &clinic); err != nil {
+		return nil, err
+	}
+	return &clinic, nil
+}
+
+func (s *ClinicServiceImpl) GetAllClinics(ctx context.Context, activeOnly bool, limit, offset int) ([]model.Clinic, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.GetAllClinics")
+	defer span.End()
+
+	clinics, totalRows, err := s.ClinicRepository.GetAllClinics(ctx, activeOnly, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return clinics, pagination, nil
+}
+
+func (s *ClinicServiceImpl) GetClinicById(ctx context.Context, id uint) (*model.Clinic, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.GetClinicById")
+	defer span.End()
+
+	clinic, err := s.ClinicRepository.GetClinicById(ctx, id)
+	if err != nil {
+		return nil, errors.New("clinic not found")
+	}
+	return clinic, nil
+}
+
+func (s *ClinicServiceImpl) UpdateClinic(ctx context.Context, id uint, request model.ClinicRequest, userID uint) (*model.Clinic, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.UpdateClinic")
+	defer span.End()
+
+	clinic, err := s.ClinicRepository.GetClinicById(ctx, id)
+	if err != nil {
+		return nil, errors.New("clinic not found")
+	}
+
+	clinic.Name = strings.TrimSpace(request.Name)
+	clinic.Address = request.Address
+	clinic.Phone = request.Phone
+	if request.Timezone != "" {
+		clinic.Timezone = request.Timezone
+	}
+	if request.IsActive != nil {
+		clinic.IsActive = *request.IsActive
+	}
+	clinic.OpeningHours = request.OpeningHours
+	clinic.UpdatedBy = userID
+	if err := validateClinic(clinic); err != nil {
+		return nil, err
+	}
+
+	if err := s.ClinicRepository.UpdateClinic(ctx, clinic); err != nil {
+		return nil, err
+	}
+	return clinic, nil
+}
+
+func (s *ClinicServiceImpl) DeleteClinic(ctx context.Context, id uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "ClinicService.DeleteClinic")
+	defer span.End()
+
+	if _, err := s.ClinicRepository.GetClinicById(ctx, id); err != nil {
+		return errors.New("clinic not found")
+	}
+	return s.ClinicRepository.DeleteClinic(ctx, id, userID)
+}
+
+func (s *ClinicServiceImpl) AddDoctor(ctx context.Context, clinicID uint, doctorID uint) error {
+	ctx, span := tracing.Start(ctx, "ClinicService.AddDoctor")
+	defer span.End()
+
+	if _, err := s.ClinicRepository.GetClinicById(ctx, clinicID); err != nil {
+		return errors.New("clinic not found")
+	}
+	if _, err := s.DoctorRepository.GetDoctorById(ctx, doctorID); err != nil {
+		return errors.New("doctor not found")
+	}
+
+	hasDoctor, err := s.ClinicRepository.HasDoctor(ctx, clinicID, doctorID)
+	if err != nil {
+		return err
+	}
+	if hasDoctor {
+		return errors.New("doctor already practises at this clinic")
+	}
+	return s.ClinicRepository.AddDoctor(ctx, clinicID, doctorID)
+}
+
+func (s *ClinicServiceImpl) RemoveDoctor(ctx context.Context, clinicID uint, doctorID uint) error {
+	ctx, span := tracing.Start(ctx, "ClinicService.RemoveDoctor")
+	defer span.End()
+
+	hasDoctor, err := s.ClinicRepository.HasDoctor(ctx, clinicID, doctorID)
+	if err != nil {
+		return err
+	}
+	if !hasDoctor {
+		return errors.New("doctor does not practise at this clinic")
+	}
+	return s.ClinicRepository.RemoveDoctor(ctx, clinicID, doctorID)
+}
+
+// AssignAdmin scopes an admin account to a single clinic.
+func (s *ClinicServiceImpl) AssignAdmin(ctx context.Context, clinicID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "ClinicService.AssignAdmin")
+	defer span.End()
+
+	if _, err := s.ClinicRepository.GetClinicById(ctx, clinicID); err != nil {
+		return errors.New("clinic not found")
+	}
+	user, err := s.UserRepository.GetUserById(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.Role != "admin" {
+		return errors.New("only admins can be assigned to a clinic")
+	}
+	return s.ClinicRepository.AssignAdmin(ctx, clinicID, userID)
+}
+
+// GetLocation returns the timezone of a clinic.
+func (s *ClinicServiceImpl) GetLocation(ctx context.Context, clinicID uint) (*time.Location, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.GetLocation")
+	defer span.End()
+
+	clinic, err := s.ClinicRepository.GetClinicById(ctx, clinicID)
+	if err != nil {
+		return nil, errors.New("clinic not found")
+	}
+	return time.LoadLocation(clinic.Timezone)
+}
+
+// ResolveLocation returns the timezone wall-clock times of a booking are read
+// in: the given clinic's, or the doctor's clinic when they only practise at one.
+func (s *ClinicServiceImpl) ResolveLocation(ctx context.Context, clinicID uint, doctorID uint) (*time.Location, error) {
+	ctx, span := tracing.Start(ctx, "ClinicService.ResolveLocation")
+	defer span.End()
+
+	if clinicID != 0 {
+		return s.GetLocation(ctx, clinicID)
+	}
+
+	clinics, err := s.ClinicRepository.GetClinicsByDoctorId(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+	switch len(clinics) {
+	case 0:
+		return nil, errors.New("doctor does not practise at any clinic")
+	case 1:
+		return time.LoadLocation(clinics[0].Timezone)
+	default:
+		return nil, errors.New("clinic_id is required for a doctor practising at several clinics")
+	}
+}
+
+func validateClinic(clinic *model.Clinic) error {
+	if clinic.Name == "" {
+		return errors.New("clinic name is required")
+	}
+	if _, err := time.LoadLocation(clinic.Timezone); err != nil {
+		return errors.New("invalid timezone")
+	}
+
+	for i := range clinic.OpeningHours {
+		hour := &clinic.OpeningHours[i]
+		if hour.Weekday < 0 || hour.Weekday > 6 {
+			return errors.New("weekday must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		opensAt, err := time.Parse("15:04", hour.OpensAt)
+		if err != nil {
+			return errors.New("invalid opens_at format")
+		}
+		closesAt, err := time.Parse("15:04", hour.ClosesAt)
+		if err != nil {
+			return errors.New("invalid closes_at format")
+		}
+		if !opensAt.Before(closesAt) {
+			return errors.New("opens_at must be before closes_at")
+		}
+		hour.OpensAt, hour.ClosesAt = opensAt.Format("15:04"), closesAt.Format("15:04")
+	}
+	return nil
+}
+
+// checkWithinOpeningHours fails when the period falls outside the clinic's
+// opening hours on that day, in the clinic's own timezone. Clinics without
+// opening hours are treated as always open.
+func checkWithinOpeningHours(clinic *model.Clinic, start, end time.Time) error {
+	if len(clinic.OpeningHours) == 0 {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(clinic.Timezone)
+	if err != nil {
+		return err
+	}
+	localStart, localEnd := start.In(loc), end.In(loc)
+	from, to := localStart.Format("15:04"), localEnd.Format("15:04")
+
+	for _, hour := range clinic.OpeningHours {
+		if time.Weekday(hour.Weekday) == localStart.Weekday() && from >= hour.OpensAt && to <= hour.ClosesAt && localStart.YearDay() == localEnd.YearDay() {
+			return nil
+		}
+	}
+	return errors.New("schedule is outside the clinic opening hours")
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"context"
+	"errors"
+)
+
+type CoverageService interface {
+	CreateCoverage(ctx context.Context, serviceID uint, request model.ServiceCoverageRequest, userID uint) (*model.ServiceCoverage, error)
+	GetCoveragesByServiceId(ctx context.Context, serviceID uint) ([]model.ServiceCoverage, error)
+	DeleteCoverage(ctx context.Context, coverageID uint, userID uint) error
+}
+
+type CoverageServiceImpl struct {
+	CoverageRepository repository.CoverageRepository
+	ServiceRepository  repository.ServiceRepository
+}
+
+func (s *CoverageServiceImpl) CreateCoverage(ctx context.Context, serviceID uint, request model.ServiceCoverageRequest, userID uint) (*model.ServiceCoverage, error) {
+	ctx, span := tracing.Start(ctx, "CoverageService.CreateCoverage")
+	defer span.End()
+
+	if _, err := s.ServiceRepository.GetServiceById(ctx, serviceID); err != nil {
+		return nil, errors.New("service not found")
+	}
+
+	if request.PayerType != "bpjs" && request.PayerType != "insurer" {
+		return nil, errors.New("payer type must be bpjs or insurer")
+	}
+
+	if request.PayerType == "bpjs" && request.InsurerName != "" {
+		return nil, errors.New("insurer name is not used for bpjs coverage")
+	}
+
+	if request.CoveragePercent <= 0 || request.CoveragePercent > 100 {
+		return nil, errors.New("coverage percent must be between 1 and 100")
+	}
+
+	if request.MaxAmount < 0 {
+		return nil, errors.New("max amount cannot be negative")
+	}
+
+	coverage := model.ServiceCoverage{
+		ServiceId:       serviceID,
+		PayerType:       request.PayerType,
+		InsurerName:     request.InsurerName,
+		CoveragePercent: request.CoveragePercent,
+		MaxAmount:       request.MaxAmount,
+		CreatedBy:       userID,
+		UpdatedBy:       userID,
+	}
+
+	if err := s.CoverageRepository.CreateCoverage(ctx, &coverage); err != nil {
+		return nil, err
+	}
+	return &coverage, nil
+}
+
+func (s *CoverageServiceImpl) GetCoveragesByServiceId(ctx context.Context, serviceID uint) ([]model.ServiceCoverage, error) {
+	ctx, span := tracing.Start(ctx, "CoverageService.GetCoveragesByServiceId")
+	defer span.End()
+
+	coverages, err := s.CoverageRepository.GetCoveragesByServiceId(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return coverages, nil
+}
+
+func (s *CoverageServiceImpl) DeleteCoverage(ctx context.Context, coverageID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "CoverageService.DeleteCoverage")
+	defer span.End()
+
+	if err := s.CoverageRepository.DeleteCoverage(ctx, coverageID, userID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,50 +3,63 @@ package services
 import (
 	"booking-klinik/model"
 	"booking-klinik/repository"
+	"booking-klinik/tracing"
 	"booking-klinik/utils"
+	"context"
 	"errors"
-	"fmt"
+	"time"
 )
 
 type DoctorServices interface {
-	CreateDoctor(doctor *model.Doctor) (*model.Doctor, error)
-	GetAllDoctors(limit, offset int) ([]model.Doctor, *utils.Paginator, error)
-	GetDoctorById(id uint) (*model.Doctor, error)
-	UpdateDoctor(doctorID uint, doctor model.Doctor) (*model.Doctor, error)
-	DeleteDoctor(doctorID uint, userID uint) error
+	CreateDoctor(ctx context.Context, doctor *model.Doctor) (*model.Doctor, error)
+	GetAllDoctors(ctx context.Context, clinicID uint, limit, offset int) ([]model.Doctor, *utils.Paginator, error)
+	GetDoctorById(ctx context.Context, id uint) (*model.Doctor, error)
+	UpdateDoctor(ctx context.Context, doctorID uint, doctor model.Doctor) (*model.Doctor, error)
+	DeleteDoctor(ctx context.Context, doctorID uint, userID uint) error
+	AddService(ctx context.Context, doctorID uint, serviceID uint) error
+	RemoveService(ctx context.Context, doctorID uint, serviceID uint) error
+	GetServicesByDoctorId(ctx context.Context, doctorID uint) ([]model.Service, error)
+	GetDoctorDirectory(ctx context.Context, specialization string, serviceID uint, clinicID uint, limit, offset int) ([]model.Doctor, map[uint]time.Time, *utils.Paginator, error)
 }
 
 type DoctorServicesImpl struct {
-	DoctorRepository repository.DoctorRepository
-	UserRepository   repository.UserRepository
-	BookingService   repository.BookingRepository
+	DoctorRepository         repository.DoctorRepository
+	UserRepository           repository.UserRepository
+	BookingService           repository.BookingRepository
+	ServiceRepository        repository.ServiceRepository
+	DoctorScheduleRepository repository.DoctorScheduleRepository
 }
 
-func (s *DoctorServicesImpl) CreateDoctor(doctor *model.Doctor) (*model.Doctor, error) {
-	user, err := s.UserRepository.GetUserById(doctor.UserId)
+func (s *DoctorServicesImpl) CreateDoctor(ctx context.Context, doctor *model.Doctor) (*model.Doctor, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.CreateDoctor")
+	defer span.End()
+
+	user, err := s.UserRepository.GetUserById(ctx, doctor.UserId)
 	if err != nil || user == nil {
 		return nil, errors.New("user not found")
 	}
 
-	if doctor.Specialization == "" {
-		return nil, errors.New("specialization is required")
-	}
-
 	if user.Role != "doctor" {
 		return nil, errors.New("user is not a doctor")
 	}
 
-	fmt.Println("UserRepository: ", s.UserRepository)
-	if err := s.DoctorRepository.CreateDoctor(doctor); err != nil {
+	if err := validateDoctorProfile(doctor); err != nil {
+		return nil, err
+	}
+
+	if err := s.DoctorRepository.CreateDoctor(ctx, doctor); err != nil {
 		return nil, err
 	}
 
 	return doctor, nil
 }
 
-func (s *DoctorServicesImpl) GetAllDoctors(limit, offset int) ([]model.Doctor, *utils.Paginator, error) {
+func (s *DoctorServicesImpl) GetAllDoctors(ctx context.Context, clinicID uint, limit, offset int) ([]model.Doctor, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.GetAllDoctors")
+	defer span.End()
+
 	var totalRows int64
-	doctors, totalRows, err := s.DoctorRepository.GetAllDoctors(limit, offset)
+	doctors, totalRows, err := s.DoctorRepository.GetAllDoctors(ctx, clinicID, limit, offset)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -57,17 +70,26 @@ func (s *DoctorServicesImpl) GetAllDoctors(limit, offset int) ([]model.Doctor, *
 	return doctors, pagination, nil
 }
 
-func (s *DoctorServicesImpl) GetDoctorById(id uint) (*model.Doctor, error) {
-	doctor, err := s.DoctorRepository.GetDoctorById(id)
+func (s *DoctorServicesImpl) GetDoctorById(ctx context.Context, id uint) (*model.Doctor, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.GetDoctorById")
+	defer span.End()
+
+	doctor, err := s.DoctorRepository.GetDoctorById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return doctor, nil
 }
 
-func (s *DoctorServicesImpl) UpdateDoctor(doctorID uint, doctor model.Doctor) (*model.Doctor, error) {
+func (s *DoctorServicesImpl) UpdateDoctor(ctx context.Context, doctorID uint, doctor model.Doctor) (*model.Doctor, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.UpdateDoctor")
+	defer span.End()
+
+	if err := validateDoctorProfile(&doctor); err != nil {
+		return nil, err
+	}
 
-	updatedDoctor, err := s.DoctorRepository.UpdateDoctor(doctorID, doctor)
+	updatedDoctor, err := s.DoctorRepository.UpdateDoctor(ctx, doctorID, doctor)
 	if err != nil {
 		return nil, err
 	}
@@ -75,11 +97,116 @@ func (s *DoctorServicesImpl) UpdateDoctor(doctorID uint, doctor model.Doctor) (*
 	return updatedDoctor, nil
 }
 
-func (s *DoctorServicesImpl) DeleteDoctor(doctorID uint, userID uint) error {
+func (s *DoctorServicesImpl) DeleteDoctor(ctx context.Context, doctorID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "DoctorService.DeleteDoctor")
+	defer span.End()
 
-	err := s.DoctorRepository.DeleteDoctor(doctorID, userID)
+	err := s.DoctorRepository.DeleteDoctor(ctx, doctorID, userID)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func (s *DoctorServicesImpl) AddService(ctx context.Context, doctorID uint, serviceID uint) error {
+	ctx, span := tracing.Start(ctx, "DoctorService.AddService")
+	defer span.End()
+
+	if _, err := s.DoctorRepository.GetDoctorById(ctx, doctorID); err != nil {
+		return errors.New("doctor not found")
+	}
+
+	service, err := s.ServiceRepository.GetServiceById(ctx, serviceID)
+	if err != nil || !service.IsActive {
+		return errors.New("service is inactive or not found")
+	}
+
+	hasService, err := s.DoctorRepository.HasService(ctx, doctorID, serviceID)
+	if err != nil {
+		return err
+	}
+	if hasService {
+		return errors.New("doctor already provides this service")
+	}
+
+	return s.DoctorRepository.AddService(ctx, doctorID, serviceID)
+}
+
+func (s *DoctorServicesImpl) RemoveService(ctx context.Context, doctorID uint, serviceID uint) error {
+	ctx, span := tracing.Start(ctx, "DoctorService.RemoveService")
+	defer span.End()
+
+	hasService, err := s.DoctorRepository.HasService(ctx, doctorID, serviceID)
+	if err != nil {
+		return err
+	}
+	if !hasService {
+		return errors.New("doctor does not provide this service")
+	}
+
+	return s.DoctorRepository.RemoveService(ctx, doctorID, serviceID)
+}
+
+func (s *DoctorServicesImpl) GetServicesByDoctorId(ctx context.Context, doctorID uint) ([]model.Service, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.GetServicesByDoctorId")
+	defer span.End()
+
+	if _, err := s.DoctorRepository.GetDoctorById(ctx, doctorID); err != nil {
+		return nil, errors.New("doctor not found")
+	}
+
+	services, err := s.DoctorRepository.GetServicesByDoctorId(ctx, doctorID)
+	if err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// GetDoctorDirectory lists doctors for the public directory together with the
+// next date each of them has a schedule, keyed by doctor ID.
+func (s *DoctorServicesImpl) GetDoctorDirectory(ctx context.Context, specialization string, serviceID uint, clinicID uint, limit, offset int) ([]model.Doctor, map[uint]time.Time, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "DoctorService.GetDoctorDirectory")
+	defer span.End()
+
+	doctors, totalRows, err := s.DoctorRepository.GetDoctorDirectory(ctx, specialization, serviceID, clinicID, limit, offset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	nextDates := map[uint]time.Time{}
+	if len(doctors) > 0 {
+		doctorIds := make([]uint, 0, len(doctors))
+		for _, doctor := range doctors {
+			doctorIds = append(doctorIds, doctor.ID)
+		}
+		nextDates, err = s.DoctorScheduleRepository.GetNextScheduleDatesByDoctor(ctx, doctorIds, serviceID, clinicID, startOfToday())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return doctors, nextDates, pagination, nil
+}
+
+func validateDoctorProfile(doctor *model.Doctor) error {
+	if doctor.Specialization == "" {
+		return errors.New("specialization is required")
+	}
+	if doctor.PracticeSince != 0 && (doctor.PracticeSince < 1900 || doctor.PracticeSince > time.Now().Year()) {
+		return errors.New("practice_since must be a valid year")
+	}
+	if doctor.STRExpiresAt != nil && doctor.STRNumber == "" {
+		return errors.New("str_number is required when str_expires_at is set")
+	}
+	if doctor.SIPExpiresAt != nil && doctor.SIPNumber == "" {
+		return errors.New("sip_number is required when sip_expires_at is set")
+	}
+	return nil
+}
+
+func startOfToday() time.Time {
+	return utils.Today()
+}
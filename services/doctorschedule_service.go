@@ -3,26 +3,33 @@ package services
 import (
 	"booking-klinik/model"
 	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"context"
 	"errors"
 	"time"
 )
 
 type DoctorScheduleService interface {
-	CreateDoctorSchedule(doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error)
-	GetDoctorSchedulesByDoctorId(doctorId uint) ([]model.DoctorSchedule, error)
-	GetAllDoctorSchedules(limit, offset int) ([]model.DoctorSchedule, error)
-	GetDoctorScheduleById(scheduleId uint) (*model.DoctorSchedule, error)
-	UpdateDoctorSchedule(scheduleID uint, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error)
-	DeleteDoctorSchedule(scheduleID uint, userID uint) error
+	CreateDoctorSchedule(ctx context.Context, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error)
+	GetDoctorSchedulesByDoctorId(ctx context.Context, doctorId uint) ([]model.DoctorSchedule, error)
+	GetAllDoctorSchedules(ctx context.Context, clinicID uint, limit, offset int) ([]model.DoctorSchedule, error)
+	GetDoctorScheduleById(ctx context.Context, scheduleId uint) (*model.DoctorSchedule, error)
+	UpdateDoctorSchedule(ctx context.Context, scheduleID uint, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error)
+	DeleteDoctorSchedule(ctx context.Context, scheduleID uint, userID uint) error
 }
 
 type DoctorScheduleServiceImpl struct {
 	DoctorScheduleRepository repository.DoctorScheduleRepository
 	DoctorRepository         repository.DoctorRepository
 	ServiceRepository        repository.ServiceRepository
+	ResourceRepository       repository.ResourceRepository
+	ClinicRepository         repository.ClinicRepository
 }
 
-func (ds *DoctorScheduleServiceImpl) CreateDoctorSchedule(doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error) {
+func (ds *DoctorScheduleServiceImpl) CreateDoctorSchedule(ctx context.Context, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error) {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.CreateDoctorSchedule")
+	defer span.End()
+
 	if doctorSchedule.DoctorId == 0 || doctorSchedule.ServiceId == 0 || doctorSchedule.Date.IsZero() || doctorSchedule.StartTime.IsZero() || doctorSchedule.EndTime.IsZero() {
 		return nil, errors.New("invalid doctor schedule data")
 	}
@@ -31,39 +38,56 @@ func (ds *DoctorScheduleServiceImpl) CreateDoctorSchedule(doctorSchedule model.D
 		return nil, errors.New("date must be in the future")
 	}
 
-	if _, err := ds.DoctorRepository.GetDoctorById(doctorSchedule.DoctorId); err != nil {
+	if !doctorSchedule.StartTime.Before(doctorSchedule.EndTime) {
+		return nil, errors.New("start time must be before end time")
+	}
+
+	doctor, err := ds.DoctorRepository.GetDoctorById(ctx, doctorSchedule.DoctorId)
+	if err != nil {
 		return nil, errors.New("doctor not found")
 	}
 
-	if _, err := ds.ServiceRepository.GetServiceById(doctorSchedule.ServiceId); err != nil {
+	if err := checkLicenseValidOn(doctor, doctorSchedule.Date); err != nil {
+		return nil, err
+	}
+
+	service, err := ds.ServiceRepository.GetServiceById(ctx, doctorSchedule.ServiceId)
+	if err != nil {
 		return nil, errors.New("service not found")
 	}
 
-	existingSchedule, err := ds.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(doctorSchedule.DoctorId)
+	if err := ds.checkClinic(ctx, doctorSchedule, service); err != nil {
+		return nil, err
+	}
+
+	hasService, err := ds.DoctorRepository.HasService(ctx, doctorSchedule.DoctorId, doctorSchedule.ServiceId)
 	if err != nil {
-		return nil, errors.New("error getting doctor schedules")
+		return nil, err
+	}
+	if !hasService {
+		return nil, errors.New("doctor does not provide this service")
 	}
 
-	for _, existing := range existingSchedule {
-		if existing.Date.Equal(doctorSchedule.Date) && existing.ServiceId == doctorSchedule.ServiceId {
-			existingStart, existingEnd := existing.StartTime, existing.EndTime
-			newStart, newEnd := doctorSchedule.StartTime, doctorSchedule.EndTime
+	if err := ds.checkDoctorOverlap(ctx, doctorSchedule, 0); err != nil {
+		return nil, err
+	}
 
-			if (newStart.Before(existingEnd) && newEnd.After(existingStart)) || (existingStart.Before(newEnd) && existingEnd.After(newStart)) || (newStart.Equal(existingStart) && newEnd.Equal(existingEnd)) || (existingStart.Equal(newStart) || existingEnd.Equal(newEnd)) {
-				return nil, errors.New("doctor schedule already exists")
-			}
-		}
+	if err := ds.checkRoom(ctx, doctorSchedule, 0); err != nil {
+		return nil, err
 	}
 
-	if err := ds.DoctorScheduleRepository.CreateDoctorSchedule(&doctorSchedule); err != nil {
+	if err := ds.DoctorScheduleRepository.CreateDoctorSchedule(ctx, &doctorSchedule); err != nil {
 		return nil, err
 	}
 
 	return &doctorSchedule, nil
 }
 
-func (ds *DoctorScheduleServiceImpl) GetDoctorSchedulesByDoctorId(doctorId uint) ([]model.DoctorSchedule, error) {
-	schedules, err := ds.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(doctorId)
+func (ds *DoctorScheduleServiceImpl) GetDoctorSchedulesByDoctorId(ctx context.Context, doctorId uint) ([]model.DoctorSchedule, error) {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.GetDoctorSchedulesByDoctorId")
+	defer span.End()
+
+	schedules, err := ds.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(ctx, doctorId)
 	if err != nil {
 		return nil, err
 	}
@@ -71,33 +95,58 @@ func (ds *DoctorScheduleServiceImpl) GetDoctorSchedulesByDoctorId(doctorId uint)
 	return schedules, nil
 }
 
-func (ds *DoctorScheduleServiceImpl) UpdateDoctorSchedule(scheduleID uint, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error) {
-	existingSchedule, err := ds.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(doctorSchedule.DoctorId)
+func (ds *DoctorScheduleServiceImpl) UpdateDoctorSchedule(ctx context.Context, scheduleID uint, doctorSchedule model.DoctorSchedule) (*model.DoctorSchedule, error) {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.UpdateDoctorSchedule")
+	defer span.End()
+
+	currentSchedule, err := ds.DoctorScheduleRepository.GetDoctorSchedulesById(ctx, scheduleID)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("doctor schedule not found")
 	}
+	doctorSchedule.ID = scheduleID
+	doctorSchedule.DoctorId = currentSchedule.DoctorId
+	doctorSchedule.ServiceId = currentSchedule.ServiceId
+	doctorSchedule.ClinicId = currentSchedule.ClinicId
 
-	for _, existing := range existingSchedule {
-		if existing.Date == doctorSchedule.Date {
-			existingStart, existingEnd := existing.StartTime, existing.EndTime
-			newStart, newEnd := doctorSchedule.StartTime, doctorSchedule.EndTime
+	if !doctorSchedule.StartTime.Before(doctorSchedule.EndTime) {
+		return nil, errors.New("start time must be before end time")
+	}
 
-			if (newStart.Before(existingEnd) && newEnd.After(existingStart)) || (existingStart.Before(newEnd) && existingEnd.After(newStart)) {
-				return nil, errors.New("doctor schedule already exists")
-			}
+	doctor, err := ds.DoctorRepository.GetDoctorById(ctx, doctorSchedule.DoctorId)
+	if err != nil {
+		return nil, errors.New("doctor not found")
+	}
 
-		}
+	if err := checkLicenseValidOn(doctor, doctorSchedule.Date); err != nil {
+		return nil, err
+	}
+
+	if err := ds.checkDoctorOverlap(ctx, doctorSchedule, scheduleID); err != nil {
+		return nil, err
 	}
 
-	if err := ds.DoctorScheduleRepository.UpdateDoctorSchedule(&doctorSchedule); err != nil {
+	if doctorSchedule.RoomId == nil {
+		doctorSchedule.RoomId = currentSchedule.RoomId
+	}
+	if err := ds.checkClinic(ctx, doctorSchedule, nil); err != nil {
+		return nil, err
+	}
+	if err := ds.checkRoom(ctx, doctorSchedule, scheduleID); err != nil {
+		return nil, err
+	}
+
+	if err := ds.DoctorScheduleRepository.UpdateDoctorSchedule(ctx, &doctorSchedule); err != nil {
 		return nil, err
 	}
 
 	return &doctorSchedule, nil
 }
 
-func (ds *DoctorScheduleServiceImpl) DeleteDoctorSchedule(scheduleID uint, userID uint) error {
-	err := ds.DoctorScheduleRepository.DeleteDoctorSchedule(scheduleID, userID)
+func (ds *DoctorScheduleServiceImpl) DeleteDoctorSchedule(ctx context.Context, scheduleID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.DeleteDoctorSchedule")
+	defer span.End()
+
+	err := ds.DoctorScheduleRepository.DeleteDoctorSchedule(ctx, scheduleID, userID)
 	if err != nil {
 		return err
 	}
@@ -105,8 +154,11 @@ func (ds *DoctorScheduleServiceImpl) DeleteDoctorSchedule(scheduleID uint, userI
 	return nil
 }
 
-func (ds *DoctorScheduleServiceImpl) GetAllDoctorSchedules(limit, offset int) ([]model.DoctorSchedule, error) {
-	schedules, err := ds.DoctorScheduleRepository.GetAllDoctorSchedules(limit, offset)
+func (ds *DoctorScheduleServiceImpl) GetAllDoctorSchedules(ctx context.Context, clinicID uint, limit, offset int) ([]model.DoctorSchedule, error) {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.GetAllDoctorSchedules")
+	defer span.End()
+
+	schedules, err := ds.DoctorScheduleRepository.GetAllDoctorSchedules(ctx, clinicID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -114,11 +166,85 @@ func (ds *DoctorScheduleServiceImpl) GetAllDoctorSchedules(limit, offset int) ([
 	return schedules, nil
 }
 
-func (ds *DoctorScheduleServiceImpl) GetDoctorScheduleById(scheduleId uint) (*model.DoctorSchedule, error) {
-	schedule, err := ds.DoctorScheduleRepository.GetDoctorSchedulesById(scheduleId)
+func (ds *DoctorScheduleServiceImpl) GetDoctorScheduleById(ctx context.Context, scheduleId uint) (*model.DoctorSchedule, error) {
+	ctx, span := tracing.Start(ctx, "DoctorScheduleService.GetDoctorScheduleById")
+	defer span.End()
+
+	schedule, err := ds.DoctorScheduleRepository.GetDoctorSchedulesById(ctx, scheduleId)
 	if err != nil {
 		return nil, err
 	}
 
 	return schedule, nil
 }
+
+// checkDoctorOverlap makes sure the doctor has no other schedule at the same
+// time, whatever its service or clinic. excludeID skips the schedule being
+// updated.
+func (ds *DoctorScheduleServiceImpl) checkDoctorOverlap(ctx context.Context, doctorSchedule model.DoctorSchedule, excludeID uint) error {
+	schedules, err := ds.DoctorScheduleRepository.GetDoctorSchedulesByDoctorId(ctx, doctorSchedule.DoctorId)
+	if err != nil {
+		return errors.New("error getting doctor schedules")
+	}
+	for _, existing := range schedules {
+		if existing.ID != excludeID && doctorSchedule.StartTime.Before(existing.EndTime) && doctorSchedule.EndTime.After(existing.StartTime) {
+			return errors.New("doctor schedule already exists")
+		}
+	}
+	return nil
+}
+
+// checkRoom makes sure the schedule's room exists and is not used by another
+// schedule at the same time. excludeID skips the schedule being updated.
+func (ds *DoctorScheduleServiceImpl) checkRoom(ctx context.Context, doctorSchedule model.DoctorSchedule, excludeID uint) error {
+	if doctorSchedule.RoomId == nil {
+		return nil
+	}
+
+	room, err := ds.ResourceRepository.GetResourceById(ctx, *doctorSchedule.RoomId)
+	if err != nil || room.Type != "room" || !room.IsActive {
+		return errors.New("room is inactive or not found")
+	}
+	if room.ClinicId != doctorSchedule.ClinicId {
+		return errors.New("room belongs to another clinic")
+	}
+
+	schedules, err := ds.DoctorScheduleRepository.GetSchedulesByRoomAndDate(ctx, room.ID, doctorSchedule.Date)
+	if err != nil {
+		return err
+	}
+	for _, existing := range schedules {
+		if existing.ID != excludeID && doctorSchedule.StartTime.Before(existing.EndTime) && doctorSchedule.EndTime.After(existing.StartTime) {
+			return errors.New("room is already scheduled at this time")
+		}
+	}
+	return nil
+}
+
+// checkClinic makes sure the doctor practises at the schedule's clinic, the
+// service is offered there and the schedule fits its opening hours. service
+// may be nil when it has already been checked.
+func (ds *DoctorScheduleServiceImpl) checkClinic(ctx context.Context, doctorSchedule model.DoctorSchedule, service *model.Service) error {
+	if doctorSchedule.ClinicId == 0 {
+		return errors.New("clinic_id is required")
+	}
+
+	clinic, err := ds.ClinicRepository.GetClinicById(ctx, doctorSchedule.ClinicId)
+	if err != nil || !clinic.IsActive {
+		return errors.New("clinic is inactive or not found")
+	}
+
+	hasDoctor, err := ds.ClinicRepository.HasDoctor(ctx, clinic.ID, doctorSchedule.DoctorId)
+	if err != nil {
+		return err
+	}
+	if !hasDoctor {
+		return errors.New("doctor does not practise at this clinic")
+	}
+
+	if service != nil && service.ClinicId != nil && *service.ClinicId != clinic.ID {
+		return errors.New("service is not offered at this clinic")
+	}
+
+	return checkWithinOpeningHours(clinic, doctorSchedule.StartTime, doctorSchedule.EndTime)
+}
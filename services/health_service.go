@@ -0,0 +1,55 @@
+package services
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// readinessTimeout bounds the database checks of one readiness probe.
+const readinessTimeout = 2 * time.Second
+
+type HealthService interface {
+	CheckReadiness(ctx context.Context) (*model.Readiness, error)
+}
+
+// HealthServiceImpl checks the database connection itself rather than through
+// a repository, since it is not about any model.
+type HealthServiceImpl struct {
+	DB *gorm.DB
+}
+
+// CheckReadiness pings the database and compares its schema version with the
+// one this build expects. The readiness is filled in as far as the checks
+// got, also when an error is returned.
+func (s *HealthServiceImpl) CheckReadiness(ctx context.Context) (*model.Readiness, error) {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	readiness := &model.Readiness{Status: "not ready", Database: "unreachable"}
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return readiness, err
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return readiness, err
+	}
+	readiness.Database = "ok"
+
+	db := s.DB.WithContext(ctx)
+	if readiness.LatestVersion, err = config.LatestVersion(db.Dialector.Name()); err != nil {
+		return readiness, err
+	}
+	if readiness.SchemaVersion, err = config.SchemaVersion(db); err != nil {
+		return readiness, err
+	}
+	if err := config.CheckSchemaVersion(db); err != nil {
+		return readiness, err
+	}
+
+	readiness.Status = "ready"
+	return readiness, nil
+}
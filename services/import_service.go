@@ -0,0 +1,365 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ImportService interface {
+	ImportDoctors(ctx context.Context, r io.Reader, createdBy uint) (*model.ImportReport, error)
+	ImportSchedules(ctx context.Context, r io.Reader, createdBy uint) (*model.ImportReport, error)
+}
+
+// ImportServiceImpl creates doctors and schedules from CSV files through the
+// same services as the API, so rows are validated by the same rules. A
+// rejected row does not stop the rows after it.
+type ImportServiceImpl struct {
+	UserService           UserService
+	UserRepository        repository.UserRepository
+	DoctorService         DoctorServices
+	DoctorRepository      repository.DoctorRepository
+	DoctorScheduleService DoctorScheduleService
+	ServiceRepository     repository.ServiceRepository
+	ClinicService         ClinicService
+}
+
+var doctorColumns = csvColumns{
+	required: []string{"email", "name", "specialization"},
+	optional: []string{"password", "bio", "photo_url", "languages", "education", "practice_since", "str_number", "str_expires_at", "sip_number", "sip_expires_at", "clinic_id", "service_ids"},
+}
+
+var scheduleColumns = csvColumns{
+	required: []string{"doctor_email", "service_id", "clinic_id", "date", "start_time", "end_time"},
+	optional: []string{"room_id"},
+}
+
+// ImportDoctors creates a doctor profile for each row, with a doctor account
+// for the email unless one exists. Accounts created without a password get a
+// random one, returned in the row's note.
+func (s *ImportServiceImpl) ImportDoctors(ctx context.Context, r io.Reader, createdBy uint) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportService.ImportDoctors")
+	defer span.End()
+
+	return importRows(r, doctorColumns, func(row csvRow) (uint, string, error) {
+		doctor, err := parseDoctorRow(row)
+		if err != nil {
+			return 0, "", err
+		}
+		doctor.CreatedBy = createdBy
+		if err := validateDoctorProfile(doctor); err != nil {
+			return 0, "", err
+		}
+
+		clinicID, err := row.uint("clinic_id")
+		if err != nil {
+			return 0, "", err
+		}
+		if clinicID != 0 {
+			if _, err := s.ClinicService.GetClinicById(ctx, clinicID); err != nil {
+				return 0, "", errors.New("clinic not found")
+			}
+		}
+		serviceIDs, err := row.uintList("service_ids")
+		if err != nil {
+			return 0, "", err
+		}
+		for _, serviceID := range serviceIDs {
+			service, err := s.ServiceRepository.GetServiceById(ctx, serviceID)
+			if err != nil || !service.IsActive {
+				return 0, "", fmt.Errorf("service %d is inactive or not found", serviceID)
+			}
+		}
+
+		// The row is validated before the account is created, so rejected
+		// rows do not leave accounts behind
+		user, note, err := s.doctorAccount(ctx, row, createdBy)
+		if err != nil {
+			return 0, "", err
+		}
+		doctor.UserId = user.ID
+
+		if _, err := s.DoctorService.CreateDoctor(ctx, doctor); err != nil {
+			return 0, note, err
+		}
+		if clinicID != 0 {
+			if err := s.ClinicService.AddDoctor(ctx, clinicID, doctor.ID); err != nil {
+				return doctor.ID, note, err
+			}
+		}
+		for _, serviceID := range serviceIDs {
+			if err := s.DoctorService.AddService(ctx, doctor.ID, serviceID); err != nil {
+				return doctor.ID, note, err
+			}
+		}
+		return doctor.ID, note, nil
+	})
+}
+
+// ImportSchedules creates a schedule for each row. Dates and times are read in
+// the clinic's timezone, like schedules created through the API.
+func (s *ImportServiceImpl) ImportSchedules(ctx context.Context, r io.Reader, createdBy uint) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportService.ImportSchedules")
+	defer span.End()
+
+	return importRows(r, scheduleColumns, func(row csvRow) (uint, string, error) {
+		user, err := s.UserRepository.GetUserByEmail(ctx, row.get("doctor_email"))
+		if err != nil {
+			return 0, "", errors.New("doctor not found")
+		}
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, user.ID)
+		if err != nil {
+			return 0, "", errors.New("doctor not found")
+		}
+		serviceID, err := row.uint("service_id")
+		if err != nil {
+			return 0, "", err
+		}
+		clinicID, err := row.uint("clinic_id")
+		if err != nil {
+			return 0, "", err
+		}
+		loc, err := s.ClinicService.GetLocation(ctx, clinicID)
+		if err != nil {
+			return 0, "", err
+		}
+
+		startTime, err := utils.ParseDateTime(row.get("date"), row.get("start_time"), loc)
+		if err != nil {
+			return 0, "", errors.New("invalid start time format")
+		}
+		endTime, err := utils.ParseDateTime(row.get("date"), row.get("end_time"), loc)
+		if err != nil {
+			return 0, "", errors.New("invalid end time format")
+		}
+
+		schedule := model.DoctorSchedule{
+			DoctorId:  doctorID,
+			ServiceId: serviceID,
+			Date:      utils.DateIn(startTime, loc),
+			StartTime: startTime,
+			EndTime:   endTime,
+			ClinicId:  clinicID,
+			CreatedBy: createdBy,
+		}
+		roomID, err := row.uint("room_id")
+		if err != nil {
+			return 0, "", err
+		}
+		if roomID != 0 {
+			schedule.RoomId = &roomID
+		}
+
+		created, err := s.DoctorScheduleService.CreateDoctorSchedule(ctx, schedule)
+		if err != nil {
+			return 0, "", err
+		}
+		return created.ID, "", nil
+	})
+}
+
+// doctorAccount returns the doctor account for the row's email, registering
+// it when it does not exist yet.
+func (s *ImportServiceImpl) doctorAccount(ctx context.Context, row csvRow, createdBy uint) (*model.User, string, error) {
+	email := row.get("email")
+	if existing, err := s.UserRepository.GetUserByEmail(ctx, email); err == nil {
+		if existing.Role != "doctor" {
+			return nil, "", errors.New("user is not a doctor")
+		}
+		if _, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, existing.ID); err == nil {
+			return nil, "", errors.New("doctor already exists")
+		}
+		return existing, "", nil
+	}
+
+	password, note := row.get("password"), ""
+	if password == "" {
+		generated, err := utils.RandomPassword()
+		if err != nil {
+			return nil, "", err
+		}
+		password, note = generated, "temporary password: "+generated
+	}
+
+	user, err := s.UserService.RegisterUser(ctx, &model.User{Name: row.get("name"), Email: email, Password: password, Role: "doctor", CreatedBy: createdBy})
+	if err != nil {
+		return nil, "", err
+	}
+	return user, note, nil
+}
+
+func parseDoctorRow(row csvRow) (*model.Doctor, error) {
+	practiceSince, err := row.int("practice_since")
+	if err != nil {
+		return nil, err
+	}
+	strExpiresAt, err := row.date("str_expires_at")
+	if err != nil {
+		return nil, err
+	}
+	sipExpiresAt, err := row.date("sip_expires_at")
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Doctor{
+		Specialization: row.get("specialization"),
+		Bio:            row.get("bio"),
+		PhotoURL:       row.get("photo_url"),
+		Languages:      row.get("languages"),
+		Education:      row.get("education"),
+		PracticeSince:  practiceSince,
+		STRNumber:      row.get("str_number"),
+		STRExpiresAt:   strExpiresAt,
+		SIPNumber:      row.get("sip_number"),
+		SIPExpiresAt:   sipExpiresAt,
+	}, nil
+}
+
+type csvColumns struct {
+	required []string
+	optional []string
+}
+
+type csvRow struct {
+	columns map[string]int
+	values  []string
+}
+
+func (r csvRow) get(column string) string {
+	index, ok := r.columns[column]
+	if !ok || index >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[index])
+}
+
+func (r csvRow) int(column string) (int, error) {
+	value := r.get(column)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", column)
+	}
+	return n, nil
+}
+
+func (r csvRow) uint(column string) (uint, error) {
+	value := r.get(column)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an ID", column)
+	}
+	return uint(n), nil
+}
+
+// uintList reads IDs separated by semicolons.
+func (r csvRow) uintList(column string) ([]uint, error) {
+	var ids []uint
+	for _, value := range strings.Split(r.get(column), ";") {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be IDs separated by semicolons", column)
+		}
+		ids = append(ids, uint(n))
+	}
+	return ids, nil
+}
+
+func (r csvRow) date(column string) (*time.Time, error) {
+	value := r.get(column)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := utils.ParseDate(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a YYYY-MM-DD date", column)
+	}
+	return &date, nil
+}
+
+// importRows checks the header against the expected columns, then calls
+// importRow for every row and records the outcome in the report.
+func importRows(r io.Reader, expected csvColumns, importRow func(row csvRow) (uint, string, error)) (*model.ImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, column := range append(expected.required, expected.optional...) {
+		known[column] = true
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if !known[column] {
+			return nil, fmt.Errorf("unknown column %q", column)
+		}
+		columns[column] = i
+	}
+	for _, column := range expected.required {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("missing column %q", column)
+		}
+	}
+
+	report := &model.ImportReport{Accepted: []model.ImportRowResult{}, Rejected: []model.ImportRowResult{}}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Rejected = append(report.Rejected, model.ImportRowResult{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := csvRow{columns: columns, values: values}
+		missing := ""
+		for _, column := range expected.required {
+			if row.get(column) == "" {
+				missing = column
+				break
+			}
+		}
+		if missing != "" {
+			report.Rejected = append(report.Rejected, model.ImportRowResult{Row: line, Error: missing + " is required"})
+			continue
+		}
+
+		id, note, err := importRow(row)
+		if err != nil {
+			report.Rejected = append(report.Rejected, model.ImportRowResult{Row: line, ID: id, Note: note, Error: err.Error()})
+			continue
+		}
+		report.Accepted = append(report.Accepted, model.ImportRowResult{Row: line, ID: id, Note: note})
+	}
+	return report, nil
+}
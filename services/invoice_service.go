@@ -0,0 +1,351 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var invoiceItemTypes = map[string]bool{
+	"service":   true,
+	"drug":      true,
+	"procedure": true,
+	"other":     true,
+}
+
+type InvoiceService interface {
+	CreateInvoiceForBooking(ctx context.Context, bookingID uint, createdBy uint) (*model.Invoice, error)
+	GetInvoiceById(ctx context.Context, invoiceID uint, userID uint, userRole string, clinicID uint) (*model.Invoice, error)
+	GetInvoices(ctx context.Context, userID uint, userRole string, status string, clinicID uint, limit, offset int) ([]model.Invoice, *utils.Paginator, error)
+	AddInvoiceItem(ctx context.Context, invoiceID uint, item model.InvoiceItemRequest, userID uint, userRole string, clinicID uint) (*model.Invoice, error)
+	UpdateInvoice(ctx context.Context, invoiceID uint, request model.InvoiceUpdateRequest, userID uint, clinicID uint) (*model.Invoice, error)
+	SettleInvoice(ctx context.Context, bookingID uint, userID uint) error
+}
+
+type InvoiceServiceImpl struct {
+	InvoiceRepository repository.InvoiceRepository
+	BookingRepository repository.BookingRepository
+	DoctorRepository  repository.DoctorRepository
+	PaymentRepository repository.PaymentRepository
+	TaxPercent        float64
+}
+
+// CreateInvoiceForBooking bills the booked service once the booking is
+// completed. The invoice starts out paid when the booking's payments already
+// cover it. Calling it again for the same booking returns the existing invoice.
+func (s *InvoiceServiceImpl) CreateInvoiceForBooking(ctx context.Context, bookingID uint, createdBy uint) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.CreateInvoiceForBooking")
+	defer span.End()
+
+	if invoice, err := s.InvoiceRepository.GetInvoiceByBookingId(ctx, bookingID); err == nil {
+		return invoice, nil
+	}
+
+	booking, err := s.BookingRepository.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if booking.Status != "completed" {
+		return nil, errors.New("invoice can only be created for completed bookings")
+	}
+
+	price, discount := bookingAmount(booking)
+
+	invoice := model.Invoice{
+		BookingId:      booking.ID,
+		UserId:         booking.UserId,
+		DiscountAmount: discount,
+		TaxPercent:     s.TaxPercent,
+		Status:         "unpaid",
+		IssuedAt:       time.Now(),
+		CreatedBy:      createdBy,
+		UpdatedBy:      createdBy,
+		Items: []model.InvoiceItem{
+			{
+				ItemType:    "service",
+				Description: booking.Service.Name,
+				Quantity:    1,
+				UnitPrice:   price,
+				Amount:      price,
+				CreatedBy:   createdBy,
+			},
+		},
+	}
+	recalculateInvoice(&invoice)
+	if err := s.applyPayments(ctx, &invoice); err != nil {
+		return nil, err
+	}
+
+	if err := s.InvoiceRepository.CreateInvoice(ctx, &invoice); err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+func (s *InvoiceServiceImpl) GetInvoiceById(ctx context.Context, invoiceID uint, userID uint, userRole string, clinicID uint) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GetInvoiceById")
+	defer span.End()
+
+	invoice, err := s.InvoiceRepository.GetInvoiceById(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch userRole {
+	case "admin":
+		if err := checkBookingClinicById(ctx, s.BookingRepository, invoice.BookingId, clinicID); err != nil {
+			return nil, err
+		}
+		return invoice, nil
+	case "patient":
+		if invoice.UserId != userID {
+			return nil, errors.New("you can only access your own invoices")
+		}
+		return invoice, nil
+	}
+	return nil, errors.New("invalid user role")
+}
+
+func (s *InvoiceServiceImpl) GetInvoices(ctx context.Context, userID uint, userRole string, status string, clinicID uint, limit, offset int) ([]model.Invoice, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GetInvoices")
+	defer span.End()
+
+	var invoices []model.Invoice
+	var totalRows int64
+	var err error
+
+	switch userRole {
+	case "patient":
+		invoices, totalRows, err = s.InvoiceRepository.GetInvoicesByUserId(ctx, userID, limit, offset)
+	case "admin":
+		if status == "" {
+			status = "unpaid"
+		}
+		invoices, totalRows, err = s.InvoiceRepository.GetInvoicesByStatus(ctx, status, clinicID, limit, offset)
+	default:
+		return nil, nil, errors.New("invalid user role")
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return invoices, pagination, nil
+}
+
+// AddInvoiceItem adds a line to an unpaid invoice. Doctors may only add them
+// to invoices of their own bookings and branch admins to those of their clinic.
+func (s *InvoiceServiceImpl) AddInvoiceItem(ctx context.Context, invoiceID uint, request model.InvoiceItemRequest, userID uint, userRole string, clinicID uint) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.AddInvoiceItem")
+	defer span.End()
+
+	invoice, err := s.InvoiceRepository.GetInvoiceById(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	booking, err := s.BookingRepository.GetBookingById(ctx, invoice.BookingId)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	switch userRole {
+	case "doctor":
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if booking.DoctorId != doctorID {
+			return nil, errors.New("you can only add items to invoices of your own bookings")
+		}
+	case "admin":
+		if err := checkBookingClinic(booking, clinicID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("invalid user role")
+	}
+
+	if invoice.Status != "unpaid" {
+		return nil, errors.New("items can only be added to unpaid invoices")
+	}
+
+	if !invoiceItemTypes[request.ItemType] {
+		return nil, errors.New("item type must be service, drug, procedure or other")
+	}
+
+	if request.Description == "" {
+		return nil, errors.New("item description is required")
+	}
+
+	if request.Quantity <= 0 || request.UnitPrice < 0 {
+		return nil, errors.New("quantity must be greater than 0 and unit price cannot be negative")
+	}
+
+	item := model.InvoiceItem{
+		InvoiceId:   invoice.ID,
+		ItemType:    request.ItemType,
+		Description: request.Description,
+		Quantity:    request.Quantity,
+		UnitPrice:   request.UnitPrice,
+		Amount:      request.Quantity * request.UnitPrice,
+		CreatedBy:   userID,
+	}
+
+	if err := s.InvoiceRepository.AddInvoiceItem(ctx, &item); err != nil {
+		return nil, err
+	}
+
+	invoice.Items = append(invoice.Items, item)
+	invoice.UpdatedBy = userID
+	recalculateInvoice(invoice)
+
+	if err := s.InvoiceRepository.UpdateInvoice(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *InvoiceServiceImpl) UpdateInvoice(ctx context.Context, invoiceID uint, request model.InvoiceUpdateRequest, userID uint, clinicID uint) (*model.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.UpdateInvoice")
+	defer span.End()
+
+	invoice, err := s.InvoiceRepository.GetInvoiceById(ctx, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingClinicById(ctx, s.BookingRepository, invoice.BookingId, clinicID); err != nil {
+		return nil, err
+	}
+
+	if invoice.Status != "unpaid" {
+		return nil, errors.New("only unpaid invoices can be changed")
+	}
+
+	if request.DiscountAmount != nil {
+		if *request.DiscountAmount < 0 {
+			return nil, errors.New("discount amount cannot be negative")
+		}
+		invoice.DiscountAmount = *request.DiscountAmount
+	}
+
+	if request.TaxPercent != nil {
+		if *request.TaxPercent < 0 || *request.TaxPercent > 100 {
+			return nil, errors.New("tax percent must be between 0 and 100")
+		}
+		invoice.TaxPercent = *request.TaxPercent
+	}
+
+	recalculateInvoice(invoice)
+	if invoice.DiscountAmount > invoice.Subtotal {
+		return nil, errors.New("discount amount cannot exceed the subtotal")
+	}
+
+	switch request.Status {
+	case "", "unpaid":
+	case "paid":
+		now := time.Now()
+		invoice.Status = "paid"
+		invoice.PaidAt = &now
+	case "void":
+		invoice.Status = "void"
+	default:
+		return nil, errors.New("status must be unpaid, paid or void")
+	}
+
+	invoice.UpdatedBy = userID
+	if err := s.InvoiceRepository.UpdateInvoice(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// SettleInvoice marks the booking's invoice as paid once its payments cover
+// it. Bookings without an invoice yet are left alone; their invoice is
+// checked against the payments when it is created.
+func (s *InvoiceServiceImpl) SettleInvoice(ctx context.Context, bookingID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "InvoiceService.SettleInvoice")
+	defer span.End()
+
+	invoice, err := s.InvoiceRepository.GetInvoiceByBookingId(ctx, bookingID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if invoice.Status != "unpaid" {
+		return nil
+	}
+
+	if err := s.applyPayments(ctx, invoice); err != nil {
+		return err
+	}
+	if invoice.Status != "paid" {
+		return nil
+	}
+	invoice.UpdatedBy = userID
+	return s.InvoiceRepository.UpdateInvoice(ctx, invoice)
+}
+
+// applyPayments marks the invoice as paid, as of the last payment, when the
+// settled payments of its booking add up to its total.
+func (s *InvoiceServiceImpl) applyPayments(ctx context.Context, invoice *model.Invoice) error {
+	payments, err := s.PaymentRepository.GetPaymentsByBookingId(ctx, invoice.BookingId)
+	if err != nil {
+		return err
+	}
+
+	paid := 0
+	var paidAt *time.Time
+	for _, payment := range payments {
+		if payment.Status != "paid" {
+			continue
+		}
+		paid += payment.Amount
+		settledAt := payment.UpdatedAt
+		if payment.PaidAt != nil {
+			settledAt = *payment.PaidAt
+		}
+		if paidAt == nil || settledAt.After(*paidAt) {
+			paidAt = &settledAt
+		}
+	}
+
+	if paidAt != nil && paid >= invoice.Total {
+		invoice.Status = "paid"
+		invoice.PaidAt = paidAt
+	}
+	return nil
+}
+
+// recalculateInvoice derives the subtotal, tax and total from the line items.
+// Tax is charged on the amount after discount.
+func recalculateInvoice(invoice *model.Invoice) {
+	subtotal := 0
+	for _, item := range invoice.Items {
+		subtotal += item.Amount
+	}
+
+	taxable := subtotal - invoice.DiscountAmount
+	if taxable < 0 {
+		taxable = 0
+	}
+
+	invoice.Subtotal = subtotal
+	invoice.TaxAmount = int(math.Round(float64(taxable) * invoice.TaxPercent / 100))
+	invoice.Total = taxable + invoice.TaxAmount
+}
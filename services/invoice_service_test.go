@@ -0,0 +1,102 @@
+package services
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func newInvoiceService(f *testutil.Fixture) *InvoiceServiceImpl {
+	return &InvoiceServiceImpl{
+		InvoiceRepository: &repository.InvoiceRepositoryImpl{DB: f.DB},
+		BookingRepository: &repository.BookingRepositoryImpl{DB: f.DB},
+		DoctorRepository:  &repository.DoctorRepositoryImpl{DB: f.DB},
+		PaymentRepository: &repository.PaymentRepositoryImpl{DB: f.DB},
+	}
+}
+
+func TestCreateInvoiceForBookingPaidInAdvance(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newInvoiceService(f)
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(-time.Hour), "completed")
+	f.AddPayment(t, booking, booking.Price, "manual", "paid")
+
+	invoice, err := s.CreateInvoiceForBooking(context.Background(), booking.ID, f.Patient.ID)
+	if err != nil {
+		t.Fatalf("CreateInvoiceForBooking() error = %v", err)
+	}
+	if invoice.Status != "paid" || invoice.PaidAt == nil {
+		t.Errorf("invoice status = %q, paid at %v, want paid", invoice.Status, invoice.PaidAt)
+	}
+}
+
+func TestSettleInvoiceOnceCovered(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newInvoiceService(f)
+	s.TaxPercent = 10
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(-time.Hour), "completed")
+	invoice, err := s.CreateInvoiceForBooking(context.Background(), booking.ID, f.Patient.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The service price alone does not cover the tax
+	for _, tt := range []struct {
+		amount int
+		want   string
+	}{
+		{booking.Price, "unpaid"},
+		{invoice.Total - booking.Price, "paid"},
+	} {
+		f.AddPayment(t, booking, tt.amount, "manual", "paid")
+		if err := s.SettleInvoice(context.Background(), booking.ID, f.Patient.ID); err != nil {
+			t.Fatalf("SettleInvoice() error = %v", err)
+		}
+		var stored model.Invoice
+		f.DB.First(&stored, invoice.ID)
+		if stored.Status != tt.want {
+			t.Errorf("after paying %d, invoice status = %q, want %q", tt.amount, stored.Status, tt.want)
+		}
+	}
+}
+
+func TestAddInvoiceItemChecksTheCaller(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newInvoiceService(f)
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(-time.Hour), "completed")
+	invoice, err := s.CreateInvoiceForBooking(context.Background(), booking.ID, f.Patient.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := f.AddDoctor(t, "Sari", "Dentist")
+	item := model.InvoiceItemRequest{ItemType: "drug", Description: "Paracetamol", Quantity: 1, UnitPrice: 999999}
+
+	tests := []struct {
+		name     string
+		userID   uint
+		role     string
+		clinicID uint
+		wantErr  bool
+	}{
+		{"other doctor", other.UserId, "doctor", 0, true},
+		{"admin of another clinic", f.Patient.ID, "admin", f.Clinic.ID + 1, true},
+		{"patient", f.Patient.ID, "patient", 0, true},
+		{"own doctor", f.Doctor.UserId, "doctor", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.AddInvoiceItem(context.Background(), invoice.ID, item, tt.userID, tt.role, tt.clinicID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AddInvoiceItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	f.DB.Model(&model.Invoice{}).Where("id = ?", invoice.ID).Update("status", "paid")
+	if _, err := s.AddInvoiceItem(context.Background(), invoice.ID, item, f.Doctor.UserId, "doctor", 0); err == nil {
+		t.Error("AddInvoiceItem() added an item to a paid invoice")
+	}
+}
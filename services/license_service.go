@@ -0,0 +1,109 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+type LicenseService interface {
+	CheckLicenses(ctx context.Context, now time.Time) (int, error)
+	GetOpenLicenseAlerts(ctx context.Context, limit, offset int) ([]model.LicenseAlert, *utils.Paginator, error)
+	Run(ctx context.Context, interval time.Duration)
+}
+
+type LicenseServiceImpl struct {
+	DoctorRepository       repository.DoctorRepository
+	LicenseAlertRepository repository.LicenseAlertRepository
+	WarningDays            int
+}
+
+// CheckLicenses raises an alert for every STR or SIP that expires within the
+// warning window or has already expired. Alerts are raised once per license
+// and expiry date; it returns the number of new alerts.
+func (s *LicenseServiceImpl) CheckLicenses(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "LicenseService.CheckLicenses")
+	defer span.End()
+
+	warnBefore := now.AddDate(0, 0, s.WarningDays)
+	doctors, err := s.DoctorRepository.GetDoctorsWithLicenseExpiringBefore(ctx, warnBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, doctor := range doctors {
+		licenses := map[string]*time.Time{"STR": doctor.STRExpiresAt, "SIP": doctor.SIPExpiresAt}
+		for license, expiresAt := range licenses {
+			if expiresAt == nil || !expiresAt.Before(warnBefore) {
+				continue
+			}
+
+			kind := "expiring"
+			if !expiresAt.After(now) {
+				kind = "expired"
+			}
+
+			alert := model.LicenseAlert{DoctorId: doctor.ID, License: license, Kind: kind, ExpiresAt: *expiresAt}
+			isNew, err := s.LicenseAlertRepository.CreateLicenseAlert(ctx, &alert)
+			if err != nil {
+				return created, err
+			}
+			if isNew {
+				created++
+				slog.WarnContext(ctx, "license alert", "license", license, "kind", kind, "doctor_id", doctor.ID, "doctor_name", doctor.User.Name, "expires_at", expiresAt.Format("2006-01-02"))
+			}
+		}
+	}
+	return created, nil
+}
+
+func (s *LicenseServiceImpl) GetOpenLicenseAlerts(ctx context.Context, limit, offset int) ([]model.LicenseAlert, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "LicenseService.GetOpenLicenseAlerts")
+	defer span.End()
+
+	alerts, totalRows, err := s.LicenseAlertRepository.GetOpenLicenseAlerts(ctx, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return alerts, pagination, nil
+}
+
+// Run checks licenses immediately and then on every interval until ctx is done.
+func (s *LicenseServiceImpl) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CheckLicenses(ctx, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "license check failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkLicenseValidOn returns an error when the doctor's STR or SIP is expired
+// on the given date.
+func checkLicenseValidOn(doctor *model.Doctor, date time.Time) error {
+	if doctor.STRExpiresAt != nil && !doctor.STRExpiresAt.After(date) {
+		return errors.New("doctor STR license is expired on the schedule date")
+	}
+	if doctor.SIPExpiresAt != nil && !doctor.SIPExpiresAt.After(date) {
+		return errors.New("doctor SIP license is expired on the schedule date")
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/payment"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PaymentService interface {
+	CreateCharge(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) (*model.Payment, error)
+	HandleWebhook(ctx context.Context, body []byte, signature string) error
+	RecordManualPayment(ctx context.Context, bookingID uint, request model.ManualPaymentRequest, userID uint, clinicID uint) (*model.Payment, error)
+	GetPaymentsByBookingId(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) ([]model.Payment, error)
+}
+
+type PaymentServiceImpl struct {
+	PaymentRepository repository.PaymentRepository
+	BookingRepository repository.BookingRepository
+	BookingService    BookingService
+	RefundService     RefundService
+	InvoiceService    InvoiceService
+	Provider          payment.Provider
+}
+
+// CreateCharge starts an online payment for a pending booking. An unexpired
+// pending charge is reused so patients retrying checkout get the same VA number.
+func (s *PaymentServiceImpl) CreateCharge(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) (*model.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.CreateCharge")
+	defer span.End()
+
+	booking, err := s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	if booking.Status != "pending" {
+		return nil, errors.New("only pending bookings can be paid online")
+	}
+
+	payments, err := s.PaymentRepository.GetPaymentsByBookingId(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range payments {
+		if existing.Status == "paid" {
+			return nil, errors.New("booking is already paid")
+		}
+		if existing.Status == "pending" && existing.Provider == s.Provider.Name() && existing.ExpiresAt != nil && existing.ExpiresAt.After(time.Now()) {
+			return &existing, nil
+		}
+	}
+
+	price, discount := bookingAmount(booking)
+	amount := price - discount
+	charge, err := s.Provider.CreateCharge(payment.ChargeRequest{
+		Reference:     fmt.Sprintf("BOOKING-%d", booking.ID),
+		Amount:        amount,
+		CustomerName:  booking.User.Name,
+		CustomerEmail: booking.User.Email,
+		Description:   booking.Service.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	newPayment := model.Payment{
+		BookingId:   booking.ID,
+		UserId:      booking.UserId,
+		Amount:      amount,
+		Method:      "online",
+		Provider:    s.Provider.Name(),
+		ProviderRef: charge.ProviderRef,
+		Status:      "pending",
+		VANumber:    charge.VANumber,
+		RedirectURL: charge.RedirectURL,
+		ExpiresAt:   &charge.ExpiresAt,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if err := s.PaymentRepository.CreatePayment(ctx, &newPayment); err != nil {
+		return nil, err
+	}
+
+	return &newPayment, nil
+}
+
+// HandleWebhook applies a signed provider callback. The payment is moved out
+// of pending by a conditional update, so a callback that is repeated or races
+// another takes effect only once. A payment settled after its booking was
+// cancelled, or after its charge was voided by a payment at the front desk,
+// is refunded in full.
+func (s *PaymentServiceImpl) HandleWebhook(ctx context.Context, body []byte, signature string) error {
+	ctx, span := tracing.Start(ctx, "PaymentService.HandleWebhook")
+	defer span.End()
+
+	event, err := s.Provider.ParseWebhook(body, signature)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.PaymentRepository.GetPaymentByProviderRef(ctx, s.Provider.Name(), event.ProviderRef)
+	if err != nil {
+		return errors.New("payment not found")
+	}
+
+	if existing.Status != "pending" && existing.Status != "void" {
+		return nil
+	}
+
+	switch event.Status {
+	case "paid":
+		if event.Amount != existing.Amount {
+			return errors.New("paid amount does not match the charge")
+		}
+		now := time.Now()
+		existing.Status = "paid"
+		existing.PaidAt = &now
+	case "failed", "expired":
+		existing.Status = event.Status
+	default:
+		return fmt.Errorf("unknown payment status %q", event.Status)
+	}
+
+	settled, err := s.PaymentRepository.UpdatePaymentStatus(ctx, existing, "pending")
+	if err != nil {
+		return err
+	}
+	if !settled && existing.Status == "paid" {
+		voided, err := s.PaymentRepository.UpdatePaymentStatus(ctx, existing, "void")
+		if err != nil {
+			return err
+		}
+		if voided {
+			_, err := s.RefundService.RefundPayment(ctx, existing, "paid after the booking was paid at the front desk", existing.UserId)
+			return err
+		}
+	}
+	if !settled || existing.Status != "paid" {
+		return nil
+	}
+
+	booking, err := s.confirmBooking(ctx, existing.BookingId, existing.UserId)
+	if err != nil {
+		return err
+	}
+	if booking.Status == "cancelled" {
+		_, err := s.RefundService.RefundPayment(ctx, existing, "paid after the booking was cancelled", existing.UserId)
+		return err
+	}
+	return s.InvoiceService.SettleInvoice(ctx, booking.ID, existing.UserId)
+}
+
+func (s *PaymentServiceImpl) RecordManualPayment(ctx context.Context, bookingID uint, request model.ManualPaymentRequest, userID uint, clinicID uint) (*model.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.RecordManualPayment")
+	defer span.End()
+
+	booking, err := s.BookingRepository.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+	if err := checkBookingClinic(booking, clinicID); err != nil {
+		return nil, err
+	}
+
+	if booking.Status == "cancelled" {
+		return nil, errors.New("cannot record a payment for a cancelled booking")
+	}
+
+	if request.Method != "cash" && request.Method != "edc" {
+		return nil, errors.New("method must be cash or edc")
+	}
+
+	payments, err := s.PaymentRepository.GetPaymentsByBookingId(ctx, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range payments {
+		if existing.Status == "paid" {
+			return nil, errors.New("booking is already paid")
+		}
+	}
+
+	price, discount := bookingAmount(booking)
+	if request.Amount != price-discount {
+		return nil, fmt.Errorf("amount must be %d, the price of the booking", price-discount)
+	}
+
+	// Pending online charges are voided so they cannot settle the booking a
+	// second time; one paid anyway is refunded when its webhook arrives
+	for i := range payments {
+		if payments[i].Status != "pending" {
+			continue
+		}
+		payments[i].Status = "void"
+		payments[i].UpdatedBy = userID
+		voided, err := s.PaymentRepository.UpdatePaymentStatus(ctx, &payments[i], "pending")
+		if err != nil {
+			return nil, err
+		}
+		if !voided {
+			if current, err := s.PaymentRepository.GetPaymentById(ctx, payments[i].ID); err == nil && current.Status == "paid" {
+				return nil, errors.New("booking is already paid")
+			}
+		}
+	}
+
+	now := time.Now()
+	newPayment := model.Payment{
+		BookingId:   booking.ID,
+		UserId:      booking.UserId,
+		Amount:      request.Amount,
+		Method:      request.Method,
+		Provider:    "manual",
+		ProviderRef: fmt.Sprintf("MANUAL-%d-%d", booking.ID, now.UnixNano()),
+		Reference:   request.Reference,
+		Status:      "paid",
+		PaidAt:      &now,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	if err := s.PaymentRepository.CreatePayment(ctx, &newPayment); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.confirmBooking(ctx, booking.ID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.InvoiceService.SettleInvoice(ctx, booking.ID, userID); err != nil {
+		return nil, err
+	}
+
+	return &newPayment, nil
+}
+
+func (s *PaymentServiceImpl) GetPaymentsByBookingId(ctx context.Context, bookingID uint, userID uint, userRole string, clinicID uint) ([]model.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.GetPaymentsByBookingId")
+	defer span.End()
+
+	if _, err := s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.PaymentRepository.GetPaymentsByBookingId(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// confirmBooking confirms a booking that is still pending once it is paid and
+// returns the booking as it is afterwards.
+func (s *PaymentServiceImpl) confirmBooking(ctx context.Context, bookingID uint, userID uint) (*model.Booking, error) {
+	if err := s.BookingRepository.ConfirmPendingBooking(ctx, bookingID, userID); err != nil {
+		return nil, err
+	}
+	return s.BookingRepository.GetBookingById(ctx, bookingID)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/payment"
+	"booking-klinik/repository"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func newPaymentService(f *testutil.Fixture, provider payment.Provider) *PaymentServiceImpl {
+	bookings := &repository.BookingRepositoryImpl{DB: f.DB}
+	payments := &repository.PaymentRepositoryImpl{DB: f.DB}
+	return &PaymentServiceImpl{
+		PaymentRepository: payments,
+		BookingRepository: bookings,
+		RefundService: &RefundServiceImpl{
+			RefundRepository:  &repository.RefundRepositoryImpl{DB: f.DB},
+			PaymentRepository: payments,
+			BookingRepository: bookings,
+			Provider:          provider,
+		},
+		InvoiceService: &InvoiceServiceImpl{
+			InvoiceRepository: &repository.InvoiceRepositoryImpl{DB: f.DB},
+			BookingRepository: bookings,
+			PaymentRepository: payments,
+		},
+		Provider: provider,
+	}
+}
+
+// deliverPaid sends the provider's "paid" callback for the charge times times.
+func deliverPaid(t *testing.T, s *PaymentServiceImpl, provider *payment.MockProvider, charge model.Payment, times int) {
+	t.Helper()
+	body, err := json.Marshal(map[string]interface{}{"provider_ref": charge.ProviderRef, "status": "paid", "amount": charge.Amount})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < times; i++ {
+		if err := s.HandleWebhook(context.Background(), body, provider.Sign(body)); err != nil {
+			t.Fatalf("HandleWebhook() delivery %d error = %v", i+1, err)
+		}
+	}
+}
+
+// refundsOf returns the refunds of a payment, checking its status on the way.
+func refundsOf(t *testing.T, f *testutil.Fixture, paid model.Payment, wantStatus string) []model.Refund {
+	t.Helper()
+	var stored model.Payment
+	if err := f.DB.First(&stored, paid.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != wantStatus {
+		t.Errorf("payment status = %q, want %q", stored.Status, wantStatus)
+	}
+	var refunds []model.Refund
+	if err := f.DB.Where("payment_id = ?", paid.ID).Find(&refunds).Error; err != nil {
+		t.Fatal(err)
+	}
+	return refunds
+}
+
+func TestManualPaymentThenWebhookRefundsTheCharge(t *testing.T) {
+	f := testutil.NewFixture(t)
+	provider := &payment.MockProvider{Secret: "secret"}
+	s := newPaymentService(f, provider)
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(24*time.Hour), "pending")
+	charge := f.AddPayment(t, booking, booking.Price, "mock", "pending")
+
+	if _, err := s.RecordManualPayment(context.Background(), booking.ID, model.ManualPaymentRequest{Method: "cash", Amount: booking.Price}, f.Patient.ID, 0); err != nil {
+		t.Fatalf("RecordManualPayment() error = %v", err)
+	}
+	deliverPaid(t, s, provider, charge, 2)
+
+	refunds := refundsOf(t, f, charge, "refunded")
+	if len(refunds) != 1 || refunds[0].Amount != charge.Amount || refunds[0].Status != "succeeded" {
+		t.Errorf("refunds = %+v, want the whole charge refunded once", refunds)
+	}
+	var stored model.Booking
+	f.DB.First(&stored, booking.ID)
+	if stored.Status != "confirmed" {
+		t.Errorf("booking status = %q, want confirmed by the cash payment", stored.Status)
+	}
+}
+
+func TestRepeatedWebhookForCancelledBookingRefundsOnce(t *testing.T) {
+	f := testutil.NewFixture(t)
+	provider := &payment.MockProvider{Secret: "secret"}
+	s := newPaymentService(f, provider)
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(24*time.Hour), "cancelled")
+	charge := f.AddPayment(t, booking, booking.Price, "mock", "pending")
+
+	deliverPaid(t, s, provider, charge, 2)
+
+	if refunds := refundsOf(t, f, charge, "refunded"); len(refunds) != 1 {
+		t.Errorf("%d refunds, want the late payment refunded once", len(refunds))
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"strings"
+)
+
+type PromoService interface {
+	CreatePromo(ctx context.Context, request model.PromoRequest, userID uint) (*model.Promo, error)
+	GetAllPromos(ctx context.Context, limit, offset int) ([]model.Promo, *utils.Paginator, error)
+	GetPromoById(ctx context.Context, id uint) (*model.Promo, error)
+	UpdatePromo(ctx context.Context, promoID uint, request model.PromoRequest, userID uint) (*model.Promo, error)
+	DeletePromo(ctx context.Context, promoID uint, userID uint) error
+	ApplyPromo(ctx context.Context, booking *model.Booking) (*model.Promo, error)
+}
+
+type PromoServiceImpl struct {
+	PromoRepository repository.PromoRepository
+}
+
+func (s *PromoServiceImpl) CreatePromo(ctx context.Context, request model.PromoRequest, userID uint) (*model.Promo, error) {
+	ctx, span := tracing.Start(ctx, "PromoService.CreatePromo")
+	defer span.End()
+
+	promo := model.Promo{IsActive: true, CreatedBy: userID, UpdatedBy: userID}
+	if err := applyPromoRequest(&promo, request); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.PromoRepository.GetPromoByCode(ctx, promo.Code); err == nil {
+		return nil, errors.New("promo code already exists")
+	}
+
+	if err := s.PromoRepository.CreatePromo(ctx, &promo); err != nil {
+		return nil, err
+	}
+	return &promo, nil
+}
+
+func (s *PromoServiceImpl) GetAllPromos(ctx context.Context, limit, offset int) ([]model.Promo, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "PromoService.GetAllPromos")
+	defer span.End()
+
+	promos, totalRows, err := s.PromoRepository.GetAllPromos(ctx, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return promos, pagination, nil
+}
+
+func (s *PromoServiceImpl) GetPromoById(ctx context.Context, id uint) (*model.Promo, error) {
+	ctx, span := tracing.Start(ctx, "PromoService.GetPromoById")
+	defer span.End()
+
+	promo, err := s.PromoRepository.GetPromoById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+func (s *PromoServiceImpl) UpdatePromo(ctx context.Context, promoID uint, request model.PromoRequest, userID uint) (*model.Promo, error) {
+	ctx, span := tracing.Start(ctx, "PromoService.UpdatePromo")
+	defer span.End()
+
+	promo, err := s.PromoRepository.GetPromoById(ctx, promoID)
+	if err != nil {
+		return nil, err
+	}
+
+	code := promo.Code
+	if err := applyPromoRequest(promo, request); err != nil {
+		return nil, err
+	}
+
+	if promo.Code != code {
+		if _, err := s.PromoRepository.GetPromoByCode(ctx, promo.Code); err == nil {
+			return nil, errors.New("promo code already exists")
+		}
+	}
+
+	promo.UpdatedBy = userID
+	if err := s.PromoRepository.UpdatePromo(ctx, promo); err != nil {
+		return nil, err
+	}
+	return promo, nil
+}
+
+func (s *PromoServiceImpl) DeletePromo(ctx context.Context, promoID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "PromoService.DeletePromo")
+	defer span.End()
+
+	if err := s.PromoRepository.DeletePromo(ctx, promoID, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ApplyPromo validates booking.PromoCode against the booking and sets
+// booking.DiscountAmount from booking.Price. The validity window is checked
+// against the appointment date.
+func (s *PromoServiceImpl) ApplyPromo(ctx context.Context, booking *model.Booking) (*model.Promo, error) {
+	ctx, span := tracing.Start(ctx, "PromoService.ApplyPromo")
+	defer span.End()
+
+	booking.PromoCode = strings.ToUpper(strings.TrimSpace(booking.PromoCode))
+
+	promo, err := s.PromoRepository.GetPromoByCode(ctx, booking.PromoCode)
+	if err != nil || !promo.IsActive {
+		return nil, errors.New("promo code is invalid")
+	}
+
+	// Promo dates belong to no clinic, so they are days in the default timezone
+	bookingDay := utils.DateIn(booking.StartAt, utils.DefaultLocation()).Format("2006-01-02")
+	if bookingDay < promo.ValidFrom.Format("2006-01-02") || bookingDay > promo.ValidUntil.Format("2006-01-02") {
+		return nil, errors.New("promo code is not valid on the booking date")
+	}
+
+	if promo.ServiceId != nil && *promo.ServiceId != booking.ServiceId {
+		return nil, errors.New("promo code does not apply to this service")
+	}
+
+	if promo.DoctorId != nil && *promo.DoctorId != booking.DoctorId {
+		return nil, errors.New("promo code does not apply to this doctor")
+	}
+
+	if promo.FirstVisitOnly {
+		visits, err := s.PromoRepository.CountVisitsByUserId(ctx, booking.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if visits > 0 {
+			return nil, errors.New("promo code is only valid for the first visit")
+		}
+	}
+
+	if promo.MaxUses > 0 {
+		used, err := s.PromoRepository.CountUsages(ctx, promo.ID)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(promo.MaxUses) {
+			return nil, repository.ErrPromoUsageLimit
+		}
+	}
+
+	if promo.MaxUsesPerPatient > 0 {
+		used, err := s.PromoRepository.CountUsagesByUserId(ctx, promo.ID, booking.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if used >= int64(promo.MaxUsesPerPatient) {
+			return nil, errors.New("you have already used this promo code")
+		}
+	}
+
+	discount := promo.DiscountValue
+	if promo.DiscountType == "percent" {
+		discount = booking.Price * promo.DiscountValue / 100
+	}
+	if discount > booking.Price {
+		discount = booking.Price
+	}
+
+	booking.DiscountAmount = discount
+	return promo, nil
+}
+
+func applyPromoRequest(promo *model.Promo, request model.PromoRequest) error {
+	code := strings.ToUpper(strings.TrimSpace(request.Code))
+	if code == "" {
+		return errors.New("promo code is required")
+	}
+
+	switch request.DiscountType {
+	case "percent":
+		if request.DiscountValue <= 0 || request.DiscountValue > 100 {
+			return errors.New("percent discount must be between 1 and 100")
+		}
+	case "fixed":
+		if request.DiscountValue <= 0 {
+			return errors.New("fixed discount must be greater than 0")
+		}
+	default:
+		return errors.New("discount type must be percent or fixed")
+	}
+
+	validFrom, err := utils.ParseDate(request.ValidFrom)
+	if err != nil {
+		return errors.New("invalid valid_from date format")
+	}
+	validUntil, err := utils.ParseDate(request.ValidUntil)
+	if err != nil {
+		return errors.New("invalid valid_until date format")
+	}
+	if validUntil.Before(validFrom) {
+		return errors.New("valid_until must not be before valid_from")
+	}
+
+	if request.MaxUses < 0 || request.MaxUsesPerPatient < 0 {
+		return errors.New("usage limits cannot be negative")
+	}
+
+	promo.Code = code
+	promo.Description = request.Description
+	promo.DiscountType = request.DiscountType
+	promo.DiscountValue = request.DiscountValue
+	promo.ValidFrom = validFrom
+	promo.ValidUntil = validUntil
+	promo.ServiceId = request.ServiceId
+	promo.DoctorId = request.DoctorId
+	promo.MaxUses = request.MaxUses
+	promo.MaxUsesPerPatient = request.MaxUsesPerPatient
+	promo.FirstVisitOnly = request.FirstVisitOnly
+	if request.IsActive != nil {
+		promo.IsActive = *request.IsActive
+	}
+	return nil
+}
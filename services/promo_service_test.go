@@ -0,0 +1,92 @@
+package services
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestApplyPromo(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := &PromoServiceImpl{PromoRepository: &repository.PromoRepositoryImpl{DB: f.DB}}
+	dental := f.AddService(t, "Dental", 45)
+	validFrom := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+	for _, promo := range []model.Promo{
+		{Code: "TENPCT", DiscountType: "percent", DiscountValue: 10},
+		{Code: "BIGCUT", DiscountType: "fixed", DiscountValue: 500000},
+		{Code: "DENTAL", DiscountType: "fixed", DiscountValue: 10000, ServiceId: &dental.ID},
+		{Code: "NEWBIE", DiscountType: "fixed", DiscountValue: 10000, FirstVisitOnly: true},
+	} {
+		promo.ValidFrom, promo.ValidUntil, promo.IsActive = validFrom, validUntil, true
+		var stored model.Promo
+		testutil.MustCreate(t, f.DB, &stored, promo)
+	}
+	var inactive model.Promo
+	testutil.MustCreate(t, f.DB, &inactive, model.Promo{Code: "OLD", DiscountType: "fixed", DiscountValue: 10000, ValidFrom: validFrom, ValidUntil: validUntil})
+	f.DB.Model(&inactive).Update("is_active", false)
+	// A visit outside the promo period rules out first visit promos
+	f.AddBooking(t, f.Doctor, f.Service, time.Date(2029, 6, 1, 2, 0, 0, 0, time.UTC), "completed")
+
+	// 07:00 in Jakarta on the last day of the promo is still the 31st there
+	lastDay := time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		code     string
+		start    time.Time
+		wantDisc int
+		wantErr  string
+	}{
+		{"percent", " tenpct ", lastDay, 10000, ""},
+		{"fixed capped at the price", "BIGCUT", lastDay, 100000, ""},
+		{"after the period in Jakarta", "TENPCT", lastDay.Add(17 * time.Hour), 0, "promo code is not valid on the booking date"},
+		{"other service", "DENTAL", lastDay, 0, "promo code does not apply to this service"},
+		{"not a first visit", "NEWBIE", lastDay, 0, "promo code is only valid for the first visit"},
+		{"inactive", "OLD", lastDay, 0, "promo code is invalid"},
+		{"unknown", "NOPE", lastDay, 0, "promo code is invalid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, StartAt: tt.start, Price: f.Service.Price, PromoCode: tt.code}
+			_, err := s.ApplyPromo(context.Background(), booking)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ApplyPromo() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPromo() error = %v", err)
+			}
+			if booking.DiscountAmount != tt.wantDisc {
+				t.Errorf("DiscountAmount = %d, want %d", booking.DiscountAmount, tt.wantDisc)
+			}
+		})
+	}
+}
+
+func TestApplyPromoUsageLimit(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := &PromoServiceImpl{PromoRepository: &repository.PromoRepositoryImpl{DB: f.DB}}
+	var promo model.Promo
+	testutil.MustCreate(t, f.DB, &promo, model.Promo{Code: "ONCE", DiscountType: "fixed", DiscountValue: 10000, ValidFrom: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), ValidUntil: time.Date(2030, 12, 31, 0, 0, 0, 0, time.UTC), MaxUses: 1, IsActive: true})
+	start := time.Date(2030, 2, 1, 2, 0, 0, 0, time.UTC)
+	used := f.AddBooking(t, f.Doctor, f.Service, start, "confirmed")
+	var usage model.PromoUsage
+	testutil.MustCreate(t, f.DB, &usage, model.PromoUsage{PromoId: promo.ID, BookingId: used.ID, UserId: f.Patient.ID, DiscountAmount: 10000})
+
+	booking := &model.Booking{UserId: f.Patient.ID, DoctorId: f.Doctor.ID, ServiceId: f.Service.ID, StartAt: start.Add(time.Hour), Price: f.Service.Price, PromoCode: "ONCE"}
+	if _, err := s.ApplyPromo(context.Background(), booking); !errors.Is(err, repository.ErrPromoUsageLimit) {
+		t.Fatalf("ApplyPromo() error = %v, want ErrPromoUsageLimit", err)
+	}
+
+	// Cancelling the booking gives the usage back
+	f.DB.Model(&used).Update("status", "cancelled")
+	if _, err := s.ApplyPromo(context.Background(), booking); err != nil {
+		t.Fatalf("ApplyPromo() after cancelling error = %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/model"
+	"booking-klinik/payment"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RefundService interface {
+	CancelWithRefunds(ctx context.Context, booking *model.Booking, reason string, userID uint) ([]model.Refund, error)
+	RetryFailedRefunds(ctx context.Context, bookingID uint, userID uint) ([]model.Refund, error)
+	RefundPayment(ctx context.Context, paid *model.Payment, reason string, userID uint) (*model.Refund, error)
+	GetRefundsByStatus(ctx context.Context, status string, clinicID uint, limit, offset int) ([]model.Refund, *utils.Paginator, error)
+	ApproveRefund(ctx context.Context, refundID uint, userID uint, clinicID uint) (*model.Refund, error)
+	RejectRefund(ctx context.Context, refundID uint, userID uint, clinicID uint) (*model.Refund, error)
+}
+
+type RefundServiceImpl struct {
+	RefundRepository  repository.RefundRepository
+	PaymentRepository repository.PaymentRepository
+	BookingRepository repository.BookingRepository
+	Provider          payment.Provider
+	Rules             []config.RefundRule
+}
+
+// RefundPercent returns the share of the payment to refund for a booking
+// starting at start that is cancelled at cancelledAt.
+func (s *RefundServiceImpl) RefundPercent(start time.Time, cancelledAt time.Time) int {
+	hoursBefore := start.Sub(cancelledAt).Hours()
+	for _, rule := range s.Rules {
+		if hoursBefore >= float64(rule.MinHoursBefore) {
+			return rule.Percent
+		}
+	}
+	return 0
+}
+
+// CancelWithRefunds cancels a booking and applies the refund policy to every
+// settled payment. The status and the refunds are saved together; online
+// refunds are then sent to the provider, while cash and EDC refunds wait for
+// admin approval. A refund the provider fails is kept as failed for
+// RetryFailedRefunds.
+func (s *RefundServiceImpl) CancelWithRefunds(ctx context.Context, booking *model.Booking, reason string, userID uint) ([]model.Refund, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.CancelWithRefunds")
+	defer span.End()
+
+	booking.Status = "cancelled"
+	booking.UpdatedBy = userID
+	refunds, err := s.RefundRepository.CancelBookingWithRefunds(ctx, booking, s.RefundPercent(booking.StartAt, time.Now()), reason)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range refunds {
+		if refunds[i].Payment.Provider == s.Provider.Name() {
+			if err := s.executeRefund(ctx, &refunds[i], &refunds[i].Payment, userID); err != nil {
+				return nil, fmt.Errorf("booking cancelled but the refund failed, cancel it again to retry: %w", err)
+			}
+		}
+	}
+
+	return refunds, nil
+}
+
+// RefundPayment gives a whole payment back, such as one settled after its
+// booking was cancelled. Online payments are refunded through the provider
+// right away; manual ones wait for admin approval.
+func (s *RefundServiceImpl) RefundPayment(ctx context.Context, paid *model.Payment, reason string, userID uint) (*model.Refund, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.RefundPayment")
+	defer span.End()
+
+	refund := model.Refund{
+		PaymentId: paid.ID,
+		BookingId: paid.BookingId,
+		Amount:    paid.Amount,
+		Percent:   100,
+		Reason:    reason,
+		Status:    "pending_approval",
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := s.RefundRepository.CreateRefund(ctx, &refund); err != nil {
+		return nil, err
+	}
+
+	if paid.Provider == s.Provider.Name() {
+		if err := s.executeRefund(ctx, &refund, paid, userID); err != nil {
+			return nil, err
+		}
+	}
+	return &refund, nil
+}
+
+// RetryFailedRefunds sends the failed online refunds of a booking to the
+// provider again.
+func (s *RefundServiceImpl) RetryFailedRefunds(ctx context.Context, bookingID uint, userID uint) ([]model.Refund, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.RetryFailedRefunds")
+	defer span.End()
+
+	refunds, err := s.RefundRepository.GetRefundsByBookingId(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	var retried []model.Refund
+	for _, refund := range refunds {
+		if refund.Status != "failed" {
+			continue
+		}
+		if err := s.executeRefund(ctx, &refund, &refund.Payment, userID); err != nil {
+			return nil, err
+		}
+		retried = append(retried, refund)
+	}
+	return retried, nil
+}
+
+func (s *RefundServiceImpl) GetRefundsByStatus(ctx context.Context, status string, clinicID uint, limit, offset int) ([]model.Refund, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.GetRefundsByStatus")
+	defer span.End()
+
+	if status == "" {
+		status = "pending_approval"
+	}
+
+	refunds, totalRows, err := s.RefundRepository.GetRefundsByStatus(ctx, status, clinicID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return refunds, pagination, nil
+}
+
+// ApproveRefund settles a refund that is waiting for approval. Refunds of
+// manual payments are marked as paid out at the front desk.
+func (s *RefundServiceImpl) ApproveRefund(ctx context.Context, refundID uint, userID uint, clinicID uint) (*model.Refund, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.ApproveRefund")
+	defer span.End()
+
+	refund, err := s.RefundRepository.GetRefundById(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingClinicById(ctx, s.BookingRepository, refund.BookingId, clinicID); err != nil {
+		return nil, err
+	}
+
+	if refund.Status != "pending_approval" {
+		return nil, errors.New("only refunds pending approval can be approved")
+	}
+
+	refund.ApprovedBy = userID
+	if err := s.executeRefund(ctx, refund, &refund.Payment, userID); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (s *RefundServiceImpl) RejectRefund(ctx context.Context, refundID uint, userID uint, clinicID uint) (*model.Refund, error) {
+	ctx, span := tracing.Start(ctx, "RefundService.RejectRefund")
+	defer span.End()
+
+	refund, err := s.RefundRepository.GetRefundById(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBookingClinicById(ctx, s.BookingRepository, refund.BookingId, clinicID); err != nil {
+		return nil, err
+	}
+
+	if refund.Status != "pending_approval" {
+		return nil, errors.New("only refunds pending approval can be rejected")
+	}
+
+	refund.Status = "rejected"
+	refund.ApprovedBy = userID
+	refund.UpdatedBy = userID
+	if err := s.RefundRepository.UpdateRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+func (s *RefundServiceImpl) executeRefund(ctx context.Context, refund *model.Refund, paid *model.Payment, userID uint) error {
+	now := time.Now()
+	refund.UpdatedBy = userID
+
+	if paid.Provider == s.Provider.Name() {
+		result, err := s.Provider.Refund(payment.RefundRequest{ProviderRef: paid.ProviderRef, Amount: refund.Amount, Reason: refund.Reason})
+		if err != nil {
+			refund.Status = "failed"
+			if updateErr := s.RefundRepository.UpdateRefund(ctx, refund); updateErr != nil {
+				return updateErr
+			}
+			return err
+		}
+		refund.RefundRef = result.RefundRef
+		refund.Status = result.Status
+	} else {
+		refund.Status = "succeeded"
+	}
+
+	if refund.Status == "succeeded" {
+		refund.ProcessedAt = &now
+		if refund.Amount >= paid.Amount {
+			paid.Status = "refunded"
+		} else {
+			paid.Status = "partially_refunded"
+		}
+		paid.UpdatedBy = userID
+		if err := s.PaymentRepository.UpdatePayment(ctx, paid); err != nil {
+			return err
+		}
+	}
+
+	return s.RefundRepository.UpdateRefund(ctx, refund)
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"booking-klinik/config"
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/payment"
+	"booking-klinik/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRefundPercent(t *testing.T) {
+	rules, err := config.ParseRefundPolicy("24:100,2:50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &RefundServiceImpl{Rules: rules}
+	start := time.Date(2030, 1, 7, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		cancelledAt time.Time
+		want        int
+	}{
+		{"two days before", start.Add(-48 * time.Hour), 100},
+		{"exactly a day before", start.Add(-24 * time.Hour), 100},
+		{"just under a day before", start.Add(-24*time.Hour + time.Minute), 50},
+		{"an hour before", start.Add(-time.Hour), 0},
+		{"after the start", start.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.RefundPercent(start, tt.cancelledAt); got != tt.want {
+				t.Errorf("RefundPercent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// flakyProvider fails refunds until it is told to work.
+type flakyProvider struct {
+	payment.MockProvider
+	working bool
+}
+
+func (p *flakyProvider) Refund(request payment.RefundRequest) (*payment.RefundResult, error) {
+	if !p.working {
+		return nil, errors.New("gateway unavailable")
+	}
+	return p.MockProvider.Refund(request)
+}
+
+func TestCancelWithRefundsRetriesFailedRefunds(t *testing.T) {
+	f := testutil.NewFixture(t)
+	provider := &flakyProvider{}
+	rules, _ := config.ParseRefundPolicy("24:100,0:50")
+	s := &RefundServiceImpl{
+		RefundRepository:  &repository.RefundRepositoryImpl{DB: f.DB},
+		PaymentRepository: &repository.PaymentRepositoryImpl{DB: f.DB},
+		BookingRepository: &repository.BookingRepositoryImpl{DB: f.DB},
+		Provider:          provider,
+		Rules:             rules,
+	}
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(time.Hour), "confirmed")
+	online := f.AddPayment(t, booking, 100000, "mock", "paid")
+	cash := f.AddPayment(t, booking, 20000, "manual", "paid")
+
+	if _, err := s.CancelWithRefunds(context.Background(), &booking, "sick", f.Patient.ID); err == nil {
+		t.Fatal("CancelWithRefunds() succeeded with the gateway down, want an error")
+	}
+	var stored model.Booking
+	f.DB.First(&stored, booking.ID)
+	if stored.Status != "cancelled" {
+		t.Fatalf("booking status = %q, want cancelled even though the refund failed", stored.Status)
+	}
+
+	provider.working = true
+	retried, err := s.RetryFailedRefunds(context.Background(), booking.ID, f.Patient.ID)
+	if err != nil {
+		t.Fatalf("RetryFailedRefunds() error = %v", err)
+	}
+	if len(retried) != 1 || retried[0].Status != "succeeded" || retried[0].Amount != 50000 {
+		t.Fatalf("retried = %+v, want the online refund of 50000 succeeded", retried)
+	}
+
+	for _, tt := range []struct {
+		payment      model.Payment
+		refundStatus string
+		paidStatus   string
+	}{
+		{online, "succeeded", "partially_refunded"},
+		{cash, "pending_approval", "paid"},
+	} {
+		var refund model.Refund
+		if err := f.DB.Preload("Payment").Where("payment_id = ?", tt.payment.ID).First(&refund).Error; err != nil {
+			t.Fatal(err)
+		}
+		if refund.Status != tt.refundStatus || refund.Payment.Status != tt.paidStatus {
+			t.Errorf("%s refund %s with payment %s, want %s with %s", tt.payment.Provider, refund.Status, refund.Payment.Status, tt.refundStatus, tt.paidStatus)
+		}
+	}
+}
+
+func TestRefundPaymentGivesEverythingBack(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := &RefundServiceImpl{
+		RefundRepository:  &repository.RefundRepositoryImpl{DB: f.DB},
+		PaymentRepository: &repository.PaymentRepositoryImpl{DB: f.DB},
+		Provider:          &payment.MockProvider{},
+	}
+	booking := f.AddBooking(t, f.Doctor, f.Service, time.Now().Add(time.Hour), "cancelled")
+	paid := f.AddPayment(t, booking, 100000, "mock", "paid")
+
+	refund, err := s.RefundPayment(context.Background(), &paid, "paid after the booking was cancelled", f.Patient.ID)
+	if err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+	if refund.Amount != 100000 || refund.Percent != 100 || refund.Status != "succeeded" || paid.Status != "refunded" {
+		t.Errorf("refund %+v of payment %s, want 100000 succeeded and the payment refunded", refund, paid.Status)
+	}
+}
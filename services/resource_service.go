@@ -0,0 +1,172 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"strings"
+)
+
+type ResourceService interface {
+	CreateResource(ctx context.Context, request model.ResourceRequest, userID uint) (*model.Resource, error)
+	GetAllResources(ctx context.Context, resourceType string, clinicID uint, limit, offset int) ([]model.Resource, *utils.Paginator, error)
+	GetResourceById(ctx context.Context, id uint) (*model.Resource, error)
+	UpdateResource(ctx context.Context, id uint, request model.ResourceRequest, userID uint) (*model.Resource, error)
+	DeleteResource(ctx context.Context, id uint, userID uint) error
+	AddServiceResource(ctx context.Context, serviceID uint, resourceID uint) error
+	RemoveServiceResource(ctx context.Context, serviceID uint, resourceID uint) error
+	GetResourcesByServiceId(ctx context.Context, serviceID uint) ([]model.Resource, error)
+}
+
+type ResourceServiceImpl struct {
+	ResourceRepository repository.ResourceRepository
+	ServiceRepository  repository.ServiceRepository
+	ClinicRepository   repository.ClinicRepository
+}
+
+func (s *ResourceServiceImpl) CreateResource(ctx context.Context, request model.ResourceRequest, userID uint) (*model.Resource, error) {
+	ctx, span := tracing.Start(ctx, "ResourceService.CreateResource")
+	defer span.End()
+
+	resource := model.Resource{
+		Name:        strings.TrimSpace(request.Name),
+		Type:        strings.ToLower(request.Type),
+		Description: request.Description,
+		ClinicId:    request.ClinicID,
+		IsActive:    true,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+	if err := validateResource(&resource); err != nil {
+		return nil, err
+	}
+	if resource.ClinicId == 0 {
+		return nil, errors.New("clinic_id is required")
+	}
+	if _, err := s.ClinicRepository.GetClinicById(ctx, resource.ClinicId); err != nil {
+		return nil, errors.New("clinic not found")
+	}
+
+	if err := s.ResourceRepository.CreateResource(ctx, &resource); err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (s *ResourceServiceImpl) GetAllResources(ctx context.Context, resourceType string, clinicID uint, limit, offset int) ([]model.Resource, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "ResourceService.GetAllResources")
+	defer span.End()
+
+	resources, totalRows, err := s.ResourceRepository.GetAllResources(ctx, resourceType, clinicID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return resources, pagination, nil
+}
+
+func (s *ResourceServiceImpl) GetResourceById(ctx context.Context, id uint) (*model.Resource, error) {
+	ctx, span := tracing.Start(ctx, "ResourceService.GetResourceById")
+	defer span.End()
+
+	resource, err := s.ResourceRepository.GetResourceById(ctx, id)
+	if err != nil {
+		return nil, errors.New("resource not found")
+	}
+	return resource, nil
+}
+
+func (s *ResourceServiceImpl) UpdateResource(ctx context.Context, id uint, request model.ResourceRequest, userID uint) (*model.Resource, error) {
+	ctx, span := tracing.Start(ctx, "ResourceService.UpdateResource")
+	defer span.End()
+
+	resource, err := s.ResourceRepository.GetResourceById(ctx, id)
+	if err != nil {
+		return nil, errors.New("resource not found")
+	}
+
+	resource.Name = strings.TrimSpace(request.Name)
+	resource.Type = strings.ToLower(request.Type)
+	resource.Description = request.Description
+	if request.IsActive != nil {
+		resource.IsActive = *request.IsActive
+	}
+	resource.UpdatedBy = userID
+	if err := validateResource(resource); err != nil {
+		return nil, err
+	}
+
+	if err := s.ResourceRepository.UpdateResource(ctx, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+func (s *ResourceServiceImpl) DeleteResource(ctx context.Context, id uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "ResourceService.DeleteResource")
+	defer span.End()
+
+	if _, err := s.ResourceRepository.GetResourceById(ctx, id); err != nil {
+		return errors.New("resource not found")
+	}
+	return s.ResourceRepository.DeleteResource(ctx, id, userID)
+}
+
+func (s *ResourceServiceImpl) AddServiceResource(ctx context.Context, serviceID uint, resourceID uint) error {
+	ctx, span := tracing.Start(ctx, "ResourceService.AddServiceResource")
+	defer span.End()
+
+	if _, err := s.ServiceRepository.GetServiceById(ctx, serviceID); err != nil {
+		return errors.New("service not found")
+	}
+
+	resource, err := s.ResourceRepository.GetResourceById(ctx, resourceID)
+	if err != nil || !resource.IsActive {
+		return errors.New("resource is inactive or not found")
+	}
+
+	resources, err := s.ResourceRepository.GetResourcesByServiceId(ctx, serviceID)
+	if err != nil {
+		return err
+	}
+	for _, existing := range resources {
+		if existing.ID == resourceID {
+			return errors.New("service already requires this resource")
+		}
+	}
+
+	return s.ResourceRepository.AddServiceResource(ctx, serviceID, resourceID)
+}
+
+func (s *ResourceServiceImpl) RemoveServiceResource(ctx context.Context, serviceID uint, resourceID uint) error {
+	ctx, span := tracing.Start(ctx, "ResourceService.RemoveServiceResource")
+	defer span.End()
+
+	return s.ResourceRepository.RemoveServiceResource(ctx, serviceID, resourceID)
+}
+
+func (s *ResourceServiceImpl) GetResourcesByServiceId(ctx context.Context, serviceID uint) ([]model.Resource, error) {
+	ctx, span := tracing.Start(ctx, "ResourceService.GetResourcesByServiceId")
+	defer span.End()
+
+	if _, err := s.ServiceRepository.GetServiceById(ctx, serviceID); err != nil {
+		return nil, errors.New("service not found")
+	}
+	return s.ResourceRepository.GetResourcesByServiceId(ctx, serviceID)
+}
+
+func validateResource(resource *model.Resource) error {
+	if resource.Name == "" {
+		return errors.New("resource name is required")
+	}
+	if resource.Type != "room" && resource.Type != "equipment" {
+		return errors.New("resource type must be room or equipment")
+	}
+	return nil
+}
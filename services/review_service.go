@@ -0,0 +1,160 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+type ReviewService interface {
+	CreateReview(ctx context.Context, bookingID uint, request model.ReviewRequest, userID uint) (*model.Review, error)
+	ReplyToReview(ctx context.Context, reviewID uint, request model.ReviewReplyRequest, userID uint, userRole string) (*model.Review, error)
+	ModerateReview(ctx context.Context, reviewID uint, request model.ReviewModerationRequest, userID uint) (*model.Review, error)
+	GetReviewsByDoctorId(ctx context.Context, doctorID uint, includeHidden bool, limit, offset int) ([]model.Review, *utils.Paginator, error)
+	GetRatingSummaries(ctx context.Context, doctorIDs []uint) (map[uint]model.RatingSummary, error)
+}
+
+type ReviewServiceImpl struct {
+	ReviewRepository  repository.ReviewRepository
+	BookingRepository repository.BookingRepository
+	DoctorRepository  repository.DoctorRepository
+	// WindowDays is how long after the visit a patient may still review it.
+	WindowDays int
+}
+
+func (s *ReviewServiceImpl) CreateReview(ctx context.Context, bookingID uint, request model.ReviewRequest, userID uint) (*model.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.CreateReview")
+	defer span.End()
+
+	booking, err := s.BookingRepository.GetBookingById(ctx, bookingID)
+	if err != nil {
+		return nil, errors.New("booking not found")
+	}
+
+	if booking.UserId != userID {
+		return nil, errors.New("you can only review your own bookings")
+	}
+
+	if booking.Status != "completed" {
+		return nil, errors.New("only completed bookings can be reviewed")
+	}
+
+	if time.Since(booking.StartAt) > time.Duration(s.WindowDays)*24*time.Hour {
+		return nil, errors.New("the review period for this booking has ended")
+	}
+
+	if request.Rating < 1 || request.Rating > 5 {
+		return nil, errors.New("rating must be between 1 and 5")
+	}
+
+	if _, err := s.ReviewRepository.GetReviewByBookingId(ctx, bookingID); err == nil {
+		return nil, errors.New("booking has already been reviewed")
+	}
+
+	review := model.Review{
+		BookingId: booking.ID,
+		DoctorId:  booking.DoctorId,
+		UserId:    userID,
+		Rating:    request.Rating,
+		Comment:   strings.TrimSpace(request.Comment),
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+	if err := s.ReviewRepository.CreateReview(ctx, &review); err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (s *ReviewServiceImpl) ReplyToReview(ctx context.Context, reviewID uint, request model.ReviewReplyRequest, userID uint, userRole string) (*model.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.ReplyToReview")
+	defer span.End()
+
+	review, err := s.ReviewRepository.GetReviewById(ctx, reviewID)
+	if err != nil {
+		return nil, errors.New("review not found")
+	}
+
+	if userRole == "doctor" {
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
+		if err != nil || doctorID != review.DoctorId {
+			return nil, errors.New("you can only reply to reviews of your own bookings")
+		}
+	}
+
+	reply := strings.TrimSpace(request.Reply)
+	if reply == "" {
+		return nil, errors.New("reply is required")
+	}
+
+	now := time.Now()
+	review.Reply = reply
+	review.RepliedAt = &now
+	review.UpdatedBy = userID
+	if err := s.ReviewRepository.UpdateReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+// ModerateReview hides or restores a review. Hidden reviews are left out of
+// public listings and rating averages.
+func (s *ReviewServiceImpl) ModerateReview(ctx context.Context, reviewID uint, request model.ReviewModerationRequest, userID uint) (*model.Review, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.ModerateReview")
+	defer span.End()
+
+	review, err := s.ReviewRepository.GetReviewById(ctx, reviewID)
+	if err != nil {
+		return nil, errors.New("review not found")
+	}
+
+	if request.Hidden && strings.TrimSpace(request.Reason) == "" {
+		return nil, errors.New("reason is required when hiding a review")
+	}
+
+	review.IsHidden = request.Hidden
+	review.HiddenReason = ""
+	review.HiddenBy = 0
+	if request.Hidden {
+		review.HiddenReason = strings.TrimSpace(request.Reason)
+		review.HiddenBy = userID
+	}
+	review.UpdatedBy = userID
+	if err := s.ReviewRepository.UpdateReview(ctx, review); err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *ReviewServiceImpl) GetReviewsByDoctorId(ctx context.Context, doctorID uint, includeHidden bool, limit, offset int) ([]model.Review, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetReviewsByDoctorId")
+	defer span.End()
+
+	reviews, totalRows, err := s.ReviewRepository.GetReviewsByDoctorId(ctx, doctorID, includeHidden, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return reviews, pagination, nil
+}
+
+func (s *ReviewServiceImpl) GetRatingSummaries(ctx context.Context, doctorIDs []uint) (map[uint]model.RatingSummary, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.GetRatingSummaries")
+	defer span.End()
+
+	if len(doctorIDs) == 0 {
+		return map[uint]model.RatingSummary{}, nil
+	}
+	return s.ReviewRepository.GetRatingSummaries(ctx, doctorIDs)
+}
@@ -3,22 +3,32 @@ package services
 import (
 	"booking-klinik/model"
 	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
 	"errors"
+	"time"
 )
 
 type ServiceService interface {
-	CreateService(service model.Service) (*model.Service, error)
-	GetAllServices(limit, offset int) ([]model.Service, error)
-	GetServiceById(id uint) (*model.Service, error)
-	UpdateService(serviceID uint, service model.Service) (*model.Service, error)
-	DeleteService(serviceID uint, deletedBy uint) error
+	CreateService(ctx context.Context, service model.Service) (*model.Service, error)
+	GetAllServices(ctx context.Context, clinicID uint, limit, offset int) ([]model.Service, error)
+	GetServiceById(ctx context.Context, id uint) (*model.Service, error)
+	UpdateService(ctx context.Context, serviceID uint, service model.Service) (*model.Service, error)
+	DeleteService(ctx context.Context, serviceID uint, deletedBy uint) error
+	GetDoctorsByServiceId(ctx context.Context, serviceID uint) ([]model.Doctor, error)
+	GetServiceCatalogue(ctx context.Context, clinicID uint, limit, offset int) ([]model.Service, map[uint]time.Time, *utils.Paginator, error)
 }
 
 type ServiceServiceImpl struct {
-	ServiceRepository repository.ServiceRepository
+	ServiceRepository        repository.ServiceRepository
+	ServicePriceService      ServicePriceService
+	DoctorScheduleRepository repository.DoctorScheduleRepository
 }
 
-func (s *ServiceServiceImpl) CreateService(service model.Service) (*model.Service, error) {
+func (s *ServiceServiceImpl) CreateService(ctx context.Context, service model.Service) (*model.Service, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateService")
+	defer span.End()
 
 	if service.Name == "" {
 		return nil, errors.New("service name is required")
@@ -29,29 +39,49 @@ func (s *ServiceServiceImpl) CreateService(service model.Service) (*model.Servic
 	}
 
 	service.IsActive = true
-	if err := s.ServiceRepository.CreateService(&service); err != nil {
+	if err := s.ServiceRepository.CreateService(ctx, &service); err != nil {
 		return nil, err
 	}
 	return &service, nil
 }
 
-func (s *ServiceServiceImpl) GetAllServices(limit, offset int) ([]model.Service, error) {
-	if services, err := s.ServiceRepository.GetAllServices(limit, offset); err != nil {
+func (s *ServiceServiceImpl) GetAllServices(ctx context.Context, clinicID uint, limit, offset int) ([]model.Service, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetAllServices")
+	defer span.End()
+
+	services, err := s.ServiceRepository.GetAllServices(ctx, clinicID, limit, offset)
+	if err != nil {
 		return nil, err
-	} else {
-		return services, nil
 	}
+
+	// Show the price in effect today rather than the base price
+	for i := range services {
+		if services[i].Price, err = s.ServicePriceService.ResolvePrice(ctx, &services[i], nil, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return services, nil
 }
 
-func (s *ServiceServiceImpl) GetServiceById(id uint) (*model.Service, error) {
-	if service, err := s.ServiceRepository.GetServiceById(id); err != nil {
+func (s *ServiceServiceImpl) GetServiceById(ctx context.Context, id uint) (*model.Service, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetServiceById")
+	defer span.End()
+
+	service, err := s.ServiceRepository.GetServiceById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if service.Price, err = s.ServicePriceService.ResolvePrice(ctx, service, nil, time.Now()); err != nil {
 		return nil, err
-	} else {
-		return service, nil
 	}
+	return service, nil
 }
 
-func (s *ServiceServiceImpl) UpdateService(serviceID uint, service model.Service) (*model.Service, error) {
+func (s *ServiceServiceImpl) UpdateService(ctx context.Context, serviceID uint, service model.Service) (*model.Service, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UpdateService")
+	defer span.End()
+
 	if service.Name == "" {
 		return nil, errors.New("service name is required")
 	}
@@ -60,16 +90,81 @@ func (s *ServiceServiceImpl) UpdateService(serviceID uint, service model.Service
 		return nil, errors.New("service price and duration minutes must be greater than 0")
 	}
 
-	if updatedService, err := s.ServiceRepository.UpdateService(serviceID, service); err != nil {
+	existingService, err := s.ServiceRepository.GetServiceById(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingService.Price != service.Price {
+		if err := s.ServicePriceService.RecordPriceChange(ctx, existingService, service.Price, service.UpdatedBy); err != nil {
+			return nil, err
+		}
+	}
+
+	if updatedService, err := s.ServiceRepository.UpdateService(ctx, serviceID, service); err != nil {
 		return nil, err
 	} else {
 		return updatedService, nil
 	}
 }
 
-func (s *ServiceServiceImpl) DeleteService(serviceID uint, deletedBy uint) error {
-	if err := s.ServiceRepository.DeleteService(serviceID, deletedBy); err != nil {
+func (s *ServiceServiceImpl) DeleteService(ctx context.Context, serviceID uint, deletedBy uint) error {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteService")
+	defer span.End()
+
+	if err := s.ServiceRepository.DeleteService(ctx, serviceID, deletedBy); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (s *ServiceServiceImpl) GetDoctorsByServiceId(ctx context.Context, serviceID uint) ([]model.Doctor, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetDoctorsByServiceId")
+	defer span.End()
+
+	if _, err := s.ServiceRepository.GetServiceById(ctx, serviceID); err != nil {
+		return nil, errors.New("service not found")
+	}
+
+	doctors, err := s.ServiceRepository.GetDoctorsByServiceId(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return doctors, nil
+}
+
+// GetServiceCatalogue lists active services priced as of today, together with
+// the next date any doctor has a schedule for each of them.
+func (s *ServiceServiceImpl) GetServiceCatalogue(ctx context.Context, clinicID uint, limit, offset int) ([]model.Service, map[uint]time.Time, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetServiceCatalogue")
+	defer span.End()
+
+	services, totalRows, err := s.ServiceRepository.GetActiveServices(ctx, clinicID, limit, offset)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	now := time.Now()
+	serviceIds := make([]uint, 0, len(services))
+	for i := range services {
+		price, err := s.ServicePriceService.ResolvePrice(ctx, &services[i], nil, now)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		services[i].Price = price
+		serviceIds = append(serviceIds, services[i].ID)
+	}
+
+	nextDates := map[uint]time.Time{}
+	if len(serviceIds) > 0 {
+		nextDates, err = s.DoctorScheduleRepository.GetNextScheduleDatesByService(ctx, serviceIds, clinicID, startOfToday())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return services, nextDates, pagination, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ServicePriceService interface {
+	SchedulePrice(ctx context.Context, serviceID uint, request model.ServicePriceRequest, userID uint) (*model.ServicePrice, error)
+	GetPriceHistory(ctx context.Context, serviceID uint) ([]model.ServicePrice, error)
+	CancelScheduledPrice(ctx context.Context, serviceID uint, priceID uint, userID uint) error
+	RecordPriceChange(ctx context.Context, service *model.Service, newPrice int, userID uint) error
+	ResolvePrice(ctx context.Context, service *model.Service, doctorID *uint, at time.Time) (int, error)
+}
+
+type ServicePriceServiceImpl struct {
+	ServicePriceRepository repository.ServicePriceRepository
+	ServiceRepository      repository.ServiceRepository
+	DoctorRepository       repository.DoctorRepository
+}
+
+// SchedulePrice adds a price that takes effect at the start of the given day,
+// optionally only for one doctor.
+func (s *ServicePriceServiceImpl) SchedulePrice(ctx context.Context, serviceID uint, request model.ServicePriceRequest, userID uint) (*model.ServicePrice, error) {
+	ctx, span := tracing.Start(ctx, "ServicePriceService.SchedulePrice")
+	defer span.End()
+
+	service, err := s.ServiceRepository.GetServiceById(ctx, serviceID)
+	if err != nil {
+		return nil, errors.New("service not found")
+	}
+
+	if request.Price <= 0 {
+		return nil, errors.New("price must be greater than 0")
+	}
+
+	// Prices are not tied to a clinic, so the day starts in the default timezone
+	loc := utils.DefaultLocation()
+	effectiveFrom, err := time.ParseInLocation("2006-01-02", request.EffectiveFrom, loc)
+	if err != nil {
+		return nil, errors.New("invalid effective_from date format")
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if effectiveFrom.Before(today) {
+		return nil, errors.New("effective_from cannot be in the past")
+	}
+
+	if request.DoctorId != nil {
+		if _, err := s.DoctorRepository.GetDoctorById(ctx, *request.DoctorId); err != nil {
+			return nil, errors.New("doctor not found")
+		}
+	}
+
+	if err := s.seedHistory(ctx, service, userID); err != nil {
+		return nil, err
+	}
+
+	servicePrice := model.ServicePrice{
+		ServiceId:     service.ID,
+		DoctorId:      request.DoctorId,
+		Price:         request.Price,
+		EffectiveFrom: effectiveFrom,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	}
+
+	if err := s.ServicePriceRepository.CreateServicePrice(ctx, &servicePrice); err != nil {
+		return nil, err
+	}
+	return &servicePrice, nil
+}
+
+func (s *ServicePriceServiceImpl) GetPriceHistory(ctx context.Context, serviceID uint) ([]model.ServicePrice, error) {
+	ctx, span := tracing.Start(ctx, "ServicePriceService.GetPriceHistory")
+	defer span.End()
+
+	servicePrices, err := s.ServicePriceRepository.GetServicePricesByServiceId(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return servicePrices, nil
+}
+
+// CancelScheduledPrice removes a price that has not taken effect yet. Prices
+// already in effect are kept as history.
+func (s *ServicePriceServiceImpl) CancelScheduledPrice(ctx context.Context, serviceID uint, priceID uint, userID uint) error {
+	ctx, span := tracing.Start(ctx, "ServicePriceService.CancelScheduledPrice")
+	defer span.End()
+
+	servicePrice, err := s.ServicePriceRepository.GetServicePriceById(ctx, priceID)
+	if err != nil || servicePrice.ServiceId != serviceID {
+		return errors.New("service price not found")
+	}
+
+	if !servicePrice.EffectiveFrom.After(time.Now()) {
+		return errors.New("only future prices can be cancelled")
+	}
+
+	return s.ServicePriceRepository.DeleteServicePrice(ctx, priceID, userID)
+}
+
+// RecordPriceChange keeps the history when the base price of a service is
+// changed directly, with the new price in effect immediately.
+func (s *ServicePriceServiceImpl) RecordPriceChange(ctx context.Context, service *model.Service, newPrice int, userID uint) error {
+	ctx, span := tracing.Start(ctx, "ServicePriceService.RecordPriceChange")
+	defer span.End()
+
+	if err := s.seedHistory(ctx, service, userID); err != nil {
+		return err
+	}
+
+	return s.ServicePriceRepository.CreateServicePrice(ctx, &model.ServicePrice{
+		ServiceId:     service.ID,
+		Price:         newPrice,
+		EffectiveFrom: time.Now(),
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	})
+}
+
+// ResolvePrice returns the price of a service for a booking at the given time.
+// Services without any price history use their base price.
+func (s *ServicePriceServiceImpl) ResolvePrice(ctx context.Context, service *model.Service, doctorID *uint, at time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "ServicePriceService.ResolvePrice")
+	defer span.End()
+
+	servicePrice, err := s.ServicePriceRepository.GetEffectivePrice(ctx, service.ID, doctorID, at)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service.Price, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return servicePrice.Price, nil
+}
+
+// seedHistory records the current base price as in effect since the service
+// was created, so services priced before history was kept still resolve
+// correctly for past dates.
+func (s *ServicePriceServiceImpl) seedHistory(ctx context.Context, service *model.Service, userID uint) error {
+	if _, err := s.ServicePriceRepository.GetEffectivePrice(ctx, service.ID, nil, time.Now()); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return s.ServicePriceRepository.CreateServicePrice(ctx, &model.ServicePrice{
+		ServiceId:     service.ID,
+		Price:         service.Price,
+		EffectiveFrom: service.CreatedAt,
+		CreatedBy:     userID,
+		UpdatedBy:     userID,
+	})
+}
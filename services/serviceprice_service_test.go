@@ -0,0 +1,117 @@
+package services
+
+import (
+	"booking-klinik/internal/testutil"
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func newServicePriceService(f *testutil.Fixture) *ServicePriceServiceImpl {
+	return &ServicePriceServiceImpl{
+		ServicePriceRepository: &repository.ServicePriceRepositoryImpl{DB: f.DB},
+		ServiceRepository:      &repository.ServiceRepositoryImpl{DB: f.DB},
+		DoctorRepository:       &repository.DoctorRepositoryImpl{DB: f.DB},
+	}
+}
+
+func TestResolvePriceWithoutHistoryUsesBasePrice(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newServicePriceService(f)
+
+	price, err := s.ResolvePrice(context.Background(), &f.Service, &f.Doctor.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != f.Service.Price {
+		t.Errorf("ResolvePrice() = %d, want the base price %d", price, f.Service.Price)
+	}
+}
+
+func TestSchedulePriceKeepsEarlierPrices(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newServicePriceService(f)
+	ctx := context.Background()
+	nextWeek := time.Now().AddDate(0, 0, 7)
+
+	if _, err := s.SchedulePrice(ctx, f.Service.ID, model.ServicePriceRequest{Price: 120000, EffectiveFrom: nextWeek.Format("2006-01-02")}, 1); err != nil {
+		t.Fatalf("SchedulePrice() error = %v", err)
+	}
+	if _, err := s.SchedulePrice(ctx, f.Service.ID, model.ServicePriceRequest{Price: 150000, DoctorId: &f.Doctor.ID, EffectiveFrom: nextWeek.Format("2006-01-02")}, 1); err != nil {
+		t.Fatalf("SchedulePrice() for a doctor error = %v", err)
+	}
+
+	other := f.AddDoctor(t, "Budi", "General Practitioner", f.Service)
+	tests := []struct {
+		name     string
+		doctorID *uint
+		at       time.Time
+		want     int
+	}{
+		{"today", nil, time.Now(), 100000},
+		{"next week", &other.ID, nextWeek.Add(24 * time.Hour), 120000},
+		{"next week with the doctor", &f.Doctor.ID, nextWeek.Add(24 * time.Hour), 150000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, err := s.ResolvePrice(ctx, &f.Service, tt.doctorID, tt.at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if price != tt.want {
+				t.Errorf("ResolvePrice() = %d, want %d", price, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePriceRejectsInvalidRequests(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newServicePriceService(f)
+	missing := f.Doctor.ID + 100
+
+	tests := []struct {
+		name    string
+		request model.ServicePriceRequest
+		want    string
+	}{
+		{"no price", model.ServicePriceRequest{EffectiveFrom: "2099-01-01"}, "price must be greater than 0"},
+		{"past date", model.ServicePriceRequest{Price: 1, EffectiveFrom: "2020-01-01"}, "effective_from cannot be in the past"},
+		{"bad date", model.ServicePriceRequest{Price: 1, EffectiveFrom: "01/01/2099"}, "invalid effective_from date format"},
+		{"unknown doctor", model.ServicePriceRequest{Price: 1, EffectiveFrom: "2099-01-01", DoctorId: &missing}, "doctor not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.SchedulePrice(context.Background(), f.Service.ID, tt.request, 1)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("SchedulePrice() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelScheduledPriceKeepsPricesInEffect(t *testing.T) {
+	f := testutil.NewFixture(t)
+	s := newServicePriceService(f)
+	ctx := context.Background()
+
+	future, err := s.SchedulePrice(ctx, f.Service.ID, model.ServicePriceRequest{Price: 120000, EffectiveFrom: time.Now().AddDate(0, 0, 7).Format("2006-01-02")}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, err := s.GetPriceHistory(ctx, f.Service.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, price := range history {
+		err := s.CancelScheduledPrice(ctx, f.Service.ID, price.ID, 1)
+		if price.ID == future.ID && err != nil {
+			t.Errorf("CancelScheduledPrice() of the future price error = %v", err)
+		}
+		if price.ID != future.ID && err == nil {
+			t.Errorf("CancelScheduledPrice() of the price in effect since %v succeeded", price.EffectiveFrom)
+		}
+	}
+}
@@ -3,26 +3,34 @@ package services
 import (
 	"booking-klinik/model"
 	"booking-klinik/repository"
+	"booking-klinik/tracing"
 	"booking-klinik/utils"
+	"context"
 	"errors"
+	"time"
 )
 
 type UserService interface {
-	RegisterUser(user *model.User) (*model.User, error)
-	LoginUser(email, password string) (string, error)
-	UpdatePassword(userID uint, OldPassword, newPassword string) error
-	GetUserById(id uint) (*model.User, error)
+	RegisterUser(ctx context.Context, user *model.User) (*model.User, error)
+	LoginUser(ctx context.Context, email, password string) (string, error)
+	UpdatePassword(ctx context.Context, userID uint, OldPassword, newPassword string) error
+	GetUserById(ctx context.Context, id uint) (*model.User, error)
 }
 
 type UserServicesImpl struct {
 	UserRepository repository.UserRepository
+	JWTSecret      string
+	JWTExpiresIn   time.Duration
 }
 
-func (s *UserServicesImpl) RegisterUser(user *model.User) (*model.User, error) {
+func (s *UserServicesImpl) RegisterUser(ctx context.Context, user *model.User) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.RegisterUser")
+	defer span.End()
+
 	if user.Role == "" {
 		user.Role = "patient"
 	}
-	existingUser, _ := s.UserRepository.GetUserByEmail(user.Email)
+	existingUser, _ := s.UserRepository.GetUserByEmail(ctx, user.Email)
 	if existingUser != nil {
 		return nil, errors.New("user already exists")
 	}
@@ -41,15 +49,18 @@ func (s *UserServicesImpl) RegisterUser(user *model.User) (*model.User, error) {
 	}
 
 	user.Password = hashedPassword
-	if err := s.UserRepository.CreateUser(user); err != nil {
+	if err := s.UserRepository.CreateUser(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
-func (s *UserServicesImpl) LoginUser(email, password string) (string, error) {
-	user, err := s.UserRepository.GetUserByEmail(email)
+func (s *UserServicesImpl) LoginUser(ctx context.Context, email, password string) (string, error) {
+	ctx, span := tracing.Start(ctx, "UserService.LoginUser")
+	defer span.End()
+
+	user, err := s.UserRepository.GetUserByEmail(ctx, email)
 	if err != nil {
 		return "", err
 	}
@@ -58,7 +69,7 @@ func (s *UserServicesImpl) LoginUser(email, password string) (string, error) {
 		return "", errors.New("invalid credentials")
 	}
 
-	token, err := utils.GenerateJWT(*user)
+	token, err := utils.GenerateJWT(*user, s.JWTSecret, s.JWTExpiresIn)
 	if err != nil {
 		return "", err
 	}
@@ -66,8 +77,11 @@ func (s *UserServicesImpl) LoginUser(email, password string) (string, error) {
 	return token, nil
 }
 
-func (s *UserServicesImpl) UpdatePassword(userID uint, OldPassword, newPassword string) error {
-	user, err := s.UserRepository.GetUserById(userID)
+func (s *UserServicesImpl) UpdatePassword(ctx context.Context, userID uint, OldPassword, newPassword string) error {
+	ctx, span := tracing.Start(ctx, "UserService.UpdatePassword")
+	defer span.End()
+
+	user, err := s.UserRepository.GetUserById(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -89,15 +103,18 @@ func (s *UserServicesImpl) UpdatePassword(userID uint, OldPassword, newPassword
 		return err
 	}
 
-	if err := s.UserRepository.UpdatePassword(userID, hashedPassword); err != nil {
+	if err := s.UserRepository.UpdatePassword(ctx, userID, hashedPassword); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *UserServicesImpl) GetUserById(id uint) (*model.User, error) {
-	user, err := s.UserRepository.GetUserById(id)
+func (s *UserServicesImpl) GetUserById(ctx context.Context, id uint) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserById")
+	defer span.End()
+
+	user, err := s.UserRepository.GetUserById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"booking-klinik/model"
+	"booking-klinik/repository"
+	"booking-klinik/tracing"
+	"booking-klinik/utils"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+type VitalSignService interface {
+	RecordVitalSign(ctx context.Context, bookingID uint, request model.VitalSignRequest, userID uint, userRole string, clinicID uint) (*model.VitalSign, error)
+	GetLatestVitalSignByBookingId(ctx context.Context, bookingID uint) (*model.VitalSign, error)
+	GetVitalSignTrend(ctx context.Context, patientID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.VitalSign, *utils.Paginator, error)
+}
+
+type VitalSignServiceImpl struct {
+	VitalSignRepository repository.VitalSignRepository
+	BookingRepository   repository.BookingRepository
+	DoctorRepository    repository.DoctorRepository
+	BookingService      BookingService
+}
+
+// RecordVitalSign records vital signs for a booking. Nurses may record them
+// for any booking at their clinic, doctors only for their own bookings and
+// branch admins for bookings at their clinic.
+func (s *VitalSignServiceImpl) RecordVitalSign(ctx context.Context, bookingID uint, request model.VitalSignRequest, userID uint, userRole string, clinicID uint) (*model.VitalSign, error) {
+	ctx, span := tracing.Start(ctx, "VitalSignService.RecordVitalSign")
+	defer span.End()
+
+	var booking *model.Booking
+	var err error
+	switch userRole {
+	case "nurse":
+		if clinicID == 0 {
+			return nil, errors.New("nurses must be assigned to a clinic")
+		}
+		booking, err = s.BookingRepository.GetBookingById(ctx, bookingID)
+		if err != nil {
+			return nil, errors.New("booking not found")
+		}
+		if err := checkBookingClinic(booking, clinicID); err != nil {
+			return nil, err
+		}
+	case "doctor", "admin":
+		booking, err = s.BookingService.GetBookingById(ctx, bookingID, userID, userRole, clinicID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("only nurses, doctors and admins can record vital signs")
+	}
+
+	if booking.Status == "cancelled" {
+		return nil, errors.New("cannot record vital signs for a cancelled booking")
+	}
+
+	temperature, err := normalizeTemperature(request.Temperature, request.TemperatureUnit)
+	if err != nil {
+		return nil, err
+	}
+	weight, err := normalizeWeight(request.Weight, request.WeightUnit)
+	if err != nil {
+		return nil, err
+	}
+	height, err := normalizeHeight(request.Height, request.HeightUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Systolic < 50 || request.Systolic > 300 || request.Diastolic < 30 || request.Diastolic > 200 {
+		return nil, errors.New("blood pressure is out of measurable range")
+	}
+	if request.Diastolic >= request.Systolic {
+		return nil, errors.New("diastolic pressure must be lower than systolic pressure")
+	}
+	if temperature < 30 || temperature > 45 {
+		return nil, errors.New("temperature is out of measurable range")
+	}
+	if weight < 0.5 || weight > 500 {
+		return nil, errors.New("weight is out of measurable range")
+	}
+	if height < 30 || height > 272 {
+		return nil, errors.New("height is out of measurable range")
+	}
+	if request.Pulse < 20 || request.Pulse > 300 {
+		return nil, errors.New("pulse is out of measurable range")
+	}
+	if request.SpO2 < 50 || request.SpO2 > 100 {
+		return nil, errors.New("spo2 must be between 50 and 100")
+	}
+
+	vitalSign := model.VitalSign{
+		BookingId:   booking.ID,
+		UserId:      booking.UserId,
+		Systolic:    request.Systolic,
+		Diastolic:   request.Diastolic,
+		Temperature: roundTo(temperature, 1),
+		WeightKg:    roundTo(weight, 1),
+		HeightCm:    roundTo(height, 1),
+		Pulse:       request.Pulse,
+		SpO2:        request.SpO2,
+		BMI:         roundTo(weight/math.Pow(height/100, 2), 1),
+		RecordedAt:  time.Now(),
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+	vitalSign.Flags = strings.Join(vitalSignFlags(vitalSign), ",")
+
+	if err := s.VitalSignRepository.CreateVitalSign(ctx, &vitalSign); err != nil {
+		return nil, err
+	}
+
+	return &vitalSign, nil
+}
+
+func (s *VitalSignServiceImpl) GetLatestVitalSignByBookingId(ctx context.Context, bookingID uint) (*model.VitalSign, error) {
+	ctx, span := tracing.Start(ctx, "VitalSignService.GetLatestVitalSignByBookingId")
+	defer span.End()
+
+	vitalSign, err := s.VitalSignRepository.GetLatestVitalSignByBookingId(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return vitalSign, nil
+}
+
+// GetVitalSignTrend lists a patient's vital signs. Patients only see their
+// own, doctors those of patients they have a booking with and branch admins
+// those of patients booked at their clinic.
+func (s *VitalSignServiceImpl) GetVitalSignTrend(ctx context.Context, patientID uint, userID uint, userRole string, clinicID uint, limit, offset int) ([]model.VitalSign, *utils.Paginator, error) {
+	ctx, span := tracing.Start(ctx, "VitalSignService.GetVitalSignTrend")
+	defer span.End()
+
+	switch userRole {
+	case "patient":
+		if patientID != userID {
+			return nil, nil, errors.New("you can only access your own vital signs")
+		}
+	case "doctor":
+		doctorID, err := s.DoctorRepository.GetDoctorIDbyUserID(ctx, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		hasBooking, err := s.BookingRepository.HasPatientBooking(ctx, patientID, doctorID, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !hasBooking {
+			return nil, nil, errors.New("you can only access your patients vital signs")
+		}
+	case "admin":
+		if clinicID != 0 {
+			hasBooking, err := s.BookingRepository.HasPatientBooking(ctx, patientID, 0, clinicID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !hasBooking {
+				return nil, nil, ErrOtherClinic
+			}
+		}
+	default:
+		return nil, nil, errors.New("invalid user role")
+	}
+
+	vitalSigns, totalRows, err := s.VitalSignRepository.GetVitalSignsByUserId(ctx, patientID, limit, offset)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pagination := &utils.Paginator{Limit: limit, Offset: offset, Page: (offset / limit) + 1, TotalRows: totalRows}
+
+	pagination.TotalPages = (totalRows + int64(pagination.Limit) - 1) / int64(pagination.Limit)
+	return vitalSigns, pagination, nil
+}
+
+// vitalSignFlags returns the names of the readings that fall outside the
+// normal adult reference range.
+func vitalSignFlags(v model.VitalSign) []string {
+	var flags []string
+
+	if v.Systolic >= 140 || v.Diastolic >= 90 {
+		flags = append(flags, "high_blood_pressure")
+	} else if v.Systolic < 90 || v.Diastolic < 60 {
+		flags = append(flags, "low_blood_pressure")
+	}
+
+	if v.Temperature > 37.5 {
+		flags = append(flags, "fever")
+	} else if v.Temperature < 36.0 {
+		flags = append(flags, "low_temperature")
+	}
+
+	if v.Pulse > 100 {
+		flags = append(flags, "tachycardia")
+	} else if v.Pulse < 60 {
+		flags = append(flags, "bradycardia")
+	}
+
+	if v.SpO2 < 95 {
+		flags = append(flags, "low_spo2")
+	}
+
+	switch {
+	case v.BMI < 18.5:
+		flags = append(flags, "underweight")
+	case v.BMI >= 30:
+		flags = append(flags, "obese")
+	case v.BMI >= 25:
+		flags = append(flags, "overweight")
+	}
+
+	return flags
+}
+
+func normalizeTemperature(value float64, unit string) (float64, error) {
+	switch strings.ToUpper(unit) {
+	case "", "C":
+		return value, nil
+	case "F":
+		return (value - 32) * 5 / 9, nil
+	}
+	return 0, errors.New("temperature unit must be C or F")
+}
+
+func normalizeWeight(value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "kg":
+		return value, nil
+	case "lb":
+		return value * 0.45359237, nil
+	}
+	return 0, errors.New("weight unit must be kg or lb")
+}
+
+func normalizeHeight(value float64, unit string) (float64, error) {
+	switch strings.ToLower(unit) {
+	case "", "cm":
+		return value, nil
+	case "m":
+		return value * 100, nil
+	case "in":
+		return value * 2.54, nil
+	}
+	return 0, errors.New("height unit must be cm, m or in")
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStorage stores uploaded files under an opaque key. Implementations
+// must treat keys as slash separated paths relative to their own root.
+type BlobStorage interface {
+	Put(key string, r io.Reader) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin gives every query run within a trace a span, a child of the
+// span in the context of the query. Queries outside any trace, such as the
+// readiness probe and the queue gauge read on each metrics scrape, are not
+// traced, so they do not each start a trace of their own. The span carries the SQL with placeholders, never the
+// bound values, which can hold personal data and password hashes. A missing
+// record is not an error, as repositories use it to tell "not found".
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", startQuery("create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", endQuery),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", startQuery("select")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", endQuery),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", startQuery("update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", endQuery),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startQuery("delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endQuery),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", startQuery("row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", endQuery),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startQuery("raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endQuery),
+	)
+}
+
+// startQuery keeps the span on the statement rather than in its context: a
+// chain such as Count then Find runs both queries on one statement, and they
+// are siblings, not parent and child.
+func startQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !trace.SpanContextFromContext(db.Statement.Context).IsValid() {
+			return
+		}
+		name := operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := tracer.Start(db.Statement.Context, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemKey.String(db.Dialector.Name())),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endQuery(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(db.Statement.Table))
+	}
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		fail(span, db.Error)
+	}
+}
+
+// fail marks the span as failed with err.
+func fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -1,7 +1,8 @@
 package utils
 
 import (
-	"log"
+	"crypto/rand"
+	"encoding/base64"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -9,7 +10,6 @@ import (
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		log.Println("Error hashing password:", err)
 		return "", err
 	}
 	return string(hashedPassword), nil
@@ -19,3 +19,12 @@ func CheckPassword(password, hashedPassword string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// RandomPassword returns a random password for accounts created without one.
+func RandomPassword() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -3,33 +3,27 @@ package utils
 import (
 	"booking-klinik/model"
 	"errors"
-	"log"
-	"os"
-	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateJWT(user model.User) (string, error) {
-	var jwtSecret = os.Getenv("JWT_SECRET_KEY")
-	var jwtExpiresIn = os.Getenv("JWT_EXPIRES_IN")
-
-	expires, err := strconv.Atoi(jwtExpiresIn)
-	if err != nil {
-		log.Panic("Error parsing JWT_EXPIRES_IN")
-	}
+func GenerateJWT(user model.User, secret string, expiresIn time.Duration) (string, error) {
 	claims := model.Claims{
 		Email:  user.Email,
 		UserID: user.ID,
 		Role:   user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(expires))),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
 		},
 	}
 
+	if user.ClinicId != nil {
+		claims.ClinicID = *user.ClinicId
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(jwtSecret))
+	tokenString, err := token.SignedString([]byte(secret))
 	if err != nil {
 		return "", err
 	}
@@ -37,11 +31,9 @@ func GenerateJWT(user model.User) (string, error) {
 	return tokenString, nil
 }
 
-func VerifyJWT(tokenString string) (*model.Claims, error) {
-	var jwtSecret = os.Getenv("JWT_SECRET_KEY")
-
+func VerifyJWT(tokenString string, secret string) (*model.Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &model.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
+		return []byte(secret), nil
 	})
 	if err != nil {
 		return nil, err
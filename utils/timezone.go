@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"time"
+)
+
+// DefaultTimezone is used for dates that do not belong to a clinic, such as
+// price and promo validity, and as the timezone of new clinics.
+const DefaultTimezone = "Asia/Jakarta"
+
+// Instants are stored in UTC. Calendar dates without a time of day (booking
+// and schedule dates, promo validity) are stored as midnight UTC of that day,
+// so they compare and format the same whatever the clinic's timezone.
+
+func DefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// ParseDate parses a YYYY-MM-DD calendar date.
+func ParseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// DateIn returns the calendar date of t as seen in loc.
+func DateIn(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Today is the current calendar date in the default timezone.
+func Today() time.Time {
+	return DateIn(time.Now(), DefaultLocation())
+}
+
+// ParseDateTime reads an instant given either as an RFC 3339 timestamp with
+// offset, or as a YYYY-MM-DD date and HH:MM wall-clock time in loc.
+func ParseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, clock); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}